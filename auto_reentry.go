@@ -0,0 +1,71 @@
+package blackholedex
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+// haltRecord remembers where the circuit breaker halted the strategy and when the last error occurred
+type haltRecord struct {
+	from types.StrategyPhase
+	at   time.Time
+}
+
+// fundsIdle reports whether the halt left the funds outside any staked position
+// A halt while monitoring, or before a rebalance finished withdrawing, leaves liquidity
+// in the pool; those halts are not re-entered automatically
+func (h haltRecord) fundsIdle(state *types.StrategyState) bool {
+	switch h.from {
+	case types.Initializing, types.WaitingForStability:
+		return true
+	case types.RebalancingRequired:
+		return state.CurrentStep == types.Step_Rebalance_WithdrawCompleted
+	default:
+		return false
+	}
+}
+
+// tryAutoReentry moves a halted strategy to WaitingForStability once the halting condition has cleared
+// The errors must have subsided for a full CircuitBreakerWindow and the pool must be readable again;
+// a failed read counts as a new error and restarts the wait. Price is then checked by the stability wait
+// Returns true if the strategy resumed
+func (b *Blackhole) tryAutoReentry(
+	config *types.StrategyConfig,
+	state *types.StrategyState,
+	halt *haltRecord,
+	circuitBreaker *types.CircuitBreaker,
+	stabilityWindow *types.StabilityWindow,
+	reportChan chan<- string,
+	now time.Time,
+) bool {
+	if now.Sub(halt.at) < config.CircuitBreakerWindow {
+		return false
+	}
+
+	if _, err := b.GetAMMState(); err != nil {
+		halt.at = now
+		b.status.recordError(err)
+		log.Printf("Auto re-entry postponed, pool still unreadable: %v", err)
+		return false
+	}
+
+	circuitBreaker.Reset()
+	stabilityWindow.Reset()
+	if state.CurrentStep > types.Step_Init_StakeCompleted {
+		// The rebalance exit is complete; a mint/stake checkpoint is kept for initialPositionEntry
+		state.CurrentStep = types.Step_None
+	}
+	state.CurrentState = types.WaitingForStability
+	log.Printf("Auto re-entry: halted in %s, errors subsided since %s, waiting for stability", halt.from, halt.at.Format(time.RFC3339))
+
+	b.sendReport(reportChan, types.StrategyReport{
+		Timestamp: now,
+		EventType: "auto_reentry",
+		Message:   fmt.Sprintf("No errors for %v after halt in %s, waiting for stability before re-entering", config.CircuitBreakerWindow, halt.from),
+		Phase:     &state.CurrentState,
+	})
+	return true
+}
@@ -0,0 +1,68 @@
+package blackholedex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTryAutoReentry(t *testing.T) {
+	config := types.DefaultStrategyConfig()
+	config.AutoReentry = true
+	haltedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	newHalted := func() (*types.StrategyState, *haltRecord, *types.CircuitBreaker, *types.StabilityWindow) {
+		state := &types.StrategyState{CurrentState: types.Halted, CurrentStep: types.Step_Rebalance_WithdrawCompleted}
+		halt := &haltRecord{from: types.RebalancingRequired, at: haltedAt}
+		circuitBreaker := &types.CircuitBreaker{ErrorWindow: config.CircuitBreakerWindow, ErrorThreshold: 3, CriticalErrorOccurred: true}
+		stabilityWindow := &types.StabilityWindow{LastPrice: big.NewInt(1), StableCount: 2}
+		return state, halt, circuitBreaker, stabilityWindow
+	}
+
+	t.Run("ReentersAfterErrorsSubside", func(t *testing.T) {
+		b := newTestBlackhole(map[string]ContractClient{wavaxUsdcPair: newMockPool(big.NewInt(1), 0)}, &mockTxListener{})
+		state, halt, circuitBreaker, stabilityWindow := newHalted()
+		reportChan := make(chan string, 1)
+
+		assert.True(t, halt.fundsIdle(state))
+		resumed := b.tryAutoReentry(config, state, halt, circuitBreaker, stabilityWindow, reportChan, haltedAt.Add(config.CircuitBreakerWindow))
+		assert.True(t, resumed)
+		assert.Equal(t, types.WaitingForStability, state.CurrentState)
+		assert.Equal(t, types.Step_None, state.CurrentStep)
+		assert.False(t, circuitBreaker.CriticalErrorOccurred)
+		assert.Nil(t, stabilityWindow.LastPrice)
+		assert.Contains(t, <-reportChan, `"event_type":"auto_reentry"`)
+	})
+
+	t.Run("WaitsForWindow", func(t *testing.T) {
+		b := newTestBlackhole(map[string]ContractClient{wavaxUsdcPair: newMockPool(big.NewInt(1), 0)}, &mockTxListener{})
+		state, halt, circuitBreaker, stabilityWindow := newHalted()
+
+		assert.False(t, b.tryAutoReentry(config, state, halt, circuitBreaker, stabilityWindow, nil, haltedAt.Add(time.Minute)))
+		assert.Equal(t, types.Halted, state.CurrentState)
+	})
+
+	t.Run("PoolErrorRestartsWait", func(t *testing.T) {
+		pool := newMockPool(big.NewInt(1), 0)
+		pool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			return nil, errors.New("rpc unavailable")
+		}
+		b := newTestBlackhole(map[string]ContractClient{wavaxUsdcPair: pool}, &mockTxListener{})
+		state, halt, circuitBreaker, stabilityWindow := newHalted()
+		now := haltedAt.Add(config.CircuitBreakerWindow)
+
+		assert.False(t, b.tryAutoReentry(config, state, halt, circuitBreaker, stabilityWindow, nil, now))
+		assert.Equal(t, types.Halted, state.CurrentState)
+		assert.Equal(t, now, halt.at)
+	})
+
+	t.Run("PositionStillOpen", func(t *testing.T) {
+		state := &types.StrategyState{CurrentState: types.Halted, CurrentStep: types.Step_Rebalance_UnstakeCompleted}
+		assert.False(t, (haltRecord{from: types.RebalancingRequired}).fundsIdle(state))
+		assert.False(t, (haltRecord{from: types.ActiveMonitoring}).fundsIdle(state))
+	})
+}
@@ -3,11 +3,17 @@ package blackholedex
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/ChoSanghyuk/blackholedex/pkg/contractclient"
+	"github.com/ChoSanghyuk/blackholedex/pkg/metrics"
+	"github.com/ChoSanghyuk/blackholedex/pkg/txlistener"
 	"github.com/ChoSanghyuk/blackholedex/pkg/types"
 	"github.com/ChoSanghyuk/blackholedex/pkg/util"
 
@@ -30,8 +36,16 @@ const (
 	nonfungiblePositionManager = "nonfungiblePositionManager"
 	gauge                      = "gauge"
 	farmingCenter              = "farmingCenter"
+	votingEscrow               = "votingEscrow"
+	algebraFactory             = "algebraFactory"
 )
 
+// requiredContracts are the contracts the strategy and position operations cannot run without
+// votingEscrow and algebraFactory are optional; only the operations that use them fail without them
+var requiredContracts = []string{
+	routerv2, usdc, wavax, black, wavaxUsdcPair, deployer, nonfungiblePositionManager, gauge, farmingCenter,
+}
+
 // Blackhole manages interactions with Blackhole DEX contracts
 type Blackhole struct {
 	poolType   types.PoolType
@@ -40,13 +54,75 @@ type Blackhole struct {
 	client     *ethclient.Client
 	tl         TxListener
 	registry   *ContractRegistry   // Manages contract client lookups
+	tokens     *TokenRegistry      // Symbols and decimals for formatting amounts
 	recorder   TransactionRecorder // Records all transaction results
+	status     strategyStatus      // Observable strategy state for external supervisors
+	codeReader CodeReader          // Reads deployed bytecode for upgrade detection
+	balances   BalanceReader       // Reads the native AVAX balance
+	logs       LogReader           // Reads Swap events for fee APR estimation
+	txReader   TransactionReader   // Fetches transactions by hash (see ReplayTransaction)
+	txSender   TransactionSender   // Broadcasts replacement transactions (see SpeedUpTransaction)
+	gasPrices  GasPriceReader      // Prices the gas of the native reserve check
+	gasLimits  GasEstimator        // Estimates rebalance steps (see EstimateRebalanceGas)
+	codeHashes map[string]common.Hash
+	nonces     *contractclient.NonceManager // Shared nonce sequence for myAddr
+	dryRun     bool                         // Simulate Swap/Mint/Stake/Unstake via eth_call instead of sending
+	deployers  []common.Address             // Custom pool deployers checked by ListPoolsForPair
+	swapMu     sync.Mutex
+	swapCache  map[common.Address]*swapVolumeCache // Scanned Swap volume per pool (see EstimateFeeAPR)
+	tickPeriod time.Duration                       // Overrides config.MonitoringInterval; only set by tests
+	capitalCap *big.Int                            // Max value deployed across positions (see WithCapitalCap)
+	mintMu     sync.Mutex                          // Serializes mints while capitalCap is set
+	gasReserve *big.Int                            // WAVAX kept out of MintMax (see WithGasReserve)
+	runCtx     atomic.Pointer[context.Context]     // Context of the running strategy (see rpcContext)
+	approvals  approvalPolicy                      // How ensureApproval changes allowances
+	avaxFloor  *big.Int                            // Native AVAX left after gas (see WithMinNativeReserve)
+}
+
+// Option is a functional option for configuring Blackhole
+type Option func(*Blackhole)
+
+// WithDryRun makes Swap, Mint, Stake and Unstake simulate their transactions with eth_call
+// Approvals are skipped, so simulations that depend on a missing allowance will revert
+// Other write operations (Withdraw, CollectFees, RemoveLiquidity, ...) return ErrDryRunUnsupported
+func WithDryRun() Option {
+	return func(b *Blackhole) {
+		b.dryRun = true
+	}
+}
+
+// WithStrictApproval makes approvals reset a non-zero but insufficient allowance to zero first
+// Needed for ERC20s (USDT-style) that revert when a non-zero allowance is changed to another non-zero value
+func WithStrictApproval() Option {
+	return func(b *Blackhole) {
+		b.approvals.strict = true
+	}
+}
+
+// WithInfiniteApproval makes approvals grant the maximum uint256 allowance instead of the exact amount
+// Saves an approval per operation for frequently used tokens, at the cost of leaving the spender
+// able to move the whole balance. Off by default
+func WithInfiniteApproval() Option {
+	return func(b *Blackhole) {
+		b.approvals.infinite = true
+	}
+}
+
+// WithPoolDeployers sets the custom pool deployers (e.g. CL200 and CL1) searched by ListPoolsForPair
+// Defaults to the deployer of the configured pool type
+func WithPoolDeployers(deployers ...common.Address) Option {
+	return func(b *Blackhole) {
+		b.deployers = deployers
+	}
 }
 
 type ContractClientConfig struct {
 	Name    string
 	Address string
 	Abipath string
+	// Symbol and Decimals register a token in the TokenRegistry for human-readable amounts; empty Symbol skips it
+	Symbol   string
+	Decimals int
 }
 
 type BlackholeConfig struct {
@@ -71,7 +147,7 @@ func NewBlackholeConfig(url string, pk string, defaultGasLimit *big.Int, pool ty
 	}
 }
 
-func NewBlackhole(client *ethclient.Client, conf *BlackholeConfig, tl TxListener, recorder TransactionRecorder) (*Blackhole, error) {
+func NewBlackhole(client *ethclient.Client, conf *BlackholeConfig, tl TxListener, recorder TransactionRecorder, opts ...Option) (*Blackhole, error) {
 
 	privateKey, err := crypto.HexToECDSA(conf.pk)
 	if err != nil {
@@ -84,8 +160,15 @@ func NewBlackhole(client *ethclient.Client, conf *BlackholeConfig, tl TxListener
 	}
 	address := crypto.PubkeyToAddress(*publicKeyECDSA)
 
+	// All clients sign for the same account, so they share one nonce sequence
+	nonceManager := contractclient.NewNonceManager(client)
+
 	ccm := make(map[string]ContractClient)
+	tokens := make(map[common.Address]TokenInfo)
 	for _, c := range conf.configs {
+		if c.Symbol != "" {
+			tokens[common.HexToAddress(c.Address)] = TokenInfo{Symbol: c.Symbol, Decimals: c.Decimals}
+		}
 		var ABI *abi.ABI
 		if c.Abipath == "excluded" {
 			ABI = nil
@@ -95,19 +178,60 @@ func NewBlackhole(client *ethclient.Client, conf *BlackholeConfig, tl TxListener
 				return nil, fmt.Errorf("Failed to load ABI: %s. %v", c.Abipath, err)
 			}
 		}
-		cc := contractclient.NewContractClient(client, common.HexToAddress(c.Address), ABI, contractclient.WithDefaultGasLimit(conf.defaultGasLimit))
+		cc := contractclient.NewContractClient(client, common.HexToAddress(c.Address), ABI,
+			contractclient.WithDefaultGasLimit(conf.defaultGasLimit),
+			contractclient.WithNonceManager(nonceManager),
+		)
 		ccm[c.Name] = cc
 	}
 
-	return &Blackhole{
+	// Catch misconfiguration at startup instead of at the first call that needs the contract
+	registry := NewContractRegistry(ccm)
+	if missing := registry.Missing(requiredContracts...); len(missing) > 0 {
+		return nil, fmt.Errorf("missing required contracts: %s", strings.Join(missing, ", "))
+	}
+
+	b := &Blackhole{
 		poolType:   conf.poolType,
 		privateKey: privateKey,
 		myAddr:     address,
 		client:     client,
 		tl:         tl,
-		registry:   NewContractRegistry(ccm),
+		codeReader: client,
+		balances:   client,
+		logs:       client,
+		txReader:   client,
+		txSender:   client,
+		gasPrices:  client,
+		gasLimits:  client,
+		avaxFloor:  new(big.Int).Set(defaultNativeReserve),
+		registry:   registry,
+		tokens:     NewTokenRegistry(tokens),
 		recorder:   recorder,
-	}, nil
+		nonces:     nonceManager,
+	}
+
+	for _, opt := range opts {
+		opt(b)
+	}
+	return b, nil
+}
+
+// ResetNonce discards the locally tracked nonce so the next transaction resyncs from the node
+// Use after transactions were sent from the same account outside this Blackhole
+func (b *Blackhole) ResetNonce() {
+	if b.nonces != nil {
+		b.nonces.ResetNonce(b.myAddr)
+	}
+}
+
+// rpcContext returns the context contract calls are made with
+// While RunAutoPositionStrategy runs this is its context, so cancelling the strategy aborts in-flight RPCs
+func (b *Blackhole) rpcContext() context.Context {
+	if ctx := b.runCtx.Load(); ctx != nil {
+		return *ctx
+	}
+	return context.Background()
 }
 
 // Phase 7: Main Strategy Integration (T050-T070)
@@ -117,6 +241,8 @@ func NewBlackhole(client *ethclient.Client, conf *BlackholeConfig, tl TxListener
 // - US2: Continuous price monitoring
 // - US3: Automated position rebalancing when out-of-range
 // - US4: Price stability detection before re-entry
+// Reports other than errors and shutdowns are dropped while reportChan is full (see DroppedReports),
+// so give it a buffer of 100 or more
 func (b *Blackhole) RunAutoPositionStrategy(
 	ctx context.Context,
 	reportChan chan<- string,
@@ -126,6 +252,8 @@ func (b *Blackhole) RunAutoPositionStrategy(
 	if err := config.Validate(); err != nil {
 		return fmt.Errorf("invalid strategy configuration: %w", err)
 	}
+	b.runCtx.Store(&ctx)
+	defer b.runCtx.Store(nil)
 
 	// T052: Initialize StrategyState
 	state := &types.StrategyState{
@@ -139,6 +267,7 @@ func (b *Blackhole) RunAutoPositionStrategy(
 		CumulativeGas:     big.NewInt(0),
 		CumulativeRewards: big.NewInt(0),
 		TotalSwapFees:     big.NewInt(0),
+		GasByOperation:    make(map[string]*big.Int),
 		ErrorCount:        0,
 		LastErrorTime:     time.Time{},
 		StartTime:         time.Now(),
@@ -155,10 +284,11 @@ func (b *Blackhole) RunAutoPositionStrategy(
 
 	// T054: Initialize StabilityWindow
 	stabilityWindow := &types.StabilityWindow{
-		Threshold:         config.StabilityThreshold,
-		RequiredIntervals: config.StabilityIntervals,
-		LastPrice:         nil,
-		StableCount:       0,
+		Threshold:          config.StabilityThreshold,
+		RequiredIntervals:  config.StabilityIntervals,
+		MaxCumulativeDrift: config.MaxCumulativeDrift,
+		LastPrice:          nil,
+		StableCount:        0,
 	}
 
 	tokenIDs, err := b.GetUserPositions()
@@ -201,7 +331,7 @@ func (b *Blackhole) RunAutoPositionStrategy(
 		state.TickUpper = position.TickUpper
 		state.PositionCreatedAt = time.Now() // We don't know the exact creation time
 
-		sendReport(reportChan, types.StrategyReport{
+		b.sendReport(reportChan, types.StrategyReport{
 			Timestamp: time.Now(),
 			EventType: "position_loaded",
 			Message: fmt.Sprintf("Loaded existing position: NFT ID %s, TickLower=%d, TickUpper=%d, Liquidity=%s",
@@ -221,39 +351,76 @@ func (b *Blackhole) RunAutoPositionStrategy(
 
 		log.Printf("Loaded existing position: NFT ID %s", nftTokenID.String())
 	}
+	b.status.setConfig(config)
+	b.status.publish(state)
 
 	// T055: Send strategy_start report
-	sendReport(reportChan, types.StrategyReport{
+	b.sendReport(reportChan, types.StrategyReport{
 		Timestamp: time.Now(),
 		EventType: "strategy_start",
 		Message:   "RunStrategy1 starting - automated liquidity repositioning",
 		Phase:     &state.CurrentState,
 	}) // State was just initialized, report it
 
-	// Record initial asset snapshot at strategy start
-
 	// T058: Implement main loop with ticker
-	ticker := time.NewTicker(config.MonitoringInterval)
+	interval := config.MonitoringInterval
+	if b.tickPeriod > 0 {
+		interval = b.tickPeriod
+	}
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	// Add 3-hour snapshot recording ticker
-	snapshotTicker := time.NewTicker(2 * time.Hour)
-	defer snapshotTicker.Stop()
-	b.RecordCurrentAssetSnapshot(state.CurrentState)
+	// Record initial asset snapshot at strategy start
+	b.RecordCurrentAssetSnapshot(state)
+	lastSnapshot := time.Now()
+
+	// Optional contract upgrade detection (disabled when interval is 0)
+	var codeHashTick <-chan time.Time
+	if config.CodeHashCheckInterval > 0 {
+		if err := b.RecordCodeHashes(); err != nil {
+			return fmt.Errorf("failed to record contract code hashes: %w", err)
+		}
+		codeHashTicker := time.NewTicker(config.CodeHashCheckInterval)
+		defer codeHashTicker.Stop()
+		codeHashTick = codeHashTicker.C
+	}
 
 	// Nonce for unstaking (should be queried from contract in production)
 	nonce := b.poolType.PoolNonce()
+	// Where and when the circuit breaker last halted the strategy (see AutoReentry)
+	var halt haltRecord
+	// Phase to return to after Resume
+	var pause pauseState
 	// T058-T070: Main strategy loop
 	for {
 		select {
 		case <-ctx.Done():
 			// T067: Graceful shutdown
+			phase := state.CurrentState
+			if phase == types.Paused {
+				phase = pause.resumePhase
+			}
+			b.shutdown(ctx, config, state, phase, nonce, reportChan)
 			return ctx.Err()
 
-		case <-snapshotTicker.C:
-			// Record asset snapshot every 3 hours
-			b.RecordCurrentAssetSnapshot(state.CurrentState)
+		case <-b.status.pauseSignals():
+			b.applyPauseRequest(state, &pause, reportChan)
+		case <-codeHashTick:
+			b.checkContractUpgrades(state, reportChan)
 		case <-ticker.C:
+			if state.CurrentState == types.Paused {
+				// Hold the position as-is: no top-ups, snapshots or phase work until Resume
+				continue
+			}
+
+			// Keep native AVAX funded for gas before sending any transactions
+			if err := b.ensureGasFunds(config, state, reportChan); err != nil {
+				log.Printf("Gas top-up failed: %v", err)
+			}
+
+			// Record asset snapshot once SnapshotInterval has elapsed
+			lastSnapshot = b.recordSnapshotIfDue(state, config.SnapshotInterval, lastSnapshot, time.Now())
+
 			// Handle different phases
 			switch state.CurrentState {
 			case types.Initializing:
@@ -262,10 +429,11 @@ func (b *Blackhole) RunAutoPositionStrategy(
 				mintResult, err := b.initialPositionEntry(config, state, reportChan)
 				if err != nil {
 					// T064, T065: Error handling
-					critical := util.IsCriticalError(err)
+					critical := isCriticalError(err)
 					shouldHalt := circuitBreaker.RecordError(err, critical)
+					b.status.recordError(err)
 
-					sendReport(reportChan, types.StrategyReport{
+					b.sendReport(reportChan, types.StrategyReport{
 						Timestamp: time.Now(),
 						EventType: "error",
 						Message:   fmt.Sprintf("Position re-entry failed at step %s", state.CurrentStep.String()),
@@ -274,13 +442,19 @@ func (b *Blackhole) RunAutoPositionStrategy(
 					})
 
 					if shouldHalt {
+						metrics.IncCircuitBreakerTrip()
+						halt = haltRecord{from: state.CurrentState, at: time.Now()}
 						state.CurrentState = types.Halted
-						state.CurrentStep = types.Step_None
+						if !config.AutoReentry {
+							// Auto re-entry keeps the checkpoint to resume from
+							state.CurrentStep = types.Step_None
+						}
 					} else {
 						// Keep CurrentStep as-is to retry from last successful checkpoint
 						// Stay in Initializing phase to retry
 						log.Printf("[Retry] Will retry Initializing phase from step: %s", state.CurrentStep.String())
 					}
+					b.status.publish(state)
 					continue
 				}
 
@@ -288,9 +462,14 @@ func (b *Blackhole) RunAutoPositionStrategy(
 				state.CurrentState = types.ActiveMonitoring
 				state.CurrentStep = types.Step_None // Reset step for new phase
 				log.Printf("Position re-entry successful: NFT ID %s", mintResult.NFTTokenID.String())
+				if position, err := b.GetPositionDetails(mintResult.NFTTokenID); err != nil {
+					log.Printf("Warning: failed to read liquidity of NFT %s: %v", mintResult.NFTTokenID.String(), err)
+				} else {
+					metrics.SetPositionLiquidity(position.Liquidity)
+				}
 
 				// Record snapshot after completing Initializing phase
-				b.RecordCurrentAssetSnapshot(state.CurrentState)
+				b.RecordCurrentAssetSnapshot(state)
 
 				// T068: Update cumulative tracking (already done in initialPositionEntry)
 				// T069: Phase transition already done
@@ -298,13 +477,14 @@ func (b *Blackhole) RunAutoPositionStrategy(
 
 			case types.ActiveMonitoring:
 				// T059: Monitor pool price
-				outOfRange, err := b.monitoringLoop(ctx, state, reportChan)
+				outOfRange, err := b.monitoringLoop(ctx, state, config, reportChan)
 				if err != nil {
 					// T064, T065: Error handling
-					critical := util.IsCriticalError(err)
+					critical := isCriticalError(err)
 					shouldHalt := circuitBreaker.RecordError(err, critical)
+					b.status.recordError(err)
 
-					sendReport(reportChan, types.StrategyReport{
+					b.sendReport(reportChan, types.StrategyReport{
 						Timestamp: time.Now(),
 						EventType: "error",
 						Message:   "Monitoring loop error",
@@ -313,8 +493,11 @@ func (b *Blackhole) RunAutoPositionStrategy(
 					})
 
 					if shouldHalt {
+						metrics.IncCircuitBreakerTrip()
+						halt = haltRecord{from: state.CurrentState, at: time.Now()}
 						state.CurrentState = types.Halted
 					}
+					b.status.publish(state)
 					continue
 				}
 
@@ -324,15 +507,32 @@ func (b *Blackhole) RunAutoPositionStrategy(
 				}
 
 			case types.RebalancingRequired:
+				// A rebalance whose gas the pending rewards do not cover loses money; keep the position and check again
+				if state.CurrentStep == types.Step_None && state.NFTTokenID != nil {
+					if worthwhile, reason := b.rebalanceWorthwhile(config, state.NFTTokenID, nonce); !worthwhile {
+						state.CurrentState = types.ActiveMonitoring
+						b.sendReport(reportChan, types.StrategyReport{
+							Timestamp:  time.Now(),
+							EventType:  "rebalance_skipped",
+							Message:    fmt.Sprintf("Rebalance skipped: %s", reason),
+							Phase:      &state.CurrentState,
+							NFTTokenID: state.NFTTokenID,
+						})
+						b.status.publish(state)
+						continue
+					}
+				}
+
 				// T060: Execute rebalancing workflow
 				// The executeRebalancing function will resume from state.CurrentStep if retrying
 				_, err := b.executeRebalancing(config, state, nonce, reportChan)
 				if err != nil {
 					// T064, T065: Error handling
-					critical := util.IsCriticalError(err)
+					critical := isCriticalError(err)
 					shouldHalt := circuitBreaker.RecordError(err, critical)
+					b.status.recordError(err)
 
-					sendReport(reportChan, types.StrategyReport{
+					b.sendReport(reportChan, types.StrategyReport{
 						Timestamp: time.Now(),
 						EventType: "error",
 						Message:   fmt.Sprintf("Rebalancing failed at step %s", state.CurrentStep.String()),
@@ -341,34 +541,43 @@ func (b *Blackhole) RunAutoPositionStrategy(
 					})
 
 					if shouldHalt {
+						metrics.IncCircuitBreakerTrip()
+						halt = haltRecord{from: state.CurrentState, at: time.Now()}
 						state.CurrentState = types.Halted
-						state.CurrentStep = types.Step_None
+						if !config.AutoReentry {
+							// Auto re-entry keeps the checkpoint to resume from
+							state.CurrentStep = types.Step_None
+						}
 					} else {
 						// Keep CurrentStep as-is to retry from last successful checkpoint
 						// Stay in RebalancingRequired phase to retry
 						log.Printf("[Retry] Will retry RebalancingRequired phase from step: %s", state.CurrentStep.String())
 					}
+					b.status.publish(state)
 					continue
 				}
 
 				// Rebalancing successful, transition to WaitingForStability
+				metrics.IncRebalance()
+				metrics.SetPositionLiquidity(nil)
 				state.CurrentState = types.WaitingForStability
 				state.CurrentStep = types.Step_None // Reset step for new phase
 				stabilityWindow.Reset()             // Start fresh stability tracking
 				log.Printf("Rebalancing completed, waiting for price stability")
 
 				// Record snapshot after completing RebalancingRequired phase
-				b.RecordCurrentAssetSnapshot(state.CurrentState)
+				b.RecordCurrentAssetSnapshot(state)
 
 			case types.WaitingForStability:
 				// T061: Wait for price stability
-				isStable, err := b.stabilityLoop(ctx, state, stabilityWindow, reportChan)
+				isStable, err := b.stabilityLoop(ctx, config, state, stabilityWindow, reportChan)
 				if err != nil {
 					// T064, T065: Error handling
-					critical := util.IsCriticalError(err)
+					critical := isCriticalError(err)
 					shouldHalt := circuitBreaker.RecordError(err, critical)
+					b.status.recordError(err)
 
-					sendReport(reportChan, types.StrategyReport{
+					b.sendReport(reportChan, types.StrategyReport{
 						Timestamp: time.Now(),
 						EventType: "error",
 						Message:   "Stability check error",
@@ -377,8 +586,11 @@ func (b *Blackhole) RunAutoPositionStrategy(
 					})
 
 					if shouldHalt {
+						metrics.IncCircuitBreakerTrip()
+						halt = haltRecord{from: state.CurrentState, at: time.Now()}
 						state.CurrentState = types.Halted
 					}
+					b.status.publish(state)
 					continue
 				}
 
@@ -386,23 +598,29 @@ func (b *Blackhole) RunAutoPositionStrategy(
 				if isStable {
 					log.Printf("Price stabilized, ready to re-enter position")
 					state.CurrentState = types.Initializing
-					continue
 				}
 			case types.Halted:
+				if config.AutoReentry && halt.fundsIdle(state) {
+					// Stay halted until the errors subside, then re-enter without a restart
+					b.tryAutoReentry(config, state, &halt, circuitBreaker, stabilityWindow, reportChan, time.Now())
+					break
+				}
 				// Strategy is halted, should not continue
 				netPnL := new(big.Int).Sub(state.CumulativeRewards, state.CumulativeGas)
 				netPnL = new(big.Int).Sub(netPnL, state.TotalSwapFees)
-				sendReport(reportChan, types.StrategyReport{
-					Timestamp:     time.Now(),
-					EventType:     "shutdown",
-					Message:       "Strategy shutdown requested",
-					Phase:         &state.CurrentState,
-					CumulativeGas: state.CumulativeGas,
-					Profit:        state.CumulativeRewards,
-					NetPnL:        netPnL,
+				b.sendReport(reportChan, types.StrategyReport{
+					Timestamp:      time.Now(),
+					EventType:      "shutdown",
+					Message:        "Strategy shutdown requested",
+					Phase:          &state.CurrentState,
+					CumulativeGas:  state.CumulativeGas,
+					Profit:         state.CumulativeRewards,
+					NetPnL:         netPnL,
+					GasByOperation: state.GasByOperation,
 				}) // State changed to Halted
 				return fmt.Errorf("strategy is in Halted state")
 			}
+			b.status.publish(state)
 		}
 	}
 }
@@ -422,7 +640,7 @@ func (b *Blackhole) initialPositionEntry(
 		state.CurrentStep = types.Step_None
 	}
 
-	sendReport(reportChan, types.StrategyReport{
+	b.sendReport(reportChan, types.StrategyReport{
 		Timestamp: time.Now(),
 		EventType: "strategy_start",
 		Message:   "Starting initial position entry",
@@ -431,11 +649,11 @@ func (b *Blackhole) initialPositionEntry(
 
 	// Get current balances
 	wavaxClient, _ := b.registry.Client(wavax)
-	wavaxBalanceRaw, _ := wavaxClient.Call(&b.myAddr, "balanceOf", b.myAddr)
+	wavaxBalanceRaw, _ := wavaxClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
 	wavaxBalance := wavaxBalanceRaw[0].(*big.Int)
 
 	usdcClient, _ := b.registry.Client(usdc)
-	usdcBalanceRaw, _ := usdcClient.Call(&b.myAddr, "balanceOf", b.myAddr)
+	usdcBalanceRaw, _ := usdcClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
 	usdcBalance := usdcBalanceRaw[0].(*big.Int)
 
 	// Get current pool state for price
@@ -448,10 +666,12 @@ func (b *Blackhole) initialPositionEntry(
 	// T017, T020: Calculate rebalance amounts
 	log.Printf("CalculateRebalanceAmounts: WAVAX %d, USDC %d, price : %v",
 		wavaxBalance.Int64(), usdcBalance.Int64(), poolState.SqrtPrice)
-	tokenToSwap, swapAmount, err := util.CalculateRebalanceAmounts(
+	// Size the swap net of the pool's current dynamic fee
+	tokenToSwap, swapAmount, err := util.CalculateRebalanceAmountsWithFee(
 		wavaxBalance,
 		usdcBalance,
 		poolState.SqrtPrice,
+		poolState.FeeFraction(),
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate rebalance: %w", err)
@@ -477,42 +697,16 @@ func (b *Blackhole) initialPositionEntry(
 			}
 
 			// Build swap route
-			wavaxUsdcPairAddr, _ := b.registry.GetAddress(wavaxUsdcPair)
-			route := types.Route{
-				Pair:         wavaxUsdcPairAddr,
-				From:         fromToken,
-				To:           toToken,
-				Stable:       false,
-				Concentrated: true,
-				Receiver:     b.myAddr,
+			route, err := b.findRoute(fromToken, toToken)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find swap route: %w", err)
 			}
 
 			// Calculate expected output amount using pool price
-			// Get price from sqrtPrice: price = (sqrtPrice / 2^96)^2
-			price := util.SqrtPriceToPrice(poolState.SqrtPrice)
-
-			// Adjust for decimals: WAVAX has 18 decimals, USDC has 6 decimals
-			// decimalAdjustment := new(big.Float).SetInt64(1_000_000_000_000) // 10^12
-			// priceUSDCperWAVAX := new(big.Float).Mul(price, decimalAdjustment)
+			expectedAmountOut := expectedSwapOut(poolState, tokenToSwap, swapAmount)
 
-			var expectedAmountOut *big.Int
-			if tokenToSwap == 0 {
-				// Swapping WAVAX to USDC
-				// expectedUSDC = swapAmount * priceUSDCperWAVAX
-				swapAmountFloat := new(big.Float).SetInt(swapAmount) // todo. expectedAmountOut 확인
-				expectedFloat := new(big.Float).Mul(swapAmountFloat, price)
-				// AmountOutBeforeAdjustment := new(big.Float).Mul(swapAmountFloat, priceUSDCperWAVAX)
-				// expectedFloat := new(big.Float).Quo(AmountOutBeforeAdjustment, decimalAdjustment)
-				expectedAmountOut, _ = expectedFloat.Int(nil)
-			} else {
-				// Swapping USDC to WAVAX
-				// expectedWAVAX = swapAmount / priceUSDCperWAVAX
-				swapAmountFloat := new(big.Float).SetInt(swapAmount)
-				expectedFloat := new(big.Float).Quo(swapAmountFloat, price)
-				// AmountOutBeforeAdjustment := new(big.Float).Quo(swapAmountFloat, priceUSDCperWAVAX)
-				// expectedFloat := new(big.Float).Mul(AmountOutBeforeAdjustment, decimalAdjustment)
-				expectedAmountOut, _ = expectedFloat.Int(nil)
-			}
+			// The pool keeps its dynamic fee out of the input amount
+			expectedAmountOut = util.ApplyFee(expectedAmountOut, poolState.FeeFraction())
 
 			// Calculate minimum output with slippage (apply slippage to the expected output amount)
 			minAmountOut := util.CalculateMinAmount(expectedAmountOut, config.SlippagePct)
@@ -522,38 +716,48 @@ func (b *Blackhole) initialPositionEntry(
 				AmountOutMin: minAmountOut,
 				Routes:       []types.Route{route},
 				To:           b.myAddr,
-				Deadline:     big.NewInt(time.Now().Add(20 * time.Minute).Unix()),
+				Deadline:     b.txDeadline(txDeadlineOffset),
 			}
 
-			swapTxHash, err := b.Swap(swapParams)
+			// Shrink the swap, or skip it, if it would move the price more than allowed
+			swapParams, err = b.limitPriceImpact(swapParams, config.MaxPriceImpact)
 			if err != nil {
-				return nil, fmt.Errorf("swap failed: %w", err)
+				return nil, fmt.Errorf("failed to estimate price impact: %w", err)
 			}
 
-			// Wait for swap transaction and get gas cost
-			swapReceipt, err := b.tl.WaitForTransaction(swapTxHash)
-			if err != nil {
-				return nil, fmt.Errorf("swap transaction failed: %w", err)
-			}
+			if swapParams != nil {
+				swapTxHash, err := b.Swap(swapParams)
+				if err != nil {
+					return nil, fmt.Errorf("swap failed: %w", err)
+				}
 
-			swapGasCost, _ = util.ExtractGasCost(swapReceipt)
+				// Wait for swap transaction and get gas cost
+				swapReceipt, err := b.tl.WaitForTransaction(swapTxHash)
+				if err != nil {
+					return nil, fmt.Errorf("swap transaction failed: %w", err)
+				}
+
+				swapGasCost, _ = util.ExtractGasCost(swapReceipt)
 
-			state.CumulativeGas = new(big.Int).Add(state.CumulativeGas, swapGasCost)
-			sendReport(reportChan, types.StrategyReport{
-				Timestamp:     time.Now(),
-				EventType:     "gas_cost",
-				Message:       fmt.Sprintf("Rebalancing: swapping token %d amount %s", tokenToSwap, swapAmount.String()),
-				GasCost:       swapGasCost,
-				CumulativeGas: state.CumulativeGas,
-				Phase:         &state.CurrentState,
-			})
+				swapRecords := []types.TransactionRecord{{TxHash: swapTxHash, GasCost: swapGasCost, Timestamp: time.Now(), Operation: "Swap"}}
+				state.RecordGas(swapRecords)
+				metrics.RecordTransactions(swapRecords)
+				b.sendReport(reportChan, types.StrategyReport{
+					Timestamp:     time.Now(),
+					EventType:     "gas_cost",
+					Message:       fmt.Sprintf("Rebalancing: swapping token %d amount %s", tokenToSwap, swapParams.AmountIn.String()),
+					GasCost:       swapGasCost,
+					CumulativeGas: state.CumulativeGas,
+					Phase:         &state.CurrentState,
+				})
 
-			// Update balances after swap
-			wavaxBalanceRaw, _ = wavaxClient.Call(&b.myAddr, "balanceOf", b.myAddr)
-			wavaxBalance = wavaxBalanceRaw[0].(*big.Int)
+				// Update balances after swap
+				wavaxBalanceRaw, _ = wavaxClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
+				wavaxBalance = wavaxBalanceRaw[0].(*big.Int)
 
-			usdcBalanceRaw, _ = usdcClient.Call(&b.myAddr, "balanceOf", b.myAddr)
-			usdcBalance = usdcBalanceRaw[0].(*big.Int)
+				usdcBalanceRaw, _ = usdcClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
+				usdcBalance = usdcBalanceRaw[0].(*big.Int)
+			}
 		}
 	}
 
@@ -566,8 +770,8 @@ func (b *Blackhole) initialPositionEntry(
 			return nil, fmt.Errorf("mint failed: %w", err)
 		}
 
-		state.CumulativeGas = new(big.Int).Add(state.CumulativeGas, mintResult.TotalGasCost)
-		sendReport(reportChan, types.StrategyReport{
+		state.RecordGas(mintResult.Transactions)
+		b.sendReport(reportChan, types.StrategyReport{
 			Timestamp:     time.Now(),
 			EventType:     "gas_cost",
 			Message:       "Mint transaction completed",
@@ -599,7 +803,7 @@ func (b *Blackhole) initialPositionEntry(
 			return nil, fmt.Errorf("stake failed: %w", err)
 		}
 
-		state.CumulativeGas = new(big.Int).Add(state.CumulativeGas, stakeResult.TotalGasCost)
+		state.RecordGas(stakeResult.Transactions)
 
 		// Checkpoint: stake completed
 		state.CurrentStep = types.Step_Init_StakeCompleted
@@ -626,7 +830,7 @@ func (b *Blackhole) initialPositionEntry(
 		Timestamp:  time.Now(),
 	}
 
-	sendReport(reportChan, types.StrategyReport{
+	b.sendReport(reportChan, types.StrategyReport{
 		Timestamp:       time.Now(),
 		EventType:       "position_created",
 		Message:         "Initial position entry completed successfully",
@@ -643,7 +847,7 @@ func (b *Blackhole) initialPositionEntry(
 // Returns true if stable, false otherwise, or error
 func (b *Blackhole) stabilityLoop(
 	ctx context.Context,
-	// config *StrategyConfig,
+	config *types.StrategyConfig,
 	state *types.StrategyState,
 	stabilityWindow *types.StabilityWindow,
 	reportChan chan<- string,
@@ -665,19 +869,21 @@ func (b *Blackhole) stabilityLoop(
 	// T044: Check stability using StabilityWindow
 	isStable := stabilityWindow.CheckStability(poolState.SqrtPrice)
 
-	// T047: Send stability check report with progress
-	progress := stabilityWindow.Progress()
-	sendReport(reportChan, types.StrategyReport{
-		Timestamp: time.Now(),
-		EventType: "stability_check",
-		Message:   fmt.Sprintf("Stability check: progress=%.1f%% (%d/%d intervals)", progress*100, stabilityWindow.StableCount, stabilityWindow.RequiredIntervals),
-		Phase:     &state.CurrentState,
-	})
+	// T047: Send stability check report with progress, unless only state changes are reported
+	if config.ReportVerbosity != types.Quiet {
+		progress := stabilityWindow.Progress()
+		b.sendReport(reportChan, types.StrategyReport{
+			Timestamp: time.Now(),
+			EventType: "stability_check",
+			Message:   fmt.Sprintf("Stability check: progress=%.1f%% (%d/%d intervals)", progress*100, stabilityWindow.StableCount, stabilityWindow.RequiredIntervals),
+			Phase:     &state.CurrentState,
+		})
+	}
 
 	// T045: Transition to ExecutingRebalancing if stable
 	if isStable {
 		state.CurrentState = types.Initializing
-		sendReport(reportChan, types.StrategyReport{
+		b.sendReport(reportChan, types.StrategyReport{
 			Timestamp: time.Now(),
 			EventType: "stability_check",
 			Message:   "Price stabilized, ready to re-enter position",
@@ -691,3 +897,14 @@ func (b *Blackhole) stabilityLoop(
 
 	return false, nil
 }
+
+// isCriticalError reports whether err should halt the strategy immediately
+// On-chain reverts are critical; RPC and validation errors, and reverts found by a call or gas estimation
+// before anything was sent, count toward the circuit breaker threshold
+func isCriticalError(err error) bool {
+	var revertErr *txlistener.RevertError
+	if errors.As(err, &revertErr) {
+		return true
+	}
+	return util.IsCriticalError(err)
+}
@@ -1,12 +1,15 @@
 package blackholedex
 
 import (
+	"context"
 	"crypto/ecdsa"
 	"math/big"
 
 	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
 )
 
 // ContractClientInterface combines all contract interaction capabilities
@@ -24,6 +27,9 @@ type TxSender interface {
 
 	// SendWithValue executes a contract method with transaction and native token value
 	SendWithValue(priority types.Priority, value *big.Int, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error)
+
+	// SendCtx is Send bound to ctx, so cancelling ctx aborts the in-flight RPCs
+	SendCtx(ctx context.Context, priority types.Priority, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error)
 }
 
 // TxReader defines methods for reading blockchain and contract state
@@ -31,6 +37,9 @@ type TxReader interface {
 	// Call executes a read-only contract method (does not create transaction)
 	Call(from *common.Address, method string, args ...interface{}) ([]interface{}, error)
 
+	// CallCtx is Call bound to ctx, so cancelling ctx aborts the in-flight eth_call
+	CallCtx(ctx context.Context, from *common.Address, method string, args ...interface{}) ([]interface{}, error)
+
 	// Call executes a read-only contract method (does not create transaction)
 	CallWithRetry(from *common.Address, method string, args ...interface{}) ([]interface{}, error)
 
@@ -40,6 +49,12 @@ type TxReader interface {
 	// ParseReceipt parses events from transaction receipt
 	ParseReceipt(receipt *types.TxReceipt) (string, error)
 
+	// DecodeLogs decodes the contract's events in a receipt with typed parameters
+	DecodeLogs(receipt *types.TxReceipt) ([]types.DecodedEvent, error)
+
+	// FindEvent returns the first event named eventName the contract emitted in a receipt
+	FindEvent(receipt *types.TxReceipt, eventName string) (*types.DecodedEvent, bool)
+
 	// TransactionData retrieves raw transaction input data by hash
 	TransactionData(hash common.Hash) ([]byte, error)
 
@@ -62,6 +77,44 @@ type TxDecoder interface {
 	DecodeByHash(txHash common.Hash) (*types.DecodedTransaction, error)
 }
 
+// CodeReader retrieves the deployed bytecode and storage of a contract
+type CodeReader interface {
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+}
+
+// BalanceReader retrieves the native coin balance of an account
+type BalanceReader interface {
+	BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error)
+}
+
+// GasPriceReader retrieves the node's suggested gas price
+type GasPriceReader interface {
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+}
+
+// GasEstimator estimates the gas a transaction would use with eth_estimateGas
+type GasEstimator interface {
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+}
+
+// LogReader retrieves block headers and event logs
+// Also used to anchor transaction deadlines to chain time
+type LogReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error)
+	FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]ethtypes.Log, error)
+}
+
+// TransactionReader retrieves transactions by hash, mined or still pending
+type TransactionReader interface {
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *ethtypes.Transaction, isPending bool, err error)
+}
+
+// TransactionSender broadcasts signed transactions
+type TransactionSender interface {
+	SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error
+}
+
 type TxListener interface {
 	WaitForTransaction(txHash common.Hash) (*types.TxReceipt, error)
 }
@@ -2,8 +2,11 @@ package blackholedex
 
 import (
 	"crypto/ecdsa"
+	"errors"
+	"fmt"
 
 	"github.com/ChoSanghyuk/blackholedex/pkg/contractclient"
+	"github.com/ChoSanghyuk/blackholedex/pkg/contractclient/mock"
 	"github.com/ChoSanghyuk/blackholedex/pkg/txlistener"
 	"github.com/ChoSanghyuk/blackholedex/pkg/types"
 	"github.com/ChoSanghyuk/blackholedex/pkg/util"
@@ -17,6 +20,7 @@ import (
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/joho/godotenv"
+	"github.com/stretchr/testify/assert"
 )
 
 func TestBlackhole(t *testing.T) {
@@ -224,7 +228,7 @@ func TestBlackhole(t *testing.T) {
 		amountOutMinBig.SetString(amountOutMin, 10)
 
 		// Create swap parameters
-		deadline := big.NewInt(time.Now().Add(20 * time.Minute).Unix())
+		deadline := util.DeadlineFromNow(20 * time.Minute)
 		params := &types.SWAPExactTokensForTokensParams{
 			AmountIn:     amountInBig,
 			AmountOutMin: amountOutMinBig,
@@ -317,7 +321,7 @@ func TestBlackhole(t *testing.T) {
 
 	t.Run("Withdraw", func(t *testing.T) {
 		nftId := big.NewInt(2519306)
-		rtn, err := b.Withdraw(nftId) // todo Nonce 구하는 법
+		rtn, err := b.Withdraw(nftId, true) // todo Nonce 구하는 법
 		if err != nil {
 			t.Fatalf("Withdraw failed: %v", err)
 		}
@@ -337,3 +341,76 @@ func TestBlackhole(t *testing.T) {
 		t.Logf("GetAMMState Result %v", state)
 	})
 }
+
+func TestNewBlackholeRequiresContracts(t *testing.T) {
+	pk := "4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318"
+	contract := func(name string) ContractClientConfig {
+		return ContractClientConfig{Name: name, Address: "0x00000000000000000000000000000000000000a1", Abipath: "excluded"}
+	}
+
+	partial := []ContractClientConfig{contract(routerv2), contract(usdc), contract(wavax), contract(black), contract(deployer)}
+	_, err := NewBlackhole(nil, NewBlackholeConfig("", pk, nil, types.CL200, partial), &mockTxListener{}, nil)
+	assert.EqualError(t, err, "missing required contracts: wavaxUsdcPair, nonfungiblePositionManager, gauge, farmingCenter")
+
+	var all []ContractClientConfig
+	for _, name := range requiredContracts {
+		all = append(all, contract(name))
+	}
+	b, err := NewBlackhole(nil, NewBlackholeConfig("", pk, nil, types.CL200, all), &mockTxListener{}, nil)
+	assert.NoError(t, err)
+	assert.NotNil(t, b)
+}
+
+var _ ContractClient = (*mock.MockContractClient)(nil)
+
+// TestStakeWithMocks runs Stake entirely against in-memory contract clients
+func TestStakeWithMocks(t *testing.T) {
+	self := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	gaugeAddr := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	tokenID := big.NewInt(42)
+
+	nftManager := mock.NewMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"), nil)
+	nftManager.OnCall("ownerOf", []interface{}{tokenID}, self)
+	nftManager.OnCall("getApproved", []interface{}{tokenID}, common.Address{})
+	gaugeClient := mock.NewMockContractClient(gaugeAddr, nil)
+	gaugeClient.TxHash = common.HexToHash("0xdeadbeef")
+
+	b := newTestBlackhole(map[string]ContractClient{
+		nonfungiblePositionManager: nftManager,
+		gauge:                      gaugeClient,
+	}, &mockTxListener{})
+
+	result, err := b.Stake(tokenID)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, result.Success)
+
+	// The NFT is approved for the gauge, then deposited
+	if assert.Equal(t, []string{"approve"}, nftManager.SentMethods()) {
+		assert.Equal(t, []interface{}{gaugeAddr, tokenID}, nftManager.Sent()[0].Args)
+	}
+	assert.Equal(t, []string{"deposit"}, gaugeClient.SentMethods())
+	if assert.Len(t, result.Transactions, 2) {
+		assert.Equal(t, "ApproveNFT", result.Transactions[0].Operation)
+		assert.Equal(t, "DepositNFT", result.Transactions[1].Operation)
+		assert.Equal(t, common.HexToHash("0xdeadbeef"), result.Transactions[1].TxHash)
+	}
+
+	// A token the wallet does not own is rejected before anything is sent
+	nftManager.OnCall("ownerOf", []interface{}{big.NewInt(43)}, common.HexToAddress("0xbb"))
+	nftManager.OnCall("getApproved", nil, common.Address{})
+	_, err = b.Stake(big.NewInt(43))
+	assert.ErrorIs(t, err, ErrNFTNotOwned)
+	assert.ErrorContains(t, err, "owned by "+common.HexToAddress("0xbb").Hex())
+	assert.Len(t, gaugeClient.Sent(), 1)
+}
+
+func TestIsCriticalErrorRevert(t *testing.T) {
+	revertErr := &txlistener.RevertError{TxHash: common.HexToHash("0x01"), Status: "0x0", GasUsed: "0x5208"}
+	assert.True(t, isCriticalError(fmt.Errorf("mint failed: %w", revertErr)))
+	callRevertErr := &contractclient.CallRevertError{Reason: "execution reverted: STF"}
+	// Nothing was sent, so a pre-flight revert (e.g. slippage) only counts toward the circuit breaker
+	assert.False(t, isCriticalError(errors.Join(errors.New("mint Send 시, EstimateGas Error"), callRevertErr)))
+	assert.False(t, isCriticalError(errors.New("connection reset by peer")))
+}
@@ -0,0 +1,138 @@
+package blackholedex
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/contractclient"
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+)
+
+// ErrCapitalCapExceeded is returned by Mint when the new position would take the value
+// deployed across all WAVAX/USDC positions above the cap set by WithCapitalCap
+var ErrCapitalCapExceeded = errors.New("capital cap exceeded")
+
+// WithCapitalCap caps the total value, in USDC smallest units, deployed across all WAVAX/USDC positions
+// Before every mint (including the ones made by rebalances) the wallet's positions, the strategy's staked
+// position and the pending mint are valued at the pool price, and the mint is refused if their sum exceeds the cap
+func WithCapitalCap(usdcValue *big.Int) Option {
+	return func(b *Blackhole) {
+		b.capitalCap = usdcValue
+	}
+}
+
+// lockCapital serializes mints while a capital cap is set, so concurrent mints cannot each pass the
+// check against the same set of positions. The returned func releases the lock
+// The lock is held until the mint is confirmed, so the next check sees the new position
+func (b *Blackhole) lockCapital() func() {
+	if b.capitalCap == nil {
+		return func() {}
+	}
+	b.mintMu.Lock()
+	return b.mintMu.Unlock
+}
+
+// checkCapitalCap returns ErrCapitalCapExceeded if minting wavaxAmount and usdcAmount on top of the
+// existing positions would exceed the capital cap. Does nothing when no cap is set
+func (b *Blackhole) checkCapitalCap(sqrtPrice *big.Int, usdcIsToken0 bool, wavaxAmount, usdcAmount *big.Int) error {
+	if b.capitalCap == nil {
+		return nil
+	}
+
+	deployed, err := b.deployedValue(sqrtPrice, usdcIsToken0)
+	if err != nil {
+		return fmt.Errorf("failed to value existing positions: %w", err)
+	}
+	total := new(big.Int).Add(deployed, usdcValue(sqrtPrice, usdcIsToken0, wavaxAmount, usdcAmount))
+	if total.Cmp(b.capitalCap) > 0 {
+		return fmt.Errorf("%w: %s deployed plus new position totals %s, cap is %s",
+			ErrCapitalCapExceeded, deployed, total, b.capitalCap)
+	}
+	return nil
+}
+
+// deployedValue sums the value of the wallet's WAVAX/USDC positions at sqrtPrice, in USDC smallest units
+// The strategy's active position is included when it is staked in the gauge or the FarmingCenter
+// Unlike the asset snapshot, a position that cannot be read fails the whole sum rather than being skipped
+func (b *Blackhole) deployedValue(sqrtPrice *big.Int, usdcIsToken0 bool) (*big.Int, error) {
+	positions, err := b.GetUserPositions()
+	if err != nil {
+		return nil, err
+	}
+	staked, err := b.stakedActivePosition(positions)
+	if err != nil {
+		return nil, err
+	}
+	if staked != nil {
+		positions = append(positions, staked)
+	}
+
+	wavaxAddr, _ := b.registry.GetAddress(wavax)
+	usdcAddr, _ := b.registry.GetAddress(usdc)
+	total := big.NewInt(0)
+	for _, tokenID := range positions {
+		position, err := b.GetPositionDetails(tokenID)
+		if err != nil {
+			return nil, err
+		}
+		if !(position.Token0 == wavaxAddr && position.Token1 == usdcAddr) &&
+			!(position.Token0 == usdcAddr && position.Token1 == wavaxAddr) {
+			continue
+		}
+
+		amount0, amount1, err := util.CalculateTokenAmountsFromLiquidity(position.Liquidity, sqrtPrice, position.TickLower, position.TickUpper)
+		if err != nil {
+			return nil, fmt.Errorf("failed to calculate token amounts for position %s: %w", tokenID, err)
+		}
+		wavaxAmount, usdcAmount := amount0, amount1
+		if position.Token0 == usdcAddr {
+			wavaxAmount, usdcAmount = amount1, amount0
+		}
+		total.Add(total, usdcValue(sqrtPrice, usdcIsToken0, wavaxAmount, usdcAmount))
+	}
+	return total, nil
+}
+
+// stakedActivePosition returns the strategy's active NFT if it is staked away from the wallet, and so missing from walletPositions
+// A staked NFT is held by the gauge or the FarmingCenter, which cannot list deposits by owner; returns nil when
+// there is no active NFT, it is already listed, or it is no longer ours (e.g. burned by a rebalance in progress)
+func (b *Blackhole) stakedActivePosition(walletPositions []*big.Int) (*big.Int, error) {
+	active := b.ActiveNFT()
+	if active == nil {
+		return nil, nil
+	}
+	for _, tokenID := range walletPositions {
+		if tokenID.Cmp(active) == 0 {
+			return nil, nil
+		}
+	}
+
+	location, err := b.GetPositionLocation(active)
+	var revertErr *contractclient.CallRevertError
+	if errors.Is(err, ErrNFTNotOwned) || errors.As(err, &revertErr) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to locate active position %s: %w", active, err)
+	}
+	if location == types.InWallet {
+		return nil, nil
+	}
+	return active, nil
+}
+
+// usdcValue values a WAVAX and USDC amount in USDC smallest units at the pool's sqrtPrice
+func usdcValue(sqrtPrice *big.Int, usdcIsToken0 bool, wavaxAmount, usdcAmount *big.Int) *big.Int {
+	// The pool price is token1 per token0
+	price := util.SqrtPriceToPrice(sqrtPrice)
+	wavaxValue := new(big.Float).SetInt(wavaxAmount)
+	if usdcIsToken0 {
+		wavaxValue.Quo(wavaxValue, price)
+	} else {
+		wavaxValue.Mul(wavaxValue, price)
+	}
+	value, _ := wavaxValue.Int(nil)
+	return value.Add(value, usdcAmount)
+}
@@ -0,0 +1,115 @@
+package blackholedex
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/contractclient"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMintCapitalCap(t *testing.T) {
+	poolABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraPoolState.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	sqrtPriceFloat := new(big.Float).Mul(new(big.Float).SetInt(util.Q96), big.NewFloat(math.Pow(1.0001, 50)))
+	sqrtPrice, _ := sqrtPriceFloat.Int(nil)
+
+	// The wallet already holds two WAVAX/USDC positions, one in each token order
+	existing := map[int64][]interface{}{
+		7: mockPosition(wavaxAddr, usdcAddr),
+		8: mockPosition(usdcAddr, wavaxAddr),
+	}
+	for _, position := range existing {
+		position[7] = big.NewInt(40_000_000)
+	}
+
+	// The strategy's active position 9 is staked, so the wallet does not list it
+	staked := mockPosition(wavaxAddr, usdcAddr)
+	staked[7] = big.NewInt(20_000_000)
+	gaugeAddr := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	var stakedOwner func() ([]interface{}, error)
+
+	setup := func(capital *big.Int) (*Blackhole, *mockContractClient) {
+		pool := newABIMock(common.HexToAddress("0x00000000000000000000000000000000000000d1"), poolABI, map[string][]interface{}{
+			"safelyGetStateOfAMM": {sqrtPrice, big.NewInt(100), uint16(0), uint8(0), big.NewInt(0), big.NewInt(200), big.NewInt(0)},
+			"token0":              {wavaxAddr},
+			"token1":              {usdcAddr},
+			"fee":                 {uint16(0)},
+			"tickSpacing":         {big.NewInt(200)},
+			"liquidity":           {big.NewInt(0)},
+		})
+		nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+		nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			switch method {
+			case "balanceOf":
+				return []interface{}{big.NewInt(int64(len(existing)))}, nil
+			case "tokenOfOwnerByIndex":
+				return []interface{}{big.NewInt(7 + args[1].(*big.Int).Int64())}, nil
+			case "positions":
+				if args[0].(*big.Int).Int64() == 9 {
+					return staked, nil
+				}
+				return existing[args[0].(*big.Int).Int64()], nil
+			case "ownerOf":
+				return stakedOwner()
+			}
+			return nil, errors.New("unexpected method " + method)
+		}
+		b := newTestBlackhole(map[string]ContractClient{
+			wavaxUsdcPair:              pool,
+			wavax:                      newMockToken(wavaxAddr, big.NewInt(1_000_000_000)),
+			usdc:                       newMockToken(usdcAddr, big.NewInt(1_000_000_000)),
+			nonfungiblePositionManager: nftManager,
+			gauge:                      newMockContractClient(gaugeAddr),
+			farmingCenter:              newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000c2")),
+		}, &mockTxListener{})
+		WithCapitalCap(capital)(b)
+		return b, nftManager
+	}
+
+	// The existing positions hold about 3.2M of value and the new one about 2.4M
+	t.Run("BlocksMintOverCap", func(t *testing.T) {
+		b, nftManager := setup(big.NewInt(5_000_000))
+		result, err := b.Mint(big.NewInt(3_000_000), big.NewInt(1_000_000), 6, 5)
+		assert.ErrorIs(t, err, ErrCapitalCapExceeded)
+		assert.False(t, result.Success)
+		assert.Empty(t, nftManager.sentMethods())
+	})
+
+	t.Run("AllowsMintUnderCap", func(t *testing.T) {
+		b, nftManager := setup(big.NewInt(6_000_000))
+		_, err := b.Mint(big.NewInt(3_000_000), big.NewInt(1_000_000), 6, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mint"}, nftManager.sentMethods())
+	})
+
+	// The staked position adds about 0.8M, taking the total over a cap the wallet positions alone fit under
+	t.Run("CountsStakedPosition", func(t *testing.T) {
+		stakedOwner = func() ([]interface{}, error) { return []interface{}{gaugeAddr}, nil }
+		b, nftManager := setup(big.NewInt(6_000_000))
+		b.status.nftID.Store(big.NewInt(9))
+		_, err := b.Mint(big.NewInt(3_000_000), big.NewInt(1_000_000), 6, 5)
+		assert.ErrorIs(t, err, ErrCapitalCapExceeded)
+		assert.Empty(t, nftManager.sentMethods())
+	})
+
+	// A rebalance burns the active position before minting its replacement
+	t.Run("SkipsBurnedActivePosition", func(t *testing.T) {
+		stakedOwner = func() ([]interface{}, error) {
+			return nil, &contractclient.CallRevertError{Reason: "execution reverted: ERC721: invalid token ID"}
+		}
+		b, nftManager := setup(big.NewInt(6_000_000))
+		b.status.nftID.Store(big.NewInt(9))
+		_, err := b.Mint(big.NewInt(3_000_000), big.NewInt(1_000_000), 6, 5)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mint"}, nftManager.sentMethods())
+	})
+}
@@ -3,16 +3,19 @@ package main
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 	"time"
 
 	blackholedex "github.com/ChoSanghyuk/blackholedex"
 	"github.com/ChoSanghyuk/blackholedex/configs"
 	"github.com/ChoSanghyuk/blackholedex/internal/db"
+	"github.com/ChoSanghyuk/blackholedex/pkg/metrics"
+	"github.com/ChoSanghyuk/blackholedex/pkg/notify"
+	"github.com/ChoSanghyuk/blackholedex/pkg/reportsink"
 	"github.com/ChoSanghyuk/blackholedex/pkg/txlistener"
 	"github.com/ChoSanghyuk/blackholedex/pkg/util"
-
-	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 func main() {
@@ -38,7 +41,7 @@ func main() {
 		panic(err)
 	}
 
-	client, err := ethclient.Dial(conf.RPC)
+	client, err := blackholedex.DialRPC(conf.RPC, conf.RPCHeaders)
 	if err != nil {
 		panic(err)
 	}
@@ -70,8 +73,19 @@ func main() {
 		panic(err)
 	}
 
+	// Optional Prometheus endpoint, e.g. METRICS_ADDR=:9100
+	if metricsAddr := os.Getenv("METRICS_ADDR"); metricsAddr != "" {
+		go func() {
+			mux := http.NewServeMux()
+			mux.Handle("/metrics", metrics.Handler())
+			if err := http.ListenAndServe(metricsAddr, mux); err != nil {
+				fmt.Printf("metrics server stopped: %s\n", err)
+			}
+		}()
+	}
+
 	strategyConf := conf.ToStrategyConfig()
-	reportChan := make(chan string)
+	reportChan := make(chan string, conf.ReportBufferSize())
 	go func() {
 		err := blackhole.RunAutoPositionStrategy(
 			context.Background(),
@@ -81,8 +95,41 @@ func main() {
 		fmt.Printf("RunStrategy1 오류 발생. %s", err)
 	}()
 
+	// Optional JSON-Lines event log, e.g. REPORT_LOG_DIR=./reports (rotated at 10 MB)
+	var sink *reportsink.FileSink
+	if reportDir := os.Getenv("REPORT_LOG_DIR"); reportDir != "" {
+		sink, err = reportsink.NewFileSink(reportDir, 10<<20)
+		if err != nil {
+			panic(err)
+		}
+		defer sink.Close()
+	}
+
+	// Optional alerts, e.g. WEBHOOK_URL=https://... WEBHOOK_EVENTS=error,shutdown (default: notify.DefaultEventTypes)
+	var alerts chan string
+	if webhookURL := os.Getenv("WEBHOOK_URL"); webhookURL != "" {
+		var eventTypes []string
+		if events := os.Getenv("WEBHOOK_EVENTS"); events != "" {
+			eventTypes = strings.Split(events, ",")
+		}
+		alerts = make(chan string, conf.ReportBufferSize())
+		go notify.NewWebhookNotifier(webhookURL, eventTypes).Consume(context.Background(), alerts)
+	}
+
 	for update := range reportChan {
 		println(update)
+		if alerts != nil {
+			select {
+			case alerts <- update:
+			default:
+				fmt.Println("webhook notifier is behind, alert dropped")
+			}
+		}
+		if sink != nil {
+			if err := sink.Write(update); err != nil {
+				fmt.Printf("failed to log report: %s\n", err)
+			}
+		}
 	}
 
 }
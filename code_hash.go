@@ -0,0 +1,101 @@
+package blackholedex
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// RecordCodeHashes stores the keccak256 hash of every registered contract's deployed code
+// The recorded hashes are the baseline used by CheckCodeHashes
+func (b *Blackhole) RecordCodeHashes() error {
+	hashes := make(map[string]common.Hash)
+	for _, name := range b.registry.Names() {
+		hash, err := b.codeHash(name)
+		if err != nil {
+			return err
+		}
+		hashes[name] = hash
+	}
+	b.codeHashes = hashes
+	return nil
+}
+
+// CheckCodeHashes compares each contract's deployed code against the recorded hash
+// Returns the names of contracts whose code changed (e.g. a proxy upgrade)
+// Changed hashes become the new baseline so each upgrade is flagged once
+func (b *Blackhole) CheckCodeHashes() ([]string, error) {
+	if b.codeHashes == nil {
+		return nil, fmt.Errorf("code hashes not recorded: call RecordCodeHashes first")
+	}
+
+	var changed []string
+	for _, name := range b.registry.Names() {
+		hash, err := b.codeHash(name)
+		if err != nil {
+			return changed, err
+		}
+		if recorded, ok := b.codeHashes[name]; ok && recorded != hash {
+			changed = append(changed, name)
+		}
+		b.codeHashes[name] = hash
+	}
+	return changed, nil
+}
+
+// eip1967ImplementationSlot is bytes32(uint256(keccak256("eip1967.proxy.implementation")) - 1)
+var eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+
+// codeHash returns the keccak256 hash of the deployed code for a registered contract
+// For EIP-1967 proxies the implementation address and code are hashed too,
+// since an upgrade only changes the implementation slot and leaves the proxy's code untouched
+func (b *Blackhole) codeHash(name string) (common.Hash, error) {
+	addr, err := b.registry.GetAddress(name)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	code, err := b.codeReader.CodeAt(b.rpcContext(), addr, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get code for %s (%s): %w", name, addr.Hex(), err)
+	}
+
+	slot, err := b.codeReader.StorageAt(b.rpcContext(), addr, eip1967ImplementationSlot, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to read implementation slot for %s (%s): %w", name, addr.Hex(), err)
+	}
+	impl := common.BytesToAddress(slot)
+	if impl == (common.Address{}) {
+		return crypto.Keccak256Hash(code), nil
+	}
+
+	implCode, err := b.codeReader.CodeAt(b.rpcContext(), impl, nil)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get implementation code for %s (%s): %w", name, impl.Hex(), err)
+	}
+	return crypto.Keccak256Hash(code, impl.Bytes(), implCode), nil
+}
+
+// checkContractUpgrades runs CheckCodeHashes and reports any changed contracts
+func (b *Blackhole) checkContractUpgrades(state *types.StrategyState, reportChan chan<- string) {
+	changed, err := b.CheckCodeHashes()
+	if err != nil {
+		log.Printf("Warning: failed to check contract code hashes: %v", err)
+	}
+	if len(changed) == 0 {
+		return
+	}
+
+	log.Printf("Contract code changed for: %s", strings.Join(changed, ", "))
+	b.sendReport(reportChan, types.StrategyReport{
+		Timestamp: time.Now(),
+		EventType: "contract_upgraded",
+		Message:   fmt.Sprintf("Deployed code changed for %s - review ABIs", strings.Join(changed, ", ")),
+		Phase:     &state.CurrentState,
+	})
+}
@@ -0,0 +1,104 @@
+package blackholedex
+
+import (
+	"context"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCodeReader serves deployed code and EIP-1967 implementation addresses per address
+type mockCodeReader struct {
+	mu   sync.Mutex
+	code map[common.Address][]byte
+	impl map[common.Address]common.Address
+}
+
+func (r *mockCodeReader) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.code[account], nil
+}
+
+func (r *mockCodeReader) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if key != eip1967ImplementationSlot {
+		return make([]byte, 32), nil
+	}
+	return common.LeftPadBytes(r.impl[account].Bytes(), 32), nil
+}
+
+func TestCodeHashChangeDetection(t *testing.T) {
+	routerAddr := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000e2")
+
+	reader := &mockCodeReader{code: map[common.Address][]byte{
+		routerAddr: {0x60, 0x80},
+		usdcAddr:   {0x60, 0x60},
+	}}
+	b := newTestBlackhole(map[string]ContractClient{
+		routerv2: newMockContractClient(routerAddr),
+		usdc:     newMockContractClient(usdcAddr),
+	}, &mockTxListener{})
+	b.codeReader = reader
+
+	assert.NoError(t, b.RecordCodeHashes())
+
+	changed, err := b.CheckCodeHashes()
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+
+	// Simulate a proxy upgrade of the router
+	reader.code[routerAddr] = []byte{0x60, 0x80, 0x01}
+
+	reportChan := make(chan string, 1)
+	state := &types.StrategyState{CurrentState: types.ActiveMonitoring}
+	b.checkContractUpgrades(state, reportChan)
+
+	select {
+	case report := <-reportChan:
+		assert.Contains(t, report, `"event_type":"contract_upgraded"`)
+		assert.Contains(t, report, routerv2)
+		assert.NotContains(t, report, usdc)
+	default:
+		t.Fatal("expected contract_upgraded report")
+	}
+
+	// The upgrade is flagged only once
+	changed, err = b.CheckCodeHashes()
+	assert.NoError(t, err)
+	assert.Empty(t, changed)
+}
+
+func TestCodeHashProxyUpgrade(t *testing.T) {
+	proxyAddr := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	implV1 := common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	implV2 := common.HexToAddress("0x00000000000000000000000000000000000000f2")
+
+	reader := &mockCodeReader{
+		code: map[common.Address][]byte{
+			proxyAddr: {0x60, 0x80},
+			implV1:    {0x60, 0x01},
+			implV2:    {0x60, 0x02},
+		},
+		impl: map[common.Address]common.Address{proxyAddr: implV1},
+	}
+	b := newTestBlackhole(map[string]ContractClient{
+		routerv2: newMockContractClient(proxyAddr),
+	}, &mockTxListener{})
+	b.codeReader = reader
+
+	assert.NoError(t, b.RecordCodeHashes())
+
+	// The proxy's own code is unchanged; only the implementation slot moves
+	reader.impl[proxyAddr] = implV2
+
+	changed, err := b.CheckCodeHashes()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{routerv2}, changed)
+}
@@ -1,22 +1,58 @@
 package configs
 
 import (
+	"errors"
 	"fmt"
+	"math/big"
 	"os"
+	"sort"
 	"time"
 
 	blackholedex "github.com/ChoSanghyuk/blackholedex"
 	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
 
 	"gopkg.in/yaml.v3"
 )
 
 // Config represents the entire configuration structure from config.yml
 type Config struct {
-	RPC              string                `yaml:"rpc"`
+	RPC              RPCURLs               `yaml:"rpc"`         // A single URL or a list tried in order (see blackholedex.DialRPC)
+	RPCHeaders       map[string]string     `yaml:"rpc_headers"` // Sent with every RPC request, e.g. a provider API key
 	ActivePool       string                `yaml:"active_pool"`
 	ContractClient   ContractClientSection `yaml:"contract_client"`
 	StrategyYAMLData StrategyYAMLData      `yaml:"strategy"`
+	// ABITypes maps a contract type to its shared ABI path, overriding DefaultABITypes
+	ABITypes map[string]string `yaml:"abi_types"`
+}
+
+// RPCURLs lists RPC endpoints, primary first; in YAML it is a single URL or a sequence of URLs
+type RPCURLs []string
+
+// UnmarshalYAML accepts a single URL, as in configs written before failover, or a list of URLs
+func (r *RPCURLs) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind == yaml.ScalarNode {
+		var url string
+		if err := value.Decode(&url); err != nil {
+			return err
+		}
+		*r = RPCURLs{url}
+		return nil
+	}
+	var urls []string
+	if err := value.Decode(&urls); err != nil {
+		return err
+	}
+	*r = urls
+	return nil
+}
+
+// DefaultABITypes are the shared ABI paths used for contracts that set a type instead of an abi
+var DefaultABITypes = map[string]string{
+	"erc20":  "blackholedex-contracts/abi/ERC20.json",
+	"pool":   "blackholedex-contracts/abi/IAlgebraPoolState.json",
+	"router": "blackholedex-contracts/abi/RouterV2.json",
+	"gauge":  "blackholedex-contracts/abi/GaugeV2.json",
 }
 
 // ContractClientSection represents the contract_client section with common and pool-specific configs
@@ -27,20 +63,57 @@ type ContractClientSection struct {
 }
 
 // ContractClientYAMLData represents a single contract configuration from YAML
+// An explicit abi takes precedence over the shared ABI of its type
 type ContractClientYAMLData struct {
-	Address string `yaml:"address"`
-	ABI     string `yaml:"abi"`
+	Address  string `yaml:"address"`
+	ABI      string `yaml:"abi"`
+	Type     string `yaml:"type"`     // Optional, e.g. erc20, pool or router (see ABITypes)
+	Symbol   string `yaml:"symbol"`   // Optional token symbol for human-readable amounts
+	Decimals int    `yaml:"decimals"` // Token decimals; used with symbol
+}
+
+// abiPath returns the ABI path of data, resolving its type when no abi is given
+func (c *Config) abiPath(data ContractClientYAMLData) (string, error) {
+	if data.ABI != "" || data.Type == "" {
+		return data.ABI, nil
+	}
+	if path, ok := c.ABITypes[data.Type]; ok {
+		return path, nil
+	}
+	if path, ok := DefaultABITypes[data.Type]; ok {
+		return path, nil
+	}
+	return "", fmt.Errorf("unknown contract type %q", data.Type)
 }
 
 type StrategyYAMLData struct {
 	MonitoringInterval      int     `yaml:"monitoringIntervalSec"`
 	StabilityThreshold      float64 `yaml:"stabilityThreshold"`
 	StabilityIntervals      int     `yaml:"stabilityIntervals"`
+	MaxCumulativeDrift      float64 `yaml:"maxCumulativeDrift"`
 	RangeWidth              int     `yaml:"rangeWidth"`
 	SlippagePct             int     `yaml:"slippagePct"`
 	CircuitBreakerWindow    int     `yaml:"circuitBreakerWindowMin"`
 	CircuitBreakerThreshold int     `yaml:"circuitBreakerThreshold"`
 	InitPhase               int     `yaml:"initPhase"`
+	CodeHashCheckInterval   int     `yaml:"codeHashCheckIntervalMin"`
+	GasTopUpFloor           float64 `yaml:"gasTopUpFloorAvax"`
+	GasTopUpAmount          float64 `yaml:"gasTopUpAmountAvax"`
+	SnapshotInterval        int     `yaml:"snapshotIntervalMin"`
+	MaxPriceImpact          float64 `yaml:"maxPriceImpactPct"`
+	ReportBuffer            int     `yaml:"reportBuffer"`
+	MinRewardThreshold      float64 `yaml:"minRewardThresholdBlack"`
+}
+
+// DefaultReportBuffer is the report channel buffer used when reportBuffer is not set
+const DefaultReportBuffer = 100
+
+// ReportBufferSize returns the buffer size for the strategy's report channel
+func (c *Config) ReportBufferSize() int {
+	if c.StrategyYAMLData.ReportBuffer <= 0 {
+		return DefaultReportBuffer
+	}
+	return c.StrategyYAMLData.ReportBuffer
 }
 
 // LoadConfig reads and parses config.yml into a Config struct
@@ -55,22 +128,95 @@ func LoadConfig(path string) (*Config, error) {
 		return nil, fmt.Errorf("failed to parse config YAML: %w", err)
 	}
 
+	if err := config.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return &config, nil
 }
 
-func (c *Config) ToBlackholeConfigs(pk string) *blackholedex.BlackholeConfig {
-	var configs []blackholedex.ContractClientConfig
+// excludedABI is the ABI path of an address-only contract, which NewBlackhole loads no ABI for
+const excludedABI = "excluded"
 
-	// Add common contracts
-	for name, data := range c.ContractClient.Common {
-		configs = append(configs, blackholedex.ContractClientConfig{
-			Name:    name,
-			Address: data.Address,
-			Abipath: data.ABI,
-		})
+// Validate checks the RPC URL and every configured contract, normalizing addresses to checksum form
+// Each contract needs a valid address and an ABI path to an existing file (or "excluded"); one address may not be
+// configured with two different ABIs. Invalid addresses are rejected instead of silently becoming the zero address
+// Returns all problems found, each naming the offending key
+func (c *Config) Validate() error {
+	var errs []error
+	if len(c.RPC) == 0 {
+		errs = append(errs, errors.New("rpc: RPC URL is not set"))
+	}
+	for i, url := range c.RPC {
+		if url == "" {
+			errs = append(errs, fmt.Errorf("rpc[%d]: RPC URL is empty", i))
+		}
 	}
 
-	// Add pool-specific contracts based on active_pool
+	sections := []struct {
+		name      string
+		contracts map[string]ContractClientYAMLData
+	}{
+		{"common", c.ContractClient.Common},
+		{"cl200", c.ContractClient.CL200},
+		{"cl1", c.ContractClient.CL1},
+	}
+
+	// Key and ABI of the first contract seen at each address
+	type contractRef struct{ key, abi string }
+	seen := make(map[common.Address]contractRef)
+
+	for _, section := range sections {
+		// Sorted so the reported errors are stable
+		names := make([]string, 0, len(section.contracts))
+		for name := range section.contracts {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			data := section.contracts[name]
+			key := fmt.Sprintf("contract_client.%s.%s", section.name, name)
+
+			abiPath, err := c.abiPath(data)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s.type: %w", key, err))
+			} else if abiPath == "" {
+				errs = append(errs, fmt.Errorf("%s.abi: ABI path is not set", key))
+			} else if abiPath != excludedABI {
+				if _, err := os.Stat(abiPath); err != nil {
+					errs = append(errs, fmt.Errorf("%s.abi: %w", key, err))
+				}
+			}
+
+			if data.Decimals < 0 || data.Decimals > 77 {
+				errs = append(errs, fmt.Errorf("%s.decimals: must be in range [0, 77], got %d", key, data.Decimals))
+			}
+
+			if !common.IsHexAddress(data.Address) {
+				errs = append(errs, fmt.Errorf("%s.address: invalid address %q", key, data.Address))
+				continue
+			}
+			addr := common.HexToAddress(data.Address)
+			if first, ok := seen[addr]; ok && first.abi != abiPath {
+				errs = append(errs, fmt.Errorf("%s.address: %s is also configured as %s with a different ABI", key, addr.Hex(), first.key))
+			} else if !ok {
+				seen[addr] = contractRef{key: key, abi: abiPath}
+			}
+			data.Address = addr.Hex()
+			section.contracts[name] = data
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// ToContractClientConfigs returns the common contracts and those of the active pool, with types resolved to ABI paths
+// An unknown type (rejected by Validate) leaves the ABI path empty, which NewBlackhole fails to load
+func (c *Config) ToContractClientConfigs() []blackholedex.ContractClientConfig {
+	var configs []blackholedex.ContractClientConfig
+
+	// Add common contracts, then pool-specific contracts based on active_pool
 	var poolContracts map[string]ContractClientYAMLData
 	switch c.ActivePool {
 	case "cl1":
@@ -79,14 +225,25 @@ func (c *Config) ToBlackholeConfigs(pk string) *blackholedex.BlackholeConfig {
 		poolContracts = c.ContractClient.CL200
 	}
 
-	for name, data := range poolContracts {
-		configs = append(configs, blackholedex.ContractClientConfig{
-			Name:    name,
-			Address: data.Address,
-			Abipath: data.ABI,
-		})
+	for _, contracts := range []map[string]ContractClientYAMLData{c.ContractClient.Common, poolContracts} {
+		for name, data := range contracts {
+			abiPath, _ := c.abiPath(data)
+			configs = append(configs, blackholedex.ContractClientConfig{
+				Name:     name,
+				Address:  data.Address,
+				Abipath:  abiPath,
+				Symbol:   data.Symbol,
+				Decimals: data.Decimals,
+			})
+		}
 	}
 
+	return configs
+}
+
+func (c *Config) ToBlackholeConfigs(pk string) *blackholedex.BlackholeConfig {
+	configs := c.ToContractClientConfigs()
+
 	var pool types.PoolType
 	switch c.ActivePool {
 	case "cl1":
@@ -97,8 +254,12 @@ func (c *Config) ToBlackholeConfigs(pk string) *blackholedex.BlackholeConfig {
 		pool = types.CL200 // default to CL200 if unknown
 	}
 
+	var primaryRPC string
+	if len(c.RPC) > 0 {
+		primaryRPC = c.RPC[0]
+	}
 	return blackholedex.NewBlackholeConfig(
-		c.RPC,
+		primaryRPC,
 		pk,
 		nil, // todo. 필요시 config.yaml에서 별도 설정.
 		pool,
@@ -111,25 +272,26 @@ func (c *Config) ToStrategyConfig() *types.StrategyConfig {
 		MonitoringInterval:      time.Duration(c.StrategyYAMLData.MonitoringInterval) * time.Second,
 		StabilityThreshold:      c.StrategyYAMLData.StabilityThreshold,
 		StabilityIntervals:      c.StrategyYAMLData.StabilityIntervals,
+		MaxCumulativeDrift:      c.StrategyYAMLData.MaxCumulativeDrift,
 		RangeWidth:              c.StrategyYAMLData.RangeWidth,
 		SlippagePct:             c.StrategyYAMLData.SlippagePct,
 		CircuitBreakerWindow:    time.Duration(c.StrategyYAMLData.CircuitBreakerWindow) * time.Minute,
 		CircuitBreakerThreshold: c.StrategyYAMLData.CircuitBreakerThreshold,
+		CodeHashCheckInterval:   time.Duration(c.StrategyYAMLData.CodeHashCheckInterval) * time.Minute,
+		GasTopUpFloor:           avaxToWei(c.StrategyYAMLData.GasTopUpFloor),
+		GasTopUpAmount:          avaxToWei(c.StrategyYAMLData.GasTopUpAmount),
+		SnapshotInterval:        time.Duration(c.StrategyYAMLData.SnapshotInterval) * time.Minute,
+		MaxPriceImpact:          c.StrategyYAMLData.MaxPriceImpact,
+		MinRewardThreshold:      avaxToWei(c.StrategyYAMLData.MinRewardThreshold), // BLACK has 18 decimals, like AVAX
 		// InitPhase:               blackholedex.StrategyPhase(c.StrategyYAMLData.InitPhase),
 	}
 }
 
-// // ToContractClientConfigs converts the Config struct into a slice of ContractClientConfig
-// // This method returns the format expected by blackholedex.NewBlackhole()
-// func (c *Config) ToContractClientConfigs() []blackholedex.ContractClientConfig {
-// 	var configs []blackholedex.ContractClientConfig
-
-// 	for _, data := range c.ContractClient {
-// 		configs = append(configs, blackholedex.ContractClientConfig{
-// 			Address: data.Address,
-// 			Abipath: data.ABI,
-// 		})
-// 	}
-
-// 	return configs
-// }
+// avaxToWei converts an AVAX amount to wei, returning nil for zero (feature disabled)
+func avaxToWei(avax float64) *big.Int {
+	if avax <= 0 {
+		return nil
+	}
+	wei, _ := new(big.Float).Mul(big.NewFloat(avax), big.NewFloat(1e18)).Int(nil)
+	return wei
+}
@@ -0,0 +1,177 @@
+package configs
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+func TestValidateNormalizesAddresses(t *testing.T) {
+	// ABI paths are relative to the repository root, as for cmd/main.go
+	t.Chdir("..")
+	c := &Config{RPC: RPCURLs{"http://localhost:8545"}, ContractClient: ContractClientSection{
+		Common: map[string]ContractClientYAMLData{
+			"wavax": {Address: "0xb31f66aa3c1e785363f0875a1b74e27b85fd66c7", ABI: "blackholedex-contracts/abi/WAVAX.json"},
+		},
+	}}
+
+	assert.NoError(t, c.Validate())
+	assert.Equal(t, "0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7", c.ContractClient.Common["wavax"].Address)
+}
+
+func TestValidateRejectsTruncatedAddress(t *testing.T) {
+	c := &Config{ContractClient: ContractClientSection{
+		CL200: map[string]ContractClientYAMLData{
+			"gauge": {Address: "0x3ADE52f9779c07471F4B6d5997444C3c2124C1", ABI: "GaugeV2.json"},
+		},
+	}}
+
+	err := c.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "contract_client.cl200.gauge.address")
+	}
+	// The malformed address is left as-is, never turned into the zero address
+	assert.Equal(t, "0x3ADE52f9779c07471F4B6d5997444C3c2124C1", c.ContractClient.CL200["gauge"].Address)
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Chdir("..")
+	_, err := LoadConfig("configs/config.yml")
+	assert.NoError(t, err)
+}
+
+func TestLoadConfigRejectsMalformed(t *testing.T) {
+	t.Chdir("..")
+	const contracts = `
+contract_client:
+  common:
+    wavax:
+      address: 0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7
+      abi: blackholedex-contracts/abi/WAVAX.json
+    usdc:
+      address: 0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E
+      abi: blackholedex-contracts/abi/ERC20.json
+`
+	load := func(t *testing.T, yml string) error {
+		path := filepath.Join(t.TempDir(), "config.yml")
+		if err := os.WriteFile(path, []byte(yml), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		_, err := LoadConfig(path)
+		return err
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, load(t, "rpc: http://localhost:8545\n"+contracts))
+	})
+
+	t.Run("MissingRPC", func(t *testing.T) {
+		assert.ErrorContains(t, load(t, contracts), "rpc: RPC URL is not set")
+	})
+
+	t.Run("MissingABIPath", func(t *testing.T) {
+		err := load(t, "rpc: http://localhost:8545\n"+contracts+`
+  cl200:
+    gauge:
+      address: 0x3ADE52f9779c07471F4B6d5997444C3c2124C1c0
+`)
+		assert.ErrorContains(t, err, "contract_client.cl200.gauge.abi: ABI path is not set")
+	})
+
+	t.Run("NonexistentABIFile", func(t *testing.T) {
+		err := load(t, "rpc: http://localhost:8545\n"+contracts+`
+  cl200:
+    gauge:
+      address: 0x3ADE52f9779c07471F4B6d5997444C3c2124C1c0
+      abi: blackholedex-contracts/abi/Missing.json
+`)
+		assert.ErrorContains(t, err, "contract_client.cl200.gauge.abi")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+	})
+
+	t.Run("DuplicateAddressWithDifferentABI", func(t *testing.T) {
+		err := load(t, "rpc: http://localhost:8545\n"+contracts+`
+  cl1:
+    wrapped:
+      address: 0xb31f66aa3c1e785363f0875a1b74e27b85fd66c7
+      abi: blackholedex-contracts/abi/ERC20.json
+`)
+		assert.ErrorContains(t, err, "contract_client.cl1.wrapped.address")
+		assert.ErrorContains(t, err, "also configured as contract_client.common.wavax")
+	})
+
+	t.Run("ReportsEveryInvalidContract", func(t *testing.T) {
+		err := load(t, contracts+`
+  cl200:
+    gauge:
+      address: 0x3ADE52f9779c07471F4B6d5997444C3c2124C1c0
+    wavaxUsdcPair:
+      address: 0x41100c6d2c6920b10d12cd8d59c8a9aa2ef56fc7
+      abi: blackholedex-contracts/abi/Missing.json
+`)
+		assert.ErrorContains(t, err, "rpc:")
+		assert.ErrorContains(t, err, "contract_client.cl200.gauge.abi")
+		assert.ErrorContains(t, err, "contract_client.cl200.wavaxUsdcPair.abi")
+	})
+}
+
+func TestContractTypeSharesABI(t *testing.T) {
+	t.Chdir("..")
+	const yml = `
+rpc: http://localhost:8545
+active_pool: cl200
+abi_types:
+  token: blackholedex-contracts/abi/ERC20.json
+contract_client:
+  common:
+    usdc:
+      address: 0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E
+      type: erc20
+    black:
+      address: 0xcd94a87696fac69edae3a70fe5725307ae1c43f6
+      type: token
+    wavax:
+      address: 0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7
+      type: erc20
+      abi: blackholedex-contracts/abi/WAVAX.json
+  cl200:
+    wavaxUsdcPair:
+      address: 0x41100c6d2c6920b10d12cd8d59c8a9aa2ef56fc7
+      type: pool
+`
+	path := filepath.Join(t.TempDir(), "config.yml")
+	if err := os.WriteFile(path, []byte(yml), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := LoadConfig(path)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	abis := make(map[string]string)
+	for _, cc := range c.ToContractClientConfigs() {
+		abis[cc.Name] = cc.Abipath
+	}
+	assert.Equal(t, map[string]string{
+		"usdc":          "blackholedex-contracts/abi/ERC20.json",
+		"black":         "blackholedex-contracts/abi/ERC20.json",
+		"wavax":         "blackholedex-contracts/abi/WAVAX.json", // Explicit abi wins over the type
+		"wavaxUsdcPair": "blackholedex-contracts/abi/IAlgebraPoolState.json",
+	}, abis)
+
+	c.ContractClient.Common["black"] = ContractClientYAMLData{Address: "0xcd94a87696fac69edae3a70fe5725307ae1c43f6", Type: "nft"}
+	assert.ErrorContains(t, c.Validate(), `contract_client.common.black.type: unknown contract type "nft"`)
+}
+
+func TestRPCURLs(t *testing.T) {
+	var single, list Config
+	assert.NoError(t, yaml.Unmarshal([]byte("rpc: http://localhost:8545\n"), &single))
+	assert.Equal(t, RPCURLs{"http://localhost:8545"}, single.RPC)
+
+	assert.NoError(t, yaml.Unmarshal([]byte("rpc:\n  - http://primary:8545\n  - http://backup:8545\n"), &list))
+	assert.Equal(t, RPCURLs{"http://primary:8545", "http://backup:8545"}, list.RPC)
+}
@@ -2,7 +2,9 @@ package blackholedex
 
 import (
 	"fmt"
+	"sort"
 	"strings"
+	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -10,34 +12,52 @@ import (
 // ContractRegistry manages a map of named contract clients
 // Provides lookup by name or address for any contract interaction
 // This is a domain-agnostic utility that can be moved to pkg/ if needed in other packages.
+// Safe for concurrent use
 type ContractRegistry struct {
+	mu      sync.RWMutex
 	clients map[string]ContractClient
 }
 
 // NewContractRegistry creates a registry from contract client map
+// The map is copied, so later changes to it do not affect the registry
 func NewContractRegistry(clients map[string]ContractClient) *ContractRegistry {
+	copied := make(map[string]ContractClient, len(clients))
+	for name, c := range clients {
+		copied[name] = c
+	}
 	return &ContractRegistry{
-		clients: clients,
+		clients: copied,
 	}
 }
 
+// Register adds or replaces the client registered under name
+func (r *ContractRegistry) Register(name string, client ContractClient) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[name] = client
+}
+
 // Client retrieves a contract client by registered name
 func (r *ContractRegistry) Client(name string) (ContractClient, error) {
+	r.mu.RLock()
 	c := r.clients[name]
+	r.mu.RUnlock()
 	if c == nil {
-		return nil, fmt.Errorf("no mapped client for name: %s", name)
+		return nil, fmt.Errorf("%w for name: %s", ErrClientNotFound, name)
 	}
 	return c, nil
 }
 
 // ClientByAddress finds a contract client by its contract address
 func (r *ContractRegistry) ClientByAddress(address string) (ContractClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	for _, c := range r.clients {
 		if strings.EqualFold(address, c.ContractAddress().Hex()) {
 			return c, nil
 		}
 	}
-	return nil, fmt.Errorf("no mapped client for address: %s", address)
+	return nil, fmt.Errorf("%w for address: %s", ErrClientNotFound, address)
 }
 
 // GetAddress retrieves the contract address for a given contract name
@@ -48,3 +68,28 @@ func (r *ContractRegistry) GetAddress(name string) (common.Address, error) {
 	}
 	return *client.ContractAddress(), nil
 }
+
+// Names returns the registered contract names in sorted order
+func (r *ContractRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Missing returns the names, in the given order, that have no registered client
+func (r *ContractRegistry) Missing(names ...string) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var missing []string
+	for _, name := range names {
+		if r.clients[name] == nil {
+			missing = append(missing, name)
+		}
+	}
+	return missing
+}
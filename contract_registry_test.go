@@ -0,0 +1,45 @@
+package blackholedex
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run with -race to check lookups and registrations are synchronized
+func TestContractRegistryConcurrentAccess(t *testing.T) {
+	registry := NewContractRegistry(map[string]ContractClient{
+		wavax: newMockContractClient(common.HexToAddress("0xa1")),
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			addr := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+			registry.Register(fmt.Sprintf("client%d", i), newMockContractClient(addr))
+		}(i)
+		go func() {
+			defer wg.Done()
+			_, err := registry.Client(wavax)
+			assert.NoError(t, err)
+			_, err = registry.ClientByAddress(common.HexToAddress("0xa1").Hex())
+			assert.NoError(t, err)
+			registry.Names()
+			registry.Missing(usdc)
+		}()
+	}
+	wg.Wait()
+
+	assert.Len(t, registry.Names(), 51)
+	for i := 0; i < 50; i++ {
+		addr, err := registry.GetAddress(fmt.Sprintf("client%d", i))
+		assert.NoError(t, err)
+		assert.Equal(t, common.BigToAddress(big.NewInt(int64(0x1000+i))), addr)
+	}
+}
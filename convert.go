@@ -0,0 +1,156 @@
+package blackholedex
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/metrics"
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrConversionFailed is returned by CollectFees and Withdraw when the tokens were collected
+// but converting them with ConvertTo failed; the returned amounts are the unconverted ones
+var ErrConversionFailed = errors.New("collected tokens could not be converted")
+
+// CollectOption configures CollectFees and Withdraw
+type CollectOption func(*collectOptions)
+
+type collectOptions struct {
+	convertTo   *common.Address
+	slippagePct int
+}
+
+// ConvertTo swaps the other collected token into token after collecting,
+// so the result is denominated in a single token
+// token must be WAVAX or USDC; slippagePct bounds the swap's minimum output
+func ConvertTo(token common.Address, slippagePct int) CollectOption {
+	return func(o *collectOptions) {
+		o.convertTo = &token
+		o.slippagePct = slippagePct
+	}
+}
+
+// convertCollected swaps the non-preferred amount of a WAVAX/USDC pair into the preferred token
+// amount0 is the WAVAX amount and amount1 the USDC amount, whatever the pool's token order
+// Returns the amounts after conversion (the swapped side is zero) and the swap transaction, if any
+func (b *Blackhole) convertCollected(amount0, amount1 *big.Int, opts []CollectOption) (*big.Int, *big.Int, []types.TransactionRecord, error) {
+	var o collectOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.convertTo == nil {
+		return amount0, amount1, nil, nil
+	}
+
+	wavaxAddr, _ := b.registry.GetAddress(wavax)
+	usdcAddr, _ := b.registry.GetAddress(usdc)
+
+	var tokenToSwap int
+	var fromToken common.Address
+	var swapAmount *big.Int
+	switch *o.convertTo {
+	case usdcAddr:
+		tokenToSwap, fromToken, swapAmount = 0, wavaxAddr, amount0
+	case wavaxAddr:
+		tokenToSwap, fromToken, swapAmount = 1, usdcAddr, amount1
+	default:
+		return amount0, amount1, nil, fmt.Errorf("cannot convert to %s: only WAVAX or USDC are supported", o.convertTo.Hex())
+	}
+	if swapAmount == nil || swapAmount.Sign() <= 0 {
+		return amount0, amount1, nil, nil
+	}
+
+	route, err := b.findRoute(fromToken, *o.convertTo)
+	if err != nil {
+		return amount0, amount1, nil, fmt.Errorf("failed to find swap route: %w", err)
+	}
+	poolInfo, usdcFirst, err := b.pairInfo()
+	if err != nil {
+		return amount0, amount1, nil, fmt.Errorf("failed to get pool state: %w", err)
+	}
+	// The pool price is token1 per token0, so the direction is flipped when USDC is token0
+	poolDirection := tokenToSwap
+	if usdcFirst {
+		poolDirection = 1 - tokenToSwap
+	}
+	expectedAmountOut := util.ApplyFee(expectedSwapOut(&poolInfo.AMMState, poolDirection, swapAmount), poolInfo.FeeFraction())
+
+	// The swap output is measured from the preferred token balance
+	toClient, err := b.registry.ClientByAddress(o.convertTo.Hex())
+	if err != nil {
+		return amount0, amount1, nil, fmt.Errorf("failed to get client for token %s: %w", o.convertTo.Hex(), err)
+	}
+	before, err := b.tokenBalance(toClient)
+	if err != nil {
+		return amount0, amount1, nil, err
+	}
+
+	swapTxHash, err := b.Swap(&types.SWAPExactTokensForTokensParams{
+		AmountIn:     swapAmount,
+		AmountOutMin: util.CalculateMinAmount(expectedAmountOut, o.slippagePct),
+		Routes:       []types.Route{route},
+		To:           b.myAddr,
+		Deadline:     b.txDeadline(txDeadlineOffset),
+	})
+	if err != nil {
+		return amount0, amount1, nil, fmt.Errorf("swap failed: %w", err)
+	}
+	if swapTxHash == (common.Hash{}) {
+		// Dry run: nothing was swapped
+		return amount0, amount1, nil, nil
+	}
+
+	receipt, err := b.tl.WaitForTransaction(swapTxHash)
+	if err != nil {
+		return amount0, amount1, nil, fmt.Errorf("swap transaction failed: %w", err)
+	}
+	gasCost, _ := util.ExtractGasCost(receipt)
+	records := []types.TransactionRecord{{TxHash: swapTxHash, GasCost: gasCost, Timestamp: time.Now(), Operation: "Swap"}}
+	metrics.RecordTransactions(records)
+
+	after, err := b.tokenBalance(toClient)
+	if err != nil {
+		return amount0, amount1, records, err
+	}
+	received := new(big.Int).Sub(after, before)
+
+	if tokenToSwap == 0 {
+		amount0, amount1 = big.NewInt(0), new(big.Int).Add(amount1, received)
+	} else {
+		amount0, amount1 = new(big.Int).Add(amount0, received), big.NewInt(0)
+	}
+	log.Printf("Converted %s of %s into %s of %s (tx: %s)",
+		swapAmount.String(), fromToken.Hex(), received.String(), o.convertTo.Hex(), swapTxHash.Hex())
+
+	return amount0, amount1, records, nil
+}
+
+// expectedSwapOut prices a swap of amount at the pool price, before fees
+// tokenToSwap: 0 for token0 to token1 (WAVAX to USDC in a WAVAX-first pool), 1 for token1 to token0
+func expectedSwapOut(poolState *types.AMMState, tokenToSwap int, amount *big.Int) *big.Int {
+	// price = (sqrtPrice / 2^96)^2, in USDC units per WAVAX wei
+	price := util.SqrtPriceToPrice(poolState.SqrtPrice)
+	amountFloat := new(big.Float).SetInt(amount)
+	var expected *big.Float
+	if tokenToSwap == 0 {
+		expected = new(big.Float).Mul(amountFloat, price)
+	} else {
+		expected = new(big.Float).Quo(amountFloat, price)
+	}
+	out, _ := expected.Int(nil)
+	return out
+}
+
+// tokenBalance returns the wallet's ERC20 balance of the token behind tokenClient
+func (b *Blackhole) tokenBalance(tokenClient ContractClient) (*big.Int, error) {
+	result, err := tokenClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	return result[0].(*big.Int), nil
+}
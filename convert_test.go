@@ -0,0 +1,118 @@
+package blackholedex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollectFeesConvertTo(t *testing.T) {
+	self := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+
+	nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+	nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "ownerOf":
+			return []interface{}{self}, nil
+		case "positions":
+			return mockPosition(wavaxAddr, usdcAddr), nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	nftManager.events = `[{"address":"0x00000000000000000000000000000000000000b1","event":"Collect","index":3,` +
+		`"parameter":{"tokenId":42,"recipient":"0x00000000000000000000000000000000000000aa",` +
+		`"amount0":500000000000000000,"amount1":4321000}}]`
+
+	wavaxClient := newMockContractClient(wavaxAddr)
+	wavaxClient.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "allowance" {
+			return []interface{}{big.NewInt(0)}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+
+	// The USDC balance grows by 10 USDC once the swap has been sent
+	router := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000c2"))
+	usdcClient := newMockContractClient(usdcAddr)
+	usdcClient.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method != "balanceOf" {
+			return nil, errors.New("unexpected method " + method)
+		}
+		balance := big.NewInt(100_000_000)
+		if len(router.sentMethods()) > 0 {
+			balance.Add(balance, big.NewInt(10_000_000))
+		}
+		return []interface{}{balance}, nil
+	}
+
+	// price = 2^-36 USDC units per WAVAX wei (about 14.55 USDC per WAVAX)
+	pool := newMockPairPool(new(big.Int).Rsh(util.Q96, 18), 0, wavaxAddr, usdcAddr)
+
+	b := newTestBlackhole(map[string]ContractClient{
+		nonfungiblePositionManager: nftManager,
+		wavax:                      wavaxClient,
+		usdc:                       usdcClient,
+		wavaxUsdcPair:              pool,
+		routerv2:                   router,
+	}, &mockTxListener{})
+
+	result, err := b.CollectFeesDetailed(big.NewInt(42), ConvertTo(usdcAddr, 1))
+	assert.NoError(t, err)
+	if !assert.NotNil(t, result) {
+		return
+	}
+	if assert.Len(t, result.Transactions, 2) {
+		assert.Equal(t, "CollectFees", result.Transactions[0].Operation)
+		assert.Equal(t, "Swap", result.Transactions[1].Operation)
+	}
+
+	// All fees end up in USDC: the collected 4.321 USDC plus the 10 USDC received from the swap
+	assert.Equal(t, big.NewInt(0), result.Amount0)
+	assert.Equal(t, big.NewInt(14_321_000), result.Amount1)
+
+	if assert.Equal(t, []string{"swapExactTokensForTokens"}, router.sentMethods()) {
+		args := router.sent[0].Args
+		assert.Equal(t, big.NewInt(500_000_000_000_000_000), args[0])
+		// 0.5 WAVAX at 2^-36 is 7,275,957 USDC units, less 1% slippage
+		assert.Equal(t, big.NewInt(7_203_197), args[1])
+		route := args[2].([]types.Route)[0]
+		assert.Equal(t, wavaxAddr, route.From)
+		assert.Equal(t, usdcAddr, route.To)
+		assert.Equal(t, pool.address, route.Pair)
+	}
+	assert.Equal(t, []string{"approve"}, wavaxClient.sentMethods())
+}
+
+func TestCollectFeesConvertToUnsupportedToken(t *testing.T) {
+	self := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+	nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "positions" {
+			return mockPosition(wavaxAddr, usdcAddr), nil
+		}
+		return []interface{}{self}, nil
+	}
+	nftManager.events = `[{"event":"Collect","parameter":{"amount0":1,"amount1":2}}]`
+
+	b := newTestBlackhole(map[string]ContractClient{
+		nonfungiblePositionManager: nftManager,
+		wavax:                      newMockContractClient(wavaxAddr),
+		usdc:                       newMockContractClient(usdcAddr),
+	}, &mockTxListener{})
+
+	amount0, amount1, _, err := b.CollectFees(big.NewInt(42), ConvertTo(common.HexToAddress("0xbb"), 1))
+	assert.ErrorIs(t, err, ErrConversionFailed)
+	assert.ErrorContains(t, err, "only WAVAX or USDC are supported")
+	// The collected amounts are still reported
+	assert.Equal(t, big.NewInt(1), amount0)
+	assert.Equal(t, big.NewInt(2), amount1)
+}
@@ -0,0 +1,27 @@
+package blackholedex
+
+import (
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+)
+
+// txDeadlineOffset is how long a submitted transaction stays valid
+const txDeadlineOffset = 20 * time.Minute
+
+// txDeadline returns the deadline, in Unix seconds, for a transaction valid for offset
+// The deadline is derived from the latest block timestamp, since contracts compare it
+// against block.timestamp and the local clock may lag the chain (common on VMs)
+// Falls back to the local clock if the latest block cannot be read
+func (b *Blackhole) txDeadline(offset time.Duration) *big.Int {
+	if b.logs != nil {
+		header, err := b.logs.HeaderByNumber(b.rpcContext(), nil)
+		if err == nil {
+			return new(big.Int).SetUint64(header.Time + uint64(offset.Seconds()))
+		}
+		log.Printf("Warning: failed to get latest block for deadline, using local clock: %v", err)
+	}
+	return util.DeadlineFromNow(offset)
+}
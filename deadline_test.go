@@ -0,0 +1,35 @@
+package blackholedex
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxDeadlineUsesBlockTime(t *testing.T) {
+	// The chain is an hour ahead of the local clock
+	blockTime := uint64(time.Now().Add(time.Hour).Unix())
+	b := newTestBlackhole(map[string]ContractClient{}, &mockTxListener{})
+	b.logs = &mockLogReader{
+		latest:  100,
+		headers: map[uint64]*ethtypes.Header{100: {Number: big.NewInt(100), Time: blockTime}},
+	}
+
+	deadline := b.txDeadline(txDeadlineOffset)
+	assert.Equal(t, blockTime+uint64(20*60), deadline.Uint64())
+}
+
+func TestTxDeadlineFallsBackToLocalClock(t *testing.T) {
+	b := newTestBlackhole(map[string]ContractClient{}, &mockTxListener{})
+	// No headers: HeaderByNumber fails
+	b.logs = &mockLogReader{}
+
+	before := time.Now().Add(txDeadlineOffset).Unix()
+	deadline := b.txDeadline(txDeadlineOffset).Int64()
+	after := time.Now().Add(txDeadlineOffset).Unix()
+	assert.GreaterOrEqual(t, deadline, before)
+	assert.LessOrEqual(t, deadline, after)
+}
@@ -0,0 +1,61 @@
+package blackholedex
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrDryRunUnsupported is returned in dry-run mode by operations that cannot be simulated
+// They refuse to run rather than broadcast a transaction
+var ErrDryRunUnsupported = errors.New("operation cannot be simulated in dry-run mode")
+
+// rejectDryRun returns ErrDryRunUnsupported for operation when dry-run mode is on
+func (b *Blackhole) rejectDryRun(operation string) error {
+	if b.dryRun {
+		return fmt.Errorf("%s: %w", operation, ErrDryRunUnsupported)
+	}
+	return nil
+}
+
+// approveOrSimulate calls ensureApproval, or in dry-run mode only logs the approval that would be sent
+// Only the simulated operations use it, so every other caller of ensureApproval approves for real
+func (b *Blackhole) approveOrSimulate(tokenClient ContractClient, spender common.Address, requiredAmount *big.Int) (common.Hash, error) {
+	if !b.dryRun {
+		return b.ensureApproval(tokenClient, spender, requiredAmount)
+	}
+
+	result, err := tokenClient.CallCtx(b.rpcContext(), &b.myAddr, "allowance", b.myAddr, spender)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to check allowance: %w", err)
+	}
+	if currentAllowance := result[0].(*big.Int); currentAllowance.Cmp(requiredAmount) < 0 {
+		log.Printf("[dry-run] skipping approval of %s for spender %s (current allowance %s)",
+			requiredAmount.String(), spender.Hex(), currentAllowance.String())
+	}
+	return common.Hash{}, nil
+}
+
+// simulate runs method as an eth_call from myAddr with the calldata Send would broadcast
+// Returns the decoded outputs, or the revert reason as an error
+func (b *Blackhole) simulate(client ContractClient, method string, args ...interface{}) ([]interface{}, error) {
+	outputs, err := client.CallCtx(b.rpcContext(), &b.myAddr, method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("dry run of %s would revert: %w", method, err)
+	}
+	log.Printf("[dry-run] %s on %s would succeed: %v", method, client.ContractAddress().Hex(), outputs)
+	return outputs, nil
+}
+
+// bigOutput returns outputs[i] as *big.Int, or zero if it is missing or of another type
+func bigOutput(outputs []interface{}, i int) *big.Int {
+	if i < len(outputs) {
+		if v, ok := outputs[i].(*big.Int); ok {
+			return v
+		}
+	}
+	return big.NewInt(0)
+}
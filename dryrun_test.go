@@ -0,0 +1,100 @@
+package blackholedex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDryRunDoesNotBroadcast(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+
+	token := newMockContractClient(wavaxAddr)
+	token.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "allowance" {
+			return []interface{}{big.NewInt(0)}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+
+	var simulated []string
+	router := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000c2"))
+	router.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		simulated = append(simulated, method)
+		if method == "swapExactTokensForTokens" {
+			return []interface{}{[]*big.Int{big.NewInt(100), big.NewInt(1249)}}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+
+	tl := &mockTxListener{}
+	b := newTestBlackhole(map[string]ContractClient{
+		routerv2: router,
+		wavax:    token,
+		usdc:     newMockContractClient(usdcAddr),
+	}, tl)
+	WithDryRun()(b)
+
+	params := &types.SWAPExactTokensForTokensParams{
+		AmountIn:     big.NewInt(100),
+		AmountOutMin: big.NewInt(1200),
+		Routes:       []types.Route{{Pair: common.HexToAddress("0x00000000000000000000000000000000000000c1"), From: wavaxAddr, To: usdcAddr, Concentrated: true}},
+		To:           b.myAddr,
+		Deadline:     big.NewInt(0),
+	}
+
+	t.Run("Success", func(t *testing.T) {
+		txHash, err := b.Swap(params)
+		assert.NoError(t, err)
+		assert.Equal(t, common.Hash{}, txHash)
+		assert.Equal(t, []string{"swapExactTokensForTokens"}, simulated)
+	})
+
+	t.Run("Revert", func(t *testing.T) {
+		router.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			return nil, errors.New("execution reverted: INSUFFICIENT_OUTPUT_AMOUNT")
+		}
+		txHash, err := b.Swap(params)
+		assert.ErrorContains(t, err, "INSUFFICIENT_OUTPUT_AMOUNT")
+		assert.Equal(t, common.Hash{}, txHash)
+	})
+
+	// Neither the approval nor the swap was broadcast
+	assert.Empty(t, token.sentMethods())
+	assert.Empty(t, router.sentMethods())
+	assert.Empty(t, tl.waited)
+}
+
+func TestDryRunRejectsUnsimulatedOperations(t *testing.T) {
+	nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+	router := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000c2"))
+	b := newTestBlackhole(map[string]ContractClient{
+		nonfungiblePositionManager: nftManager,
+		routerv2:                   router,
+	}, &mockTxListener{})
+	WithDryRun()(b)
+
+	_, err := b.Withdraw(big.NewInt(1), true)
+	assert.ErrorIs(t, err, ErrDryRunUnsupported)
+
+	_, _, _, err = b.CollectFees(big.NewInt(1))
+	assert.ErrorIs(t, err, ErrDryRunUnsupported)
+
+	_, err = b.RemoveLiquidity(&types.RemoveLiquidityParams{Liquidity: big.NewInt(1)}, common.Address{})
+	assert.ErrorIs(t, err, ErrDryRunUnsupported)
+
+	// Replacements are signed and broadcast directly, without a client to simulate them
+	_, err = b.SpeedUpTransaction(common.HexToHash("0x01"), 1.2)
+	assert.ErrorIs(t, err, ErrDryRunUnsupported)
+
+	_, err = b.CancelTransaction(common.HexToHash("0x01"), 1.2)
+	assert.ErrorIs(t, err, ErrDryRunUnsupported)
+
+	assert.Empty(t, nftManager.sentMethods())
+	assert.Empty(t, router.sentMethods())
+}
@@ -0,0 +1,19 @@
+package blackholedex
+
+import "errors"
+
+// Errors returned, wrapped with context, by Blackhole operations; match them with errors.Is
+var (
+	// ErrInsufficientBalance means the wallet holds less of a token than the operation needs
+	ErrInsufficientBalance = errors.New("insufficient balance")
+	// ErrNFTNotOwned means the position NFT belongs to another address
+	ErrNFTNotOwned = errors.New("NFT not owned by wallet")
+	// ErrNotStaked means the position NFT is not deposited in farming
+	ErrNotStaked = errors.New("NFT is not staked")
+	// ErrNoRoutes means a swap was requested without any route
+	ErrNoRoutes = errors.New("no routes provided")
+	// ErrInsufficientGas means paying for the operation's gas would take native AVAX below the reserve
+	ErrInsufficientGas = errors.New("insufficient native AVAX for gas")
+	// ErrClientNotFound means no contract client is registered under the name or address
+	ErrClientNotFound = errors.New("no mapped client")
+)
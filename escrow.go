@@ -0,0 +1,123 @@
+package blackholedex
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/metrics"
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	// lockWeek is the VotingEscrow lock granularity. Unlock times are rounded down to weeks
+	lockWeek = 7 * 24 * time.Hour
+	// maxLockDuration mirrors BlackTimeLibrary.MAX_LOCK_DURATION (4 years)
+	// VotingEscrow keeps MAXTIME internal, so it cannot be read from the contract
+	maxLockDuration = 4 * 365 * 24 * time.Hour
+)
+
+// PrepareLock builds CreateLockParams that respect the VotingEscrow lock limits
+// value: Amount of BLACK to lock (wei)
+// duration: Requested lock duration, rounded down to whole weeks
+// Returns an error if the rounded duration is shorter than a week or exceeds MAXTIME (4 years)
+func (b *Blackhole) PrepareLock(value *big.Int, duration time.Duration) (*types.CreateLockParams, error) {
+	if value == nil || value.Sign() <= 0 {
+		return nil, errors.New("lock value must be positive")
+	}
+
+	rounded := duration.Truncate(lockWeek)
+	if rounded < lockWeek {
+		return nil, fmt.Errorf("lock duration %v is shorter than one week", duration)
+	}
+	if rounded > maxLockDuration {
+		return nil, fmt.Errorf("lock duration %v exceeds escrow MAXTIME %v", rounded, maxLockDuration)
+	}
+
+	return &types.CreateLockParams{
+		Value:        new(big.Int).Set(value),
+		LockDuration: big.NewInt(int64(rounded / time.Second)),
+	}, nil
+}
+
+// MergeLocks consolidates the veNFT fromTokenID into toTokenID
+// Both tokens must be owned by the signer and the target lock must not be expired
+// Returns the merge transaction hash, also when it was sent but failed; its gas cost goes to the transaction metrics
+func (b *Blackhole) MergeLocks(fromTokenID, toTokenID *big.Int) (common.Hash, error) {
+	if fromTokenID == nil || toTokenID == nil {
+		return common.Hash{}, errors.New("token IDs must not be nil")
+	}
+	if fromTokenID.Cmp(toTokenID) == 0 {
+		return common.Hash{}, fmt.Errorf("cannot merge veNFT %s into itself", fromTokenID.String())
+	}
+	if err := b.rejectDryRun("MergeLocks"); err != nil {
+		return common.Hash{}, err
+	}
+
+	escrowClient, err := b.registry.Client(votingEscrow)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get VotingEscrow client: %w", err)
+	}
+
+	// Step 1: Verify ownership of both locks
+	for _, tokenID := range []*big.Int{fromTokenID, toTokenID} {
+		result, err := escrowClient.CallCtx(b.rpcContext(), &b.myAddr, "ownerOf", tokenID)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to get owner of veNFT %s: %w", tokenID.String(), err)
+		}
+		owner := result[0].(common.Address)
+		if owner != b.myAddr {
+			return common.Hash{}, fmt.Errorf("veNFT %s is owned by %s, not %s", tokenID.String(), owner.Hex(), b.myAddr.Hex())
+		}
+	}
+
+	// Step 2: Verify the target lock is still active
+	result, err := escrowClient.CallCtx(b.rpcContext(), &b.myAddr, "locked", toTokenID)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get lock of veNFT %s: %w", toTokenID.String(), err)
+	}
+	end := result[1].(*big.Int)
+	isPermanent := result[2].(bool)
+	if !isPermanent && end.Int64() <= time.Now().Unix() {
+		return common.Hash{}, fmt.Errorf("target veNFT %s lock expired at %s", toTokenID.String(), time.Unix(end.Int64(), 0).UTC().Format(time.RFC3339))
+	}
+
+	// Step 3: Merge
+	txHash, err := escrowClient.SendCtx(
+		b.rpcContext(),
+		types.Standard,
+		&b.myAddr,
+		b.privateKey,
+		"merge",
+		fromTokenID,
+		toTokenID,
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send merge transaction: %w", err)
+	}
+
+	record := &types.TransactionRecord{TxHash: txHash, Timestamp: time.Now(), Operation: "MergeLocks"}
+	receipt, err := b.tl.WaitForTransaction(txHash)
+	if err != nil {
+		return txHash, fmt.Errorf("merge transaction failed: %w", err)
+	}
+
+	gasCost, err := util.ExtractGasCost(receipt)
+	if err != nil {
+		return txHash, fmt.Errorf("failed to extract gas cost: %w", err)
+	}
+	gasPrice, _ := util.ParseReceiptUint(receipt.EffectiveGasPrice)
+	gasUsed, _ := util.ParseReceiptUint(receipt.GasUsed)
+	record.GasUsed = gasUsed.Uint64()
+	record.GasPrice = gasPrice
+	record.GasCost = gasCost
+	metrics.RecordTransactions([]types.TransactionRecord{*record})
+	log.Printf("Merged veNFT %s into %s (tx: %s, gas cost: %s wei)", fromTokenID.String(), toTokenID.String(), txHash.Hex(), gasCost.String())
+
+	return txHash, nil
+}
@@ -0,0 +1,72 @@
+package blackholedex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepareLock(t *testing.T) {
+	b := newTestBlackhole(map[string]ContractClient{}, &mockTxListener{})
+	value := big.NewInt(1_000_000_000_000_000_000)
+
+	t.Run("RoundsDownToWeek", func(t *testing.T) {
+		params, err := b.PrepareLock(value, 3*lockWeek+2*24*time.Hour)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(int64(3*lockWeek/time.Second)), params.LockDuration)
+		assert.Equal(t, value, params.Value)
+	})
+
+	t.Run("MaxDuration", func(t *testing.T) {
+		params, err := b.PrepareLock(value, maxLockDuration)
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(int64(maxLockDuration.Truncate(lockWeek)/time.Second)), params.LockDuration)
+	})
+
+	t.Run("OverMaxDuration", func(t *testing.T) {
+		_, err := b.PrepareLock(value, 5*365*24*time.Hour)
+		assert.ErrorContains(t, err, "exceeds escrow MAXTIME")
+	})
+
+	t.Run("SubWeekDuration", func(t *testing.T) {
+		_, err := b.PrepareLock(value, 6*24*time.Hour)
+		assert.ErrorContains(t, err, "shorter than one week")
+	})
+}
+
+func TestMergeLocks(t *testing.T) {
+	self := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+	owners := map[int64]common.Address{1: self, 2: self, 3: other}
+
+	escrow := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000f1"))
+	escrow.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "ownerOf":
+			return []interface{}{owners[args[0].(*big.Int).Int64()]}, nil
+		case "locked":
+			end := big.NewInt(time.Now().Add(52 * lockWeek).Unix())
+			return []interface{}{big.NewInt(1000), end, false, false}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	b := newTestBlackhole(map[string]ContractClient{votingEscrow: escrow}, &mockTxListener{})
+
+	t.Run("MergesOwnedLocks", func(t *testing.T) {
+		txHash, err := b.MergeLocks(big.NewInt(1), big.NewInt(2))
+		assert.NoError(t, err)
+		assert.NotEqual(t, common.Hash{}, txHash)
+		assert.Equal(t, []string{"merge"}, escrow.sentMethods())
+		assert.Equal(t, []interface{}{big.NewInt(1), big.NewInt(2)}, escrow.sent[0].Args)
+	})
+
+	t.Run("RejectsNonOwnedSource", func(t *testing.T) {
+		_, err := b.MergeLocks(big.NewInt(3), big.NewInt(2))
+		assert.ErrorContains(t, err, "veNFT 3 is owned by")
+		assert.Len(t, escrow.sentMethods(), 1)
+	})
+}
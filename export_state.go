@@ -0,0 +1,93 @@
+package blackholedex
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+// redacted replaces secrets in ExportState output
+const redacted = "[REDACTED]"
+
+// StateExport is the JSON document produced by ExportState
+type StateExport struct {
+	ExportedAt    time.Time              `json:"exported_at"`
+	Address       string                 `json:"address"`
+	Phase         string                 `json:"phase"`
+	ActiveNFT     string                 `json:"active_nft,omitempty"`
+	Position      *types.Position        `json:"position,omitempty"`
+	PositionError string                 `json:"position_error,omitempty"`
+	CumulativeGas string                 `json:"cumulative_gas"`
+	LastError     string                 `json:"last_error,omitempty"`
+	RecentReports []types.StrategyReport `json:"recent_reports"`
+	Config        ExportedConfig         `json:"config"`
+}
+
+// ExportedConfig is the configuration part of StateExport with secrets redacted
+type ExportedConfig struct {
+	PoolType   types.PoolType        `json:"pool_type"`
+	PrivateKey string                `json:"private_key"`
+	DryRun     bool                  `json:"dry_run"`
+	Contracts  map[string]string     `json:"contracts"`
+	Strategy   *types.StrategyConfig `json:"strategy,omitempty"`
+}
+
+// ExportState dumps the running bot's state as indented JSON for support and debugging
+// It includes the active position, phase, cumulative gas, recent reports and the
+// configuration with the private key redacted
+// Safe to call concurrently with RunAutoPositionStrategy
+func (b *Blackhole) ExportState() ([]byte, error) {
+	export := StateExport{
+		ExportedAt:    time.Now(),
+		Address:       b.myAddr.Hex(),
+		Phase:         b.CurrentPhase().String(),
+		CumulativeGas: "0",
+		RecentReports: []types.StrategyReport{},
+		Config: ExportedConfig{
+			PoolType:  b.poolType,
+			DryRun:    b.dryRun,
+			Contracts: make(map[string]string),
+		},
+	}
+	if b.privateKey != nil {
+		export.Config.PrivateKey = redacted
+	}
+
+	if nftID := b.ActiveNFT(); nftID != nil {
+		export.ActiveNFT = nftID.String()
+		position, err := b.GetPositionDetails(nftID)
+		if err != nil {
+			export.PositionError = err.Error()
+		} else {
+			export.Position = position
+		}
+	}
+	if err := b.LastError(); err != nil {
+		export.LastError = err.Error()
+	}
+
+	names := b.registry.Names()
+	sort.Strings(names)
+	for _, name := range names {
+		if addr, err := b.registry.GetAddress(name); err == nil {
+			export.Config.Contracts[name] = addr.Hex()
+		}
+	}
+
+	b.status.mu.Lock()
+	if b.status.cumulativeGas != nil {
+		export.CumulativeGas = b.status.cumulativeGas.String()
+	}
+	export.RecentReports = append(export.RecentReports, b.status.reports...)
+	export.Config.Strategy = b.status.config
+	b.status.mu.Unlock()
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal state export: %w", err)
+	}
+	return data, nil
+}
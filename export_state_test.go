@@ -0,0 +1,74 @@
+package blackholedex
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExportState(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	b := newTestBlackhole(map[string]ContractClient{
+		wavaxUsdcPair: newMockPool(big.NewInt(1), 0),
+	}, &mockTxListener{})
+	b.privateKey = key
+
+	config := types.DefaultStrategyConfig()
+	state := &types.StrategyState{
+		CurrentState:  types.ActiveMonitoring,
+		CumulativeGas: big.NewInt(123_456),
+	}
+	b.status.setConfig(config)
+	b.status.publish(state)
+	b.sendReport(nil, types.StrategyReport{
+		Timestamp: time.Now(),
+		EventType: "strategy_start",
+		Message:   "starting",
+		Phase:     &state.CurrentState,
+	})
+	// Later phase changes must not rewrite the recorded report
+	state.CurrentState = types.Halted
+
+	data, err := b.ExportState()
+	if !assert.NoError(t, err) {
+		return
+	}
+	exported := string(data)
+
+	keyHex := hex.EncodeToString(crypto.FromECDSA(key))
+	assert.False(t, strings.Contains(strings.ToLower(exported), keyHex), "private key leaked into export")
+
+	var export StateExport
+	if !assert.NoError(t, json.Unmarshal(data, &export)) {
+		return
+	}
+	assert.Equal(t, "ActiveMonitoring", export.Phase)
+	assert.Equal(t, "[REDACTED]", export.Config.PrivateKey)
+	assert.Equal(t, "123456", export.CumulativeGas)
+	assert.Equal(t, config.MonitoringInterval, export.Config.Strategy.MonitoringInterval)
+	assert.Contains(t, export.Config.Contracts, wavaxUsdcPair)
+	if assert.Len(t, export.RecentReports, 1) {
+		assert.Equal(t, "strategy_start", export.RecentReports[0].EventType)
+		assert.Equal(t, types.ActiveMonitoring, *export.RecentReports[0].Phase)
+	}
+}
+
+func TestRecentReportsBounded(t *testing.T) {
+	var s strategyStatus
+	for i := 0; i < maxRecentReports+5; i++ {
+		s.recordReport(types.StrategyReport{Message: string(rune('a' + i))})
+	}
+	assert.Len(t, s.reports, maxRecentReports)
+	assert.Equal(t, string(rune('a'+5)), s.reports[0].Message)
+}
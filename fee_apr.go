@@ -0,0 +1,180 @@
+package blackholedex
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	blockTimeSample = 1000 // Blocks used to measure the average block time
+	logScanChunk    = 2000 // Max block range per eth_getLogs request (common RPC limit)
+)
+
+// swapSample is the USDC volume swapped in a single block
+type swapSample struct {
+	block  uint64
+	volume *big.Int
+}
+
+// swapVolumeCache holds the Swap volume already scanned for a pool
+// Subsequent scans only fetch blocks from nextBlock on
+type swapVolumeCache struct {
+	nextBlock uint64
+	samples   []swapSample
+}
+
+// EstimateFeeAPR estimates the annualized fee return of the active liquidity in pool
+// It sums the USDC leg of the Swap events emitted over the window, applies the current
+// pool fee, annualizes the result and divides by the value of the active liquidity
+// Assumes token0=WAVAX and token1=USDC as elsewhere in this package
+// Returns APR as a fraction (0.25 = 25%), or error
+func (b *Blackhole) EstimateFeeAPR(pool common.Address, window time.Duration) (float64, error) {
+	if window <= 0 {
+		return 0, fmt.Errorf("window must be positive, got %s", window)
+	}
+	if b.logs == nil {
+		return 0, fmt.Errorf("no log reader configured")
+	}
+
+	poolClient, err := b.poolClient(pool)
+	if err != nil {
+		return 0, err
+	}
+
+	volume, err := b.swapVolume(poolClient, pool, window)
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := poolClient.CallCtx(b.rpcContext(), &b.myAddr, "fee")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read fee of pool %s: %w", pool.Hex(), err)
+	}
+	fee := result[0].(uint16)
+
+	state, err := readAMMState(b.rpcContext(), poolClient)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get state of pool %s: %w", pool.Hex(), err)
+	}
+
+	// Active liquidity is constant between the initialized ticks around the current price
+	amount0, amount1, err := util.CalculateTokenAmountsFromLiquidity(state.ActiveLiquidity, state.SqrtPrice, state.PreviousTick, state.NextTick)
+	if err != nil {
+		return 0, fmt.Errorf("failed to value active liquidity: %w", err)
+	}
+	liquidityValue := new(big.Float).Mul(new(big.Float).SetInt(amount0), util.SqrtPriceToPrice(state.SqrtPrice))
+	liquidityValue.Add(liquidityValue, new(big.Float).SetInt(amount1))
+	if liquidityValue.Sign() <= 0 {
+		return 0, fmt.Errorf("pool %s has no active liquidity", pool.Hex())
+	}
+
+	// Fee is in hundredths of a bip (1e-6)
+	fees := new(big.Float).Mul(new(big.Float).SetInt(volume), big.NewFloat(float64(fee)/1e6))
+	apr := new(big.Float).Quo(fees, liquidityValue)
+	apr.Mul(apr, big.NewFloat(float64(365*24*time.Hour)/float64(window)))
+
+	value, _ := apr.Float64()
+	return value, nil
+}
+
+// swapVolume returns the USDC volume swapped in pool over the window
+// Scanned blocks are cached per pool so repeated calls only fetch new blocks
+func (b *Blackhole) swapVolume(poolClient ContractClient, pool common.Address, window time.Duration) (*big.Int, error) {
+	poolABI := poolClient.Abi()
+	if poolABI == nil {
+		return nil, fmt.Errorf("no ABI for pool %s", pool.Hex())
+	}
+	swapEvent, ok := poolABI.Events["Swap"]
+	if !ok {
+		return nil, fmt.Errorf("pool ABI has no Swap event")
+	}
+
+	ctx := b.rpcContext()
+	latest, err := b.logs.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	latestBlock := latest.Number.Uint64()
+
+	fromBlock, err := b.windowStartBlock(ctx, latestBlock, latest.Time, window)
+	if err != nil {
+		return nil, err
+	}
+
+	b.swapMu.Lock()
+	defer b.swapMu.Unlock()
+
+	if b.swapCache == nil {
+		b.swapCache = make(map[common.Address]*swapVolumeCache)
+	}
+	cache, ok := b.swapCache[pool]
+	if !ok || cache.nextBlock < fromBlock {
+		cache = &swapVolumeCache{nextBlock: fromBlock}
+		b.swapCache[pool] = cache
+	}
+
+	for start := cache.nextBlock; start <= latestBlock; start += logScanChunk {
+		end := min(start+logScanChunk-1, latestBlock)
+		logs, err := b.logs.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(start),
+			ToBlock:   new(big.Int).SetUint64(end),
+			Addresses: []common.Address{pool},
+			Topics:    [][]common.Hash{{swapEvent.ID}},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get Swap logs for blocks %d-%d: %w", start, end, err)
+		}
+		for _, l := range logs {
+			values, err := poolABI.Unpack("Swap", l.Data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to decode Swap log %s: %w", l.TxHash.Hex(), err)
+			}
+			// amount1 is the USDC leg: positive into the pool, negative out of it
+			amount1 := new(big.Int).Abs(values[1].(*big.Int))
+			cache.samples = append(cache.samples, swapSample{block: l.BlockNumber, volume: amount1})
+		}
+		cache.nextBlock = end + 1
+	}
+
+	// Drop samples that fell out of the window
+	kept := cache.samples[:0]
+	volume := new(big.Int)
+	for _, s := range cache.samples {
+		if s.block >= fromBlock {
+			kept = append(kept, s)
+			volume.Add(volume, s.volume)
+		}
+	}
+	cache.samples = kept
+
+	return volume, nil
+}
+
+// windowStartBlock estimates the first block of the window ending at latestBlock
+// using the average block time over the last blockTimeSample blocks
+func (b *Blackhole) windowStartBlock(ctx context.Context, latestBlock, latestTime uint64, window time.Duration) (uint64, error) {
+	sample := min(uint64(blockTimeSample), latestBlock)
+	if sample == 0 {
+		return 0, nil
+	}
+	past, err := b.logs.HeaderByNumber(ctx, new(big.Int).SetUint64(latestBlock-sample))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get block %d: %w", latestBlock-sample, err)
+	}
+	if latestTime <= past.Time {
+		return 0, fmt.Errorf("cannot measure block time: block %d is not newer than block %d", latestBlock, latestBlock-sample)
+	}
+
+	blockTime := float64(latestTime-past.Time) / float64(sample)
+	blocks := uint64(window.Seconds() / blockTime)
+	if blocks > latestBlock {
+		return 0, nil
+	}
+	return latestBlock - blocks + 1, nil
+}
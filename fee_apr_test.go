@@ -0,0 +1,136 @@
+package blackholedex
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockLogReader serves fixed headers and filters a fixed set of logs by block range
+type mockLogReader struct {
+	headers map[uint64]*ethtypes.Header
+	latest  uint64
+	logs    []ethtypes.Log
+	queries []ethereum.FilterQuery
+}
+
+func (m *mockLogReader) HeaderByNumber(ctx context.Context, number *big.Int) (*ethtypes.Header, error) {
+	n := m.latest
+	if number != nil {
+		n = number.Uint64()
+	}
+	if h, ok := m.headers[n]; ok {
+		return h, nil
+	}
+	return nil, errors.New("mock: unknown block")
+}
+
+func (m *mockLogReader) FilterLogs(ctx context.Context, q ethereum.FilterQuery) ([]ethtypes.Log, error) {
+	m.queries = append(m.queries, q)
+	var out []ethtypes.Log
+	for _, l := range m.logs {
+		if l.BlockNumber >= q.FromBlock.Uint64() && l.BlockNumber <= q.ToBlock.Uint64() {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func swapLog(t *testing.T, poolABI *abi.ABI, block uint64, amount0, amount1 int64) ethtypes.Log {
+	data, err := poolABI.Events["Swap"].Inputs.NonIndexed().Pack(
+		big.NewInt(amount0), big.NewInt(amount1), util.Q96, big.NewInt(1), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ethtypes.Log{BlockNumber: block, Data: data}
+}
+
+func TestEstimateFeeAPR(t *testing.T) {
+	poolABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraPoolState.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	activeLiquidity := new(big.Int).Exp(big.NewInt(10), big.NewInt(12), nil)
+	pool := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000d1"))
+	pool.abi = poolABI
+	pool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "fee":
+			return []interface{}{uint16(3000)}, nil // 0.3%
+		case "safelyGetStateOfAMM":
+			// Price 1 (tick 0), active liquidity spans ticks -200..200
+			return []interface{}{
+				util.Q96, big.NewInt(0), uint16(3000), uint8(0),
+				activeLiquidity, big.NewInt(200), big.NewInt(-200),
+			}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+
+	// 2s blocks: a one hour window covers the last 1800 blocks (8201..10000)
+	logs := &mockLogReader{
+		latest: 10_000,
+		headers: map[uint64]*ethtypes.Header{
+			10_000: {Number: big.NewInt(10_000), Time: 20_000},
+			9_000:  {Number: big.NewInt(9_000), Time: 18_000},
+		},
+		logs: []ethtypes.Log{
+			swapLog(t, poolABI, 8_000, -1, 5_000_000_000), // outside the window
+			swapLog(t, poolABI, 8_500, -1, 1_000_000_000),
+			swapLog(t, poolABI, 9_500, 1, -3_000_000_000),
+		},
+	}
+
+	b := newTestBlackhole(map[string]ContractClient{"pool": pool}, &mockTxListener{})
+	b.logs = logs
+
+	apr, err := b.EstimateFeeAPR(pool.address, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// 4,000 USDC volume at 0.3% earns 12 USDC in an hour
+	amount0, amount1, err := util.CalculateTokenAmountsFromLiquidity(activeLiquidity, util.Q96, -200, 200)
+	if !assert.NoError(t, err) {
+		return
+	}
+	liquidityValue, _ := new(big.Float).SetInt(new(big.Int).Add(amount0, amount1)).Float64()
+	expected := 12_000_000 / liquidityValue * 24 * 365
+	assert.InDelta(t, expected, apr, expected*1e-9)
+	assert.Len(t, logs.queries, 1)
+	assert.Equal(t, uint64(8_201), logs.queries[0].FromBlock.Uint64())
+
+	// A later call only scans the new blocks and drops swaps that left the window
+	logs.latest = 10_500
+	logs.headers[10_500] = &ethtypes.Header{Number: big.NewInt(10_500), Time: 21_000}
+	logs.headers[9_500] = &ethtypes.Header{Number: big.NewInt(9_500), Time: 19_000}
+	logs.logs = append(logs.logs, swapLog(t, poolABI, 10_200, -1, 2_000_000_000))
+
+	apr, err = b.EstimateFeeAPR(pool.address, time.Hour)
+	if !assert.NoError(t, err) {
+		return
+	}
+	if assert.Len(t, logs.queries, 2) {
+		assert.Equal(t, uint64(10_001), logs.queries[1].FromBlock.Uint64())
+	}
+	// Window is now 8701..10500: the swap at 8500 dropped out, 9500 and 10200 remain
+	expected = 15_000_000 / liquidityValue * 24 * 365
+	assert.InDelta(t, expected, apr, expected*1e-9)
+}
+
+func TestEstimateFeeAPRInvalidWindow(t *testing.T) {
+	b := newTestBlackhole(map[string]ContractClient{}, &mockTxListener{})
+	_, err := b.EstimateFeeAPR(common.Address{}, 0)
+	assert.Error(t, err)
+}
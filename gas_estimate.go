@@ -0,0 +1,196 @@
+package blackholedex
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum"
+)
+
+// Gas units budgeted for rebalance steps that cannot be estimated before the earlier steps run
+// Mint, stake and unstake use the native reserve budgets, which include their approvals
+const (
+	withdrawGasEstimate = 400_000
+	swapGasEstimate     = 300_000 // Including the token approval
+)
+
+// EstimateRebalanceGas estimates the wei a Rebalance of the active position would cost at the node's suggested gas price
+// Unstake and withdraw are estimated with eth_estimateGas against the current chain state; swap, mint and stake
+// depend on the tokens the earlier steps release, so they use fixed gas budgets, as does any step whose estimate fails
+func (b *Blackhole) EstimateRebalanceGas() (*big.Int, error) {
+	nftTokenID := b.ActiveNFT()
+	if nftTokenID == nil {
+		return nil, fmt.Errorf("no active position to rebalance")
+	}
+	return b.estimateRebalanceGas(nftTokenID, b.poolType.PoolNonce())
+}
+
+// estimateRebalanceGas estimates the wei of rebalancing nftTokenID, staked under the incentive with nonce
+func (b *Blackhole) estimateRebalanceGas(nftTokenID, nonce *big.Int) (*big.Int, error) {
+	if b.gasPrices == nil || b.gasLimits == nil {
+		return nil, fmt.Errorf("gas estimation needs an RPC client")
+	}
+	gasPrice, err := b.gasPrices.SuggestGasPrice(b.rpcContext())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	location, err := b.GetPositionLocation(nftTokenID)
+	if err != nil {
+		return nil, err
+	}
+
+	var gasUnits uint64
+	switch location {
+	case types.InFarming:
+		gasUnits += b.estimateStep("unstake", unstakeGasEstimate, func() (ContractClient, []byte, error) {
+			return b.unstakeCalldata(nftTokenID, nonce)
+		})
+	case types.InGauge:
+		gasUnits += b.estimateStep("unstake", unstakeGasEstimate, func() (ContractClient, []byte, error) {
+			gaugeClient, err := b.registry.Client(gauge)
+			if err != nil {
+				return nil, nil, err
+			}
+			data, err := gaugeClient.Abi().Pack("withdraw", nftTokenID)
+			return gaugeClient, data, err
+		})
+	}
+	gasUnits += b.estimateStep("withdraw", withdrawGasEstimate, func() (ContractClient, []byte, error) {
+		return b.withdrawCalldata(nftTokenID)
+	})
+	gasUnits += swapGasEstimate + mintGasEstimate + stakeGasEstimate
+
+	return new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasUnits)), nil
+}
+
+// estimateStep runs eth_estimateGas for the transaction calldata builds, returning fallback when it cannot be estimated
+func (b *Blackhole) estimateStep(step string, fallback uint64, calldata func() (ContractClient, []byte, error)) uint64 {
+	client, data, err := calldata()
+	if err == nil {
+		var gas uint64
+		gas, err = b.gasLimits.EstimateGas(b.rpcContext(), ethereum.CallMsg{
+			From: b.myAddr,
+			To:   client.ContractAddress(),
+			Data: data,
+		})
+		if err == nil {
+			return gas
+		}
+	}
+	log.Printf("Using %d gas budget for %s, estimate failed: %v", fallback, step, err)
+	return fallback
+}
+
+// unstakeCalldata builds the FarmingCenter multicall Unstake sends: exitFarming, then claimReward
+func (b *Blackhole) unstakeCalldata(nftTokenID, nonce *big.Int) (ContractClient, []byte, error) {
+	farmingCenterClient, err := b.registry.Client(farmingCenter)
+	if err != nil {
+		return nil, nil, err
+	}
+	blackAddr, _ := b.registry.GetAddress(black)
+	poolAddr, _ := b.registry.GetAddress(wavaxUsdcPair)
+	incentiveKey := types.IncentiveKey{
+		RewardToken:      blackAddr,
+		BonusRewardToken: blackAddr,
+		Pool:             poolAddr,
+		Nonce:            nonce,
+	}
+
+	farmingCenterABI := farmingCenterClient.Abi()
+	exitFarmingData, err := farmingCenterABI.Pack("exitFarming", incentiveKey, nftTokenID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode exitFarming: %w", err)
+	}
+	claimRewardData, err := farmingCenterABI.Pack("claimReward", blackAddr, b.myAddr, big.NewInt(0))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode claimReward: %w", err)
+	}
+	data, err := farmingCenterABI.Pack("multicall", [][]byte{exitFarmingData, claimRewardData})
+	return farmingCenterClient, data, err
+}
+
+// withdrawCalldata builds the position manager multicall Withdraw sends for a full withdrawal:
+// decreaseLiquidity, collect and burn
+func (b *Blackhole) withdrawCalldata(nftTokenID *big.Int) (ContractClient, []byte, error) {
+	nftManagerClient, err := b.registry.Client(nonfungiblePositionManager)
+	if err != nil {
+		return nil, nil, err
+	}
+	positionsResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "positions", nftTokenID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to query position: %w", err)
+	}
+	liquidity := positionsResult[7].(*big.Int)
+
+	nftManagerABI := nftManagerClient.Abi()
+	decreaseData, err := nftManagerABI.Pack("decreaseLiquidity", &types.DecreaseLiquidityParams{
+		TokenId:    nftTokenID,
+		Liquidity:  liquidity,
+		Amount0Min: big.NewInt(0),
+		Amount1Min: big.NewInt(0),
+		Deadline:   b.txDeadline(txDeadlineOffset),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode decreaseLiquidity: %w", err)
+	}
+	maxUint128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	collectData, err := nftManagerABI.Pack("collect", &types.CollectParams{
+		TokenId:    nftTokenID,
+		Recipient:  b.myAddr,
+		Amount0Max: maxUint128,
+		Amount1Max: maxUint128,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode collect: %w", err)
+	}
+	burnData, err := nftManagerABI.Pack("burn", nftTokenID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode burn: %w", err)
+	}
+	data, err := nftManagerABI.Pack("multicall", [][]byte{decreaseData, collectData, burnData})
+	return nftManagerClient, data, err
+}
+
+// rebalanceWorthwhile reports whether nftTokenID's pending rewards cover config.MinRewardThreshold plus the
+// estimated rebalance gas, priced in BLACK, and why. Always true when MinRewardThreshold is nil
+// Estimates are logged either way; gas that cannot be estimated or priced counts as zero, and rewards that
+// cannot be read (e.g. a position not in farming) do not block the rebalance
+func (b *Blackhole) rebalanceWorthwhile(config *types.StrategyConfig, nftTokenID, nonce *big.Int) (bool, string) {
+	gasDesc := "unknown"
+	gasCost, err := b.estimateRebalanceGas(nftTokenID, nonce)
+	if err != nil {
+		log.Printf("Failed to estimate rebalance gas: %v", err)
+	} else {
+		gasDesc = gasCost.String() + " wei"
+	}
+	rewards, err := b.GetPendingRewards(nftTokenID)
+	if err != nil {
+		log.Printf("Estimated rebalance gas: %s (pending rewards unavailable: %v)", gasDesc, err)
+		return true, "pending rewards unavailable"
+	}
+
+	// Gas is paid in AVAX, which trades as WAVAX
+	gasInBlack := big.NewInt(0)
+	if gasCost != nil {
+		blackAddr, _ := b.registry.GetAddress(black)
+		wavaxAddr, _ := b.registry.GetAddress(wavax)
+		if value, err := b.valueInToken(gasCost, wavaxAddr, blackAddr); err == nil {
+			gasInBlack = value
+		} else {
+			log.Printf("Failed to price rebalance gas in BLACK: %v", err)
+		}
+	}
+	log.Printf("Estimated rebalance gas: %s (%s BLACK), pending rewards: %s BLACK", gasDesc, gasInBlack, rewards.Reward)
+
+	if config.MinRewardThreshold == nil {
+		return true, "no minimum reward set"
+	}
+	required := new(big.Int).Add(config.MinRewardThreshold, gasInBlack)
+	if rewards.Reward.Cmp(required) < 0 {
+		return false, fmt.Sprintf("pending rewards %s BLACK below the %s BLACK minimum plus %s BLACK estimated gas",
+			rewards.Reward, config.MinRewardThreshold, gasInBlack)
+	}
+	return true, fmt.Sprintf("pending rewards %s BLACK cover the %s BLACK required", rewards.Reward, required)
+}
@@ -0,0 +1,165 @@
+package blackholedex
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockGasEstimator returns a fixed estimate per target contract and fails for any other
+type mockGasEstimator struct {
+	gas   map[common.Address]uint64
+	calls []ethereum.CallMsg
+}
+
+func (m *mockGasEstimator) EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error) {
+	m.calls = append(m.calls, msg)
+	if gas, ok := m.gas[*msg.To]; ok {
+		return gas, nil
+	}
+	return 0, errors.New("execution reverted")
+}
+
+func TestEstimateRebalanceGas(t *testing.T) {
+	gasPrice := big.NewInt(25_000_000_000) // 25 gwei
+	setup := func(t *testing.T, gas map[string]uint64) (*Blackhole, *mockGasEstimator) {
+		b, clients := newRebalanceBlackhole(t)
+		estimator := &mockGasEstimator{gas: map[common.Address]uint64{}}
+		for name, units := range gas {
+			estimator.gas[*clients[name].ContractAddress()] = units
+		}
+		b.gasPrices = &mockGasPriceReader{price: gasPrice}
+		b.gasLimits = estimator
+		b.status.nftID.Store(big.NewInt(42))
+		return b, estimator
+	}
+
+	t.Run("Estimated", func(t *testing.T) {
+		b, estimator := setup(t, map[string]uint64{farmingCenter: 180_000, nonfungiblePositionManager: 250_000})
+		total, err := b.EstimateRebalanceGas()
+		if !assert.NoError(t, err) {
+			return
+		}
+		units := int64(180_000 + 250_000 + swapGasEstimate + mintGasEstimate + stakeGasEstimate)
+		assert.Equal(t, new(big.Int).Mul(gasPrice, big.NewInt(units)), total)
+
+		// Both estimates are multicalls sent from the wallet
+		if assert.Len(t, estimator.calls, 2) {
+			for _, msg := range estimator.calls {
+				assert.Equal(t, b.myAddr, msg.From)
+				client, err := b.registry.Client(farmingCenter)
+				if *msg.To != *client.ContractAddress() {
+					client, err = b.registry.Client(nonfungiblePositionManager)
+				}
+				if !assert.NoError(t, err) {
+					continue
+				}
+				method, err := client.Abi().MethodById(msg.Data[:4])
+				if assert.NoError(t, err) {
+					assert.Equal(t, "multicall", method.Name)
+				}
+			}
+		}
+	})
+
+	t.Run("RevertingStepUsesBudget", func(t *testing.T) {
+		b, _ := setup(t, map[string]uint64{farmingCenter: 180_000})
+		total, err := b.EstimateRebalanceGas()
+		if !assert.NoError(t, err) {
+			return
+		}
+		units := int64(180_000 + withdrawGasEstimate + swapGasEstimate + mintGasEstimate + stakeGasEstimate)
+		assert.Equal(t, new(big.Int).Mul(gasPrice, big.NewInt(units)), total)
+	})
+
+	t.Run("NoActivePosition", func(t *testing.T) {
+		b, _ := setup(t, nil)
+		b.status.nftID.Store(nil)
+		_, err := b.EstimateRebalanceGas()
+		assert.Error(t, err)
+	})
+}
+
+func TestRebalanceSkippedBelowMinReward(t *testing.T) {
+	b, clients := newRebalanceBlackhole(t)
+	// The wallet holds NFT 42 over [-400, 400], loaded in ActiveMonitoring
+	nftManager := clients[nonfungiblePositionManager]
+	positions := nftManager.callFn
+	nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "balanceOf":
+			return []interface{}{big.NewInt(1)}, nil
+		case "tokenOfOwnerByIndex":
+			return []interface{}{big.NewInt(42)}, nil
+		}
+		return positions(method, args...)
+	}
+	// The price has moved out of the range
+	pool := clients[wavaxUsdcPair]
+	poolState := pool.callFn
+	pool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		outputs, err := poolState(method, args...)
+		if method == "safelyGetStateOfAMM" && err == nil {
+			outputs[0] = util.TickToSqrtPriceX96(1000)
+			outputs[1] = big.NewInt(1000)
+		}
+		return outputs, err
+	}
+	// 1 BLACK pending
+	blackAddr := common.HexToAddress("0x00000000000000000000000000000000000000b2")
+	clients[farmingCenter].callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "deposits":
+			return []interface{}{[32]byte{1}}, nil
+		case "incentiveKeys":
+			return []interface{}{blackAddr, blackAddr, *pool.ContractAddress(), big.NewInt(1)}, nil
+		case "collectRewards":
+			return []interface{}{big.NewInt(1_000_000_000_000_000_000), big.NewInt(0)}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	b.tickPeriod = 10 * time.Millisecond
+
+	config := types.DefaultStrategyConfig()
+	config.CodeHashCheckInterval = 0
+	config.MinRewardThreshold = new(big.Int).Mul(big.NewInt(10), big.NewInt(1_000_000_000_000_000_000))
+
+	reports := make(chan string, 100)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() {
+		done <- b.RunAutoPositionStrategy(ctx, reports, config)
+	}()
+
+	timeout := time.After(2 * time.Second)
+	for skipped := false; !skipped; {
+		select {
+		case report := <-reports:
+			assert.NotContains(t, report, `"event_type":"rebalance_start"`)
+			if strings.Contains(report, `"event_type":"rebalance_skipped"`) {
+				assert.Contains(t, report, "below the 10000000000000000000 BLACK minimum")
+				skipped = true
+			}
+		case err := <-done:
+			t.Fatalf("strategy stopped: %v", err)
+		case <-timeout:
+			t.Fatal("no rebalance_skipped report")
+		}
+	}
+	cancel()
+	<-done
+
+	// The position was left staked
+	assert.Empty(t, clients[farmingCenter].sentMethods())
+	assert.Empty(t, nftManager.sentMethods())
+}
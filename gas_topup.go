@@ -0,0 +1,95 @@
+package blackholedex
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// UnwrapWAVAX converts WAVAX back to native AVAX through WAVAX.withdraw
+// Returns the transaction hash after confirmation
+func (b *Blackhole) UnwrapWAVAX(amount *big.Int) (common.Hash, error) {
+	if amount == nil || amount.Sign() <= 0 {
+		return common.Hash{}, errors.New("unwrap amount must be positive")
+	}
+	if err := b.rejectDryRun("UnwrapWAVAX"); err != nil {
+		return common.Hash{}, err
+	}
+
+	wavaxClient, err := b.registry.Client(wavax)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get WAVAX client: %w", err)
+	}
+
+	txHash, err := wavaxClient.SendCtx(
+		b.rpcContext(),
+		types.Standard,
+		&b.myAddr,
+		b.privateKey,
+		"withdraw",
+		amount,
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send WAVAX withdraw: %w", err)
+	}
+
+	if _, err := b.tl.WaitForTransaction(txHash); err != nil {
+		return txHash, fmt.Errorf("WAVAX withdraw transaction failed: %w", err)
+	}
+	return txHash, nil
+}
+
+// ensureGasFunds unwraps config.GasTopUpAmount of WAVAX when the native balance drops below config.GasTopUpFloor
+// The unwrap is capped at the WAVAX balance. Disabled when GasTopUpFloor is nil or zero
+func (b *Blackhole) ensureGasFunds(config *types.StrategyConfig, state *types.StrategyState, reportChan chan<- string) error {
+	if config.GasTopUpFloor == nil || config.GasTopUpFloor.Sign() <= 0 {
+		return nil
+	}
+
+	nativeBalance, err := b.balances.BalanceAt(b.rpcContext(), b.myAddr, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get native AVAX balance: %w", err)
+	}
+	if nativeBalance.Cmp(config.GasTopUpFloor) >= 0 {
+		return nil
+	}
+
+	wavaxClient, err := b.registry.Client(wavax)
+	if err != nil {
+		return fmt.Errorf("failed to get WAVAX client: %w", err)
+	}
+	result, err := wavaxClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
+	if err != nil {
+		return fmt.Errorf("failed to get WAVAX balance: %w", err)
+	}
+	wavaxBalance := result[0].(*big.Int)
+
+	amount := new(big.Int).Set(config.GasTopUpAmount)
+	if amount.Cmp(wavaxBalance) > 0 {
+		amount.Set(wavaxBalance)
+	}
+	if amount.Sign() == 0 {
+		return fmt.Errorf("native AVAX balance %s below floor %s and no WAVAX to unwrap", nativeBalance, config.GasTopUpFloor)
+	}
+
+	txHash, err := b.UnwrapWAVAX(amount)
+	if err != nil {
+		return err
+	}
+	log.Printf("Gas top-up: unwrapped %s WAVAX wei (native balance was %s wei, tx: %s)", amount, nativeBalance, txHash.Hex())
+
+	b.sendReport(reportChan, types.StrategyReport{
+		Timestamp: time.Now(),
+		EventType: "gas_topup",
+		Message: fmt.Sprintf("Unwrapped %s WAVAX wei for gas (native balance %s wei below floor %s wei)",
+			amount, nativeBalance, config.GasTopUpFloor),
+		Phase: &state.CurrentState,
+	})
+	return nil
+}
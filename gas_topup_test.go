@@ -0,0 +1,79 @@
+package blackholedex
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockBalanceReader reports a fixed native balance
+type mockBalanceReader struct {
+	balance *big.Int
+}
+
+func (m *mockBalanceReader) BalanceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (*big.Int, error) {
+	return m.balance, nil
+}
+
+func TestEnsureGasFunds(t *testing.T) {
+	ether := big.NewInt(1_000_000_000_000_000_000)
+	floor := new(big.Int).Div(ether, big.NewInt(20))       // 0.05 AVAX
+	topUp := new(big.Int).Div(ether, big.NewInt(5))        // 0.2 AVAX
+	lowBalance := new(big.Int).Div(ether, big.NewInt(100)) // 0.01 AVAX
+
+	newBlackhole := func(native, wavaxBalance *big.Int) (*Blackhole, *mockContractClient) {
+		wavaxClient := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000a1"))
+		wavaxClient.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			if method == "balanceOf" {
+				return []interface{}{wavaxBalance}, nil
+			}
+			return nil, errors.New("unexpected method " + method)
+		}
+		b := newTestBlackhole(map[string]ContractClient{wavax: wavaxClient}, &mockTxListener{})
+		b.balances = &mockBalanceReader{balance: native}
+		return b, wavaxClient
+	}
+
+	config := types.DefaultStrategyConfig()
+	config.GasTopUpFloor = floor
+	config.GasTopUpAmount = topUp
+	state := &types.StrategyState{CurrentState: types.ActiveMonitoring}
+
+	t.Run("UnwrapsBelowFloor", func(t *testing.T) {
+		b, wavaxClient := newBlackhole(lowBalance, ether)
+		reportChan := make(chan string, 1)
+
+		assert.NoError(t, b.ensureGasFunds(config, state, reportChan))
+		if assert.Equal(t, []string{"withdraw"}, wavaxClient.sentMethods()) {
+			assert.Equal(t, topUp, wavaxClient.sent[0].Args[0])
+		}
+		assert.Contains(t, <-reportChan, `"event_type":"gas_topup"`)
+	})
+
+	t.Run("CappedAtWAVAXBalance", func(t *testing.T) {
+		wavaxBalance := big.NewInt(1_000)
+		b, wavaxClient := newBlackhole(lowBalance, wavaxBalance)
+
+		assert.NoError(t, b.ensureGasFunds(config, state, nil))
+		if assert.Len(t, wavaxClient.sent, 1) {
+			assert.Equal(t, wavaxBalance, wavaxClient.sent[0].Args[0])
+		}
+	})
+
+	t.Run("AboveFloor", func(t *testing.T) {
+		b, wavaxClient := newBlackhole(ether, ether)
+		assert.NoError(t, b.ensureGasFunds(config, state, nil))
+		assert.Empty(t, wavaxClient.sentMethods())
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		b, wavaxClient := newBlackhole(lowBalance, ether)
+		assert.NoError(t, b.ensureGasFunds(types.DefaultStrategyConfig(), state, nil))
+		assert.Empty(t, wavaxClient.sentMethods())
+	})
+}
@@ -0,0 +1,88 @@
+package db
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PnLSummary aggregates profitability across the snapshots recorded in a time range
+// Values are in USDC smallest units, gas in wei and rewards in BLACK token units
+type PnLSummary struct {
+	Start        time.Time // Timestamp of the earliest snapshot in range
+	End          time.Time // Timestamp of the latest snapshot in range
+	StartValue   *big.Int  // TotalValue of the earliest snapshot
+	EndValue     *big.Int  // TotalValue of the latest snapshot
+	TotalGas     *big.Int  // Gas spent between the earliest and latest snapshot
+	TotalRewards *big.Int  // Rewards collected between the earliest and latest snapshot
+	NetChange    *big.Int  // EndValue - StartValue
+}
+
+// GetNetPnL summarizes the value change, gas spent and rewards collected between start and end
+func (r *MySQLRecorder) GetNetPnL(start, end time.Time) (*PnLSummary, error) {
+	return netPnL(r.db, start, end)
+}
+
+func netPnL(db *gorm.DB, start, end time.Time) (*PnLSummary, error) {
+	records, err := snapshotsByTimeRange(db, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("no snapshots between %s and %s", start.Format(time.RFC3339), end.Format(time.RFC3339))
+	}
+
+	first, last := records[0], records[len(records)-1]
+	startValue, err := stringToBigInt(first.TotalValue)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %d total value: %w", first.ID, err)
+	}
+	endValue, err := stringToBigInt(last.TotalValue)
+	if err != nil {
+		return nil, fmt.Errorf("snapshot %d total value: %w", last.ID, err)
+	}
+
+	totalGas, err := sumCumulative(records, func(r AssetSnapshotRecord) string { return r.CumulativeGas })
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum gas: %w", err)
+	}
+	totalRewards, err := sumCumulative(records, func(r AssetSnapshotRecord) string { return r.CumulativeRewards })
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum rewards: %w", err)
+	}
+
+	return &PnLSummary{
+		Start:        first.Timestamp,
+		End:          last.Timestamp,
+		StartValue:   startValue,
+		EndValue:     endValue,
+		TotalGas:     totalGas,
+		TotalRewards: totalRewards,
+		NetChange:    new(big.Int).Sub(endValue, startValue),
+	}, nil
+}
+
+// sumCumulative totals the growth of a running counter across time-ordered records
+// A counter that decreases was reset by a strategy restart, so its new value counts in full
+func sumCumulative(records []AssetSnapshotRecord, field func(AssetSnapshotRecord) string) (*big.Int, error) {
+	total := big.NewInt(0)
+	var prev *big.Int
+	for _, record := range records {
+		value, err := stringToBigInt(field(record))
+		if err != nil {
+			return nil, fmt.Errorf("snapshot %d: %w", record.ID, err)
+		}
+		switch {
+		case prev == nil:
+			// The earliest snapshot is the baseline
+		case value.Cmp(prev) >= 0:
+			total.Add(total, new(big.Int).Sub(value, prev))
+		default:
+			total.Add(total, value)
+		}
+		prev = value
+	}
+	return total, nil
+}
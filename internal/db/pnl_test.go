@@ -0,0 +1,74 @@
+package db
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+func TestMySQLRecorder_GetNetPnL(t *testing.T) {
+	// Seed an in-memory SQLite database and query it through MySQLRecorder, which shares the schema
+	sqliteRecorder, err := NewSQLiteRecorder(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	defer sqliteRecorder.Close()
+	recorder := &MySQLRecorder{db: sqliteRecorder.GetDB()}
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	seeds := []struct {
+		offset  time.Duration
+		value   int64
+		gas     int64
+		rewards int64
+	}{
+		{-time.Hour, 900, 0, 0},     // before the range
+		{0, 1_000, 100, 10},         // earliest in range (baseline)
+		{time.Hour, 1_050, 250, 40}, // +150 gas, +30 rewards
+		{2 * time.Hour, 990, 20, 5}, // strategy restarted: counters reset, +20 gas, +5 rewards
+		{3 * time.Hour, 1_200, 70, 25},
+	}
+	for _, seed := range seeds {
+		err := sqliteRecorder.RecordReport(types.CurrentAssetSnapshot{
+			Timestamp:         base.Add(seed.offset),
+			CurrentState:      types.ActiveMonitoring,
+			TotalValue:        big.NewInt(seed.value),
+			CumulativeGas:     big.NewInt(seed.gas),
+			CumulativeRewards: big.NewInt(seed.rewards),
+		})
+		if err != nil {
+			t.Fatalf("RecordReport failed: %v", err)
+		}
+	}
+
+	summary, err := recorder.GetNetPnL(base, base.Add(3*time.Hour))
+	if err != nil {
+		t.Fatalf("GetNetPnL failed: %v", err)
+	}
+
+	checks := []struct {
+		name string
+		got  *big.Int
+		want int64
+	}{
+		{"StartValue", summary.StartValue, 1_000},
+		{"EndValue", summary.EndValue, 1_200},
+		{"TotalGas", summary.TotalGas, 150 + 20 + 50},
+		{"TotalRewards", summary.TotalRewards, 30 + 5 + 20},
+		{"NetChange", summary.NetChange, 200},
+	}
+	for _, c := range checks {
+		if c.got.Cmp(big.NewInt(c.want)) != 0 {
+			t.Errorf("%s = %s, want %d", c.name, c.got, c.want)
+		}
+	}
+	if !summary.Start.Equal(base) || !summary.End.Equal(base.Add(3*time.Hour)) {
+		t.Errorf("range = %v..%v, want %v..%v", summary.Start, summary.End, base, base.Add(3*time.Hour))
+	}
+
+	if _, err := recorder.GetNetPnL(base.Add(10*time.Hour), base.Add(11*time.Hour)); err == nil {
+		t.Errorf("expected error for a range without snapshots")
+	}
+}
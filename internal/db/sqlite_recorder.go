@@ -0,0 +1,93 @@
+package db
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// SQLiteRecorder implements TransactionRecorder interface using GORM and SQLite
+// Intended for local runs and tests where a MySQL server is not available
+type SQLiteRecorder struct {
+	db *gorm.DB
+}
+
+// NewSQLiteRecorder creates a new SQLiteRecorder backed by the database file at path
+// Use ":memory:" for a throwaway in-memory database
+func NewSQLiteRecorder(path string) (*SQLiteRecorder, error) {
+	db, err := gorm.Open(sqlite.Open(path), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Warn),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open SQLite database: %w", err)
+	}
+
+	// An in-memory database lives only as long as its connection
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get underlying DB: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+
+	// Auto migrate the schema
+	// varchar(78) columns get TEXT affinity in SQLite, so big.Int strings are stored unchanged
+	if err := db.AutoMigrate(&AssetSnapshotRecord{}); err != nil {
+		return nil, fmt.Errorf("failed to migrate schema: %w", err)
+	}
+
+	return &SQLiteRecorder{db: db}, nil
+}
+
+// RecordReport implements TransactionRecorder interface
+// Timestamps are stored in UTC since SQLite compares them as text
+func (r *SQLiteRecorder) RecordReport(snapshot types.CurrentAssetSnapshot) error {
+	snapshot.Timestamp = snapshot.Timestamp.UTC()
+	return recordSnapshot(r.db, snapshot)
+}
+
+// GetDB returns the underlying GORM DB instance for advanced queries
+func (r *SQLiteRecorder) GetDB() *gorm.DB {
+	return r.db
+}
+
+// Close closes the database connection
+func (r *SQLiteRecorder) Close() error {
+	return closeDB(r.db)
+}
+
+// GetLatestSnapshot retrieves the most recent snapshot from the database
+func (r *SQLiteRecorder) GetLatestSnapshot() (*AssetSnapshotRecord, error) {
+	return latestSnapshot(r.db)
+}
+
+// GetSnapshotsByTimeRange retrieves snapshots within a time range
+func (r *SQLiteRecorder) GetSnapshotsByTimeRange(start, end time.Time) ([]AssetSnapshotRecord, error) {
+	return snapshotsByTimeRange(r.db, start.UTC(), end.UTC())
+}
+
+// GetSnapshotsByPhase retrieves all snapshots for a specific strategy phase
+func (r *SQLiteRecorder) GetSnapshotsByPhase(phase types.StrategyPhase) ([]AssetSnapshotRecord, error) {
+	return snapshotsByPhase(r.db, phase)
+}
+
+// GetActivePositionAt returns the position recorded by the latest snapshot at or before t
+// tokenID is nil when no position was open at that snapshot
+func (r *SQLiteRecorder) GetActivePositionAt(t time.Time) (tokenID *big.Int, tickLower, tickUpper int32, err error) {
+	return activePositionAt(r.db, t.UTC())
+}
+
+// CountSnapshots returns the total number of snapshots in the database
+func (r *SQLiteRecorder) CountSnapshots() (int64, error) {
+	return countSnapshots(r.db)
+}
+
+// GetNetPnL summarizes the value change, gas spent and rewards collected between start and end
+func (r *SQLiteRecorder) GetNetPnL(start, end time.Time) (*PnLSummary, error) {
+	return netPnL(r.db, start.UTC(), end.UTC())
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+func TestSQLiteRecorder_RecordAndRead(t *testing.T) {
+	recorder, err := NewSQLiteRecorder(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	defer recorder.Close()
+
+	// Larger than int64 to make sure big.Int columns keep every digit
+	totalValue, _ := new(big.Int).SetString("123456789012345678901234567890", 10)
+	now := time.Now().Truncate(time.Second)
+	snapshot := types.CurrentAssetSnapshot{
+		Timestamp:     now,
+		CurrentState:  types.ActiveMonitoring,
+		TotalValue:    totalValue,
+		EstimatedAvax: big.NewInt(42),
+		AmountWavax:   big.NewInt(500000),
+		AmountUsdc:    big.NewInt(300000),
+		AmountBlack:   big.NewInt(150000),
+		AmountAvax:    big.NewInt(50000),
+	}
+
+	if err := recorder.RecordReport(snapshot); err != nil {
+		t.Fatalf("RecordReport failed: %v", err)
+	}
+
+	latest, err := recorder.GetLatestSnapshot()
+	if err != nil {
+		t.Fatalf("GetLatestSnapshot failed: %v", err)
+	}
+	if latest.TotalValue != totalValue.String() {
+		t.Errorf("TotalValue = %s, want %s", latest.TotalValue, totalValue.String())
+	}
+	if latest.AmountUsdc != "300000" {
+		t.Errorf("AmountUsdc = %s, want 300000", latest.AmountUsdc)
+	}
+	if latest.CurrentState != int(types.ActiveMonitoring) {
+		t.Errorf("CurrentState = %d, want %d", latest.CurrentState, types.ActiveMonitoring)
+	}
+	if !latest.Timestamp.Equal(now) {
+		t.Errorf("Timestamp = %v, want %v", latest.Timestamp, now)
+	}
+
+	inRange, err := recorder.GetSnapshotsByTimeRange(now.Add(-time.Minute), now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("GetSnapshotsByTimeRange failed: %v", err)
+	}
+	if len(inRange) != 1 {
+		t.Errorf("GetSnapshotsByTimeRange returned %d snapshots, want 1", len(inRange))
+	}
+
+	byPhase, err := recorder.GetSnapshotsByPhase(types.Halted)
+	if err != nil {
+		t.Fatalf("GetSnapshotsByPhase failed: %v", err)
+	}
+	if len(byPhase) != 0 {
+		t.Errorf("GetSnapshotsByPhase(Halted) returned %d snapshots, want 0", len(byPhase))
+	}
+
+	count, err := recorder.CountSnapshots()
+	if err != nil {
+		t.Fatalf("CountSnapshots failed: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("CountSnapshots = %d, want 1", count)
+	}
+}
+
+func TestSQLiteRecorder_GetActivePositionAt(t *testing.T) {
+	recorder, err := NewSQLiteRecorder(":memory:")
+	if err != nil {
+		t.Fatalf("failed to create recorder: %v", err)
+	}
+	defer recorder.Close()
+
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	seeds := []struct {
+		offset    time.Duration
+		tokenID   *big.Int
+		tickLower int32
+		tickUpper int32
+	}{
+		{0, big.NewInt(101), -251200, -250800},
+		{time.Hour, big.NewInt(101), -251200, -250800},
+		{2 * time.Hour, nil, 0, 0}, // withdrawn, waiting for stability
+		{3 * time.Hour, big.NewInt(102), -250600, -250200},
+	}
+	for _, seed := range seeds {
+		err := recorder.RecordReport(types.CurrentAssetSnapshot{
+			Timestamp:    base.Add(seed.offset),
+			CurrentState: types.ActiveMonitoring,
+			NFTTokenID:   seed.tokenID,
+			TickLower:    seed.tickLower,
+			TickUpper:    seed.tickUpper,
+		})
+		if err != nil {
+			t.Fatalf("RecordReport failed: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		tokenID   *big.Int
+		tickLower int32
+		tickUpper int32
+	}{
+		{"between snapshots", base.Add(90 * time.Minute), big.NewInt(101), -251200, -250800},
+		{"no open position", base.Add(150 * time.Minute), nil, 0, 0},
+		{"exactly at snapshot", base.Add(3 * time.Hour), big.NewInt(102), -250600, -250200},
+		{"after last snapshot", base.Add(24 * time.Hour), big.NewInt(102), -250600, -250200},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokenID, tickLower, tickUpper, err := recorder.GetActivePositionAt(tt.at)
+			if err != nil {
+				t.Fatalf("GetActivePositionAt failed: %v", err)
+			}
+			if (tokenID == nil) != (tt.tokenID == nil) || (tokenID != nil && tokenID.Cmp(tt.tokenID) != 0) {
+				t.Errorf("tokenID = %v, want %v", tokenID, tt.tokenID)
+			}
+			if tickLower != tt.tickLower || tickUpper != tt.tickUpper {
+				t.Errorf("ticks = [%d, %d], want [%d, %d]", tickLower, tickUpper, tt.tickLower, tt.tickUpper)
+			}
+		})
+	}
+
+	if _, _, _, err := recorder.GetActivePositionAt(base.Add(-time.Minute)); err == nil {
+		t.Errorf("expected error before the first snapshot")
+	}
+}
@@ -23,8 +23,15 @@ type AssetSnapshotRecord struct {
 	AmountUsdc    string    `gorm:"type:varchar(78);not null;comment:big.Int as string"`
 	AmountBlack   string    `gorm:"type:varchar(78);not null;comment:big.Int as string"`
 	AmountAvax    string    `gorm:"type:varchar(78);not null;comment:big.Int as string"`
-	CreatedAt     time.Time `gorm:"autoCreateTime"`
-	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+	// Running totals since strategy start; they restart from zero when the strategy restarts
+	CumulativeGas     string `gorm:"type:varchar(78);not null;default:'0';comment:big.Int as string - gas spent in wei"`
+	CumulativeRewards string `gorm:"type:varchar(78);not null;default:'0';comment:big.Int as string - BLACK rewards"`
+	// Active position at snapshot time; NFTTokenID is empty when no position was open
+	NFTTokenID string    `gorm:"type:varchar(78);not null;default:'';comment:big.Int as string - active position NFT"`
+	TickLower  int32     `gorm:"not null;default:0"`
+	TickUpper  int32     `gorm:"not null;default:0"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
 }
 
 // TableName specifies the table name for GORM
@@ -67,6 +74,11 @@ func NewMySQLRecorderWithDB(db *gorm.DB) (*MySQLRecorder, error) {
 
 // RecordReport implements TransactionRecorder interface
 func (r *MySQLRecorder) RecordReport(snapshot types.CurrentAssetSnapshot) error {
+	return recordSnapshot(r.db, snapshot)
+}
+
+// recordSnapshot inserts snapshot as an AssetSnapshotRecord
+func recordSnapshot(db *gorm.DB, snapshot types.CurrentAssetSnapshot) error {
 	record := AssetSnapshotRecord{
 		Timestamp:     snapshot.Timestamp,
 		CurrentState:  int(snapshot.CurrentState),
@@ -76,9 +88,18 @@ func (r *MySQLRecorder) RecordReport(snapshot types.CurrentAssetSnapshot) error
 		AmountUsdc:    bigIntToString(snapshot.AmountUsdc),
 		AmountBlack:   bigIntToString(snapshot.AmountBlack),
 		AmountAvax:    bigIntToString(snapshot.AmountAvax),
+
+		CumulativeGas:     bigIntToString(snapshot.CumulativeGas),
+		CumulativeRewards: bigIntToString(snapshot.CumulativeRewards),
+
+		TickLower: snapshot.TickLower,
+		TickUpper: snapshot.TickUpper,
+	}
+	if snapshot.NFTTokenID != nil {
+		record.NFTTokenID = snapshot.NFTTokenID.String()
 	}
 
-	result := r.db.Create(&record)
+	result := db.Create(&record)
 	if result.Error != nil {
 		return fmt.Errorf("failed to record snapshot: %w", result.Error)
 	}
@@ -93,7 +114,12 @@ func (r *MySQLRecorder) GetDB() *gorm.DB {
 
 // Close closes the database connection
 func (r *MySQLRecorder) Close() error {
-	sqlDB, err := r.db.DB()
+	return closeDB(r.db)
+}
+
+// closeDB closes the connection underlying db
+func closeDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
 	if err != nil {
 		return fmt.Errorf("failed to get underlying DB: %w", err)
 	}
@@ -108,10 +134,27 @@ func bigIntToString(value *big.Int) string {
 	return value.String()
 }
 
+// stringToBigInt parses a big.Int column written by bigIntToString
+// Empty values (rows created before the column existed) parse as zero
+func stringToBigInt(value string) (*big.Int, error) {
+	if value == "" {
+		return big.NewInt(0), nil
+	}
+	v, ok := new(big.Int).SetString(value, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid big.Int value %q", value)
+	}
+	return v, nil
+}
+
 // GetLatestSnapshot retrieves the most recent snapshot from the database
 func (r *MySQLRecorder) GetLatestSnapshot() (*AssetSnapshotRecord, error) {
+	return latestSnapshot(r.db)
+}
+
+func latestSnapshot(db *gorm.DB) (*AssetSnapshotRecord, error) {
 	var record AssetSnapshotRecord
-	result := r.db.Order("timestamp DESC").First(&record)
+	result := db.Order("timestamp DESC").First(&record)
 	if result.Error != nil {
 		return nil, fmt.Errorf("failed to get latest snapshot: %w", result.Error)
 	}
@@ -120,8 +163,12 @@ func (r *MySQLRecorder) GetLatestSnapshot() (*AssetSnapshotRecord, error) {
 
 // GetSnapshotsByTimeRange retrieves snapshots within a time range
 func (r *MySQLRecorder) GetSnapshotsByTimeRange(start, end time.Time) ([]AssetSnapshotRecord, error) {
+	return snapshotsByTimeRange(r.db, start, end)
+}
+
+func snapshotsByTimeRange(db *gorm.DB, start, end time.Time) ([]AssetSnapshotRecord, error) {
 	var records []AssetSnapshotRecord
-	result := r.db.Where("timestamp BETWEEN ? AND ?", start, end).
+	result := db.Where("timestamp BETWEEN ? AND ?", start, end).
 		Order("timestamp ASC").
 		Find(&records)
 	if result.Error != nil {
@@ -132,8 +179,12 @@ func (r *MySQLRecorder) GetSnapshotsByTimeRange(start, end time.Time) ([]AssetSn
 
 // GetSnapshotsByPhase retrieves all snapshots for a specific strategy phase
 func (r *MySQLRecorder) GetSnapshotsByPhase(phase types.StrategyPhase) ([]AssetSnapshotRecord, error) {
+	return snapshotsByPhase(r.db, phase)
+}
+
+func snapshotsByPhase(db *gorm.DB, phase types.StrategyPhase) ([]AssetSnapshotRecord, error) {
 	var records []AssetSnapshotRecord
-	result := r.db.Where("current_state = ?", int(phase)).
+	result := db.Where("current_state = ?", int(phase)).
 		Order("timestamp ASC").
 		Find(&records)
 	if result.Error != nil {
@@ -142,10 +193,36 @@ func (r *MySQLRecorder) GetSnapshotsByPhase(phase types.StrategyPhase) ([]AssetS
 	return records, nil
 }
 
+// GetActivePositionAt returns the position recorded by the latest snapshot at or before t
+// tokenID is nil when no position was open at that snapshot
+func (r *MySQLRecorder) GetActivePositionAt(t time.Time) (tokenID *big.Int, tickLower, tickUpper int32, err error) {
+	return activePositionAt(r.db, t)
+}
+
+func activePositionAt(db *gorm.DB, t time.Time) (*big.Int, int32, int32, error) {
+	var record AssetSnapshotRecord
+	result := db.Where("timestamp <= ?", t).Order("timestamp DESC").First(&record)
+	if result.Error != nil {
+		return nil, 0, 0, fmt.Errorf("failed to get snapshot before %s: %w", t.Format(time.RFC3339), result.Error)
+	}
+	if record.NFTTokenID == "" {
+		return nil, 0, 0, nil
+	}
+	tokenID, err := stringToBigInt(record.NFTTokenID)
+	if err != nil {
+		return nil, 0, 0, fmt.Errorf("snapshot %d NFT token ID: %w", record.ID, err)
+	}
+	return tokenID, record.TickLower, record.TickUpper, nil
+}
+
 // CountSnapshots returns the total number of snapshots in the database
 func (r *MySQLRecorder) CountSnapshots() (int64, error) {
+	return countSnapshots(r.db)
+}
+
+func countSnapshots(db *gorm.DB) (int64, error) {
 	var count int64
-	result := r.db.Model(&AssetSnapshotRecord{}).Count(&count)
+	result := db.Model(&AssetSnapshotRecord{}).Count(&count)
 	if result.Error != nil {
 		return 0, fmt.Errorf("failed to count snapshots: %w", result.Error)
 	}
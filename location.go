@@ -0,0 +1,73 @@
+package blackholedex
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetPositionLocation reports where the position NFT is held, so recovery can pick the unstake path
+// A gauge deposit transfers the NFT to the gauge, while farming leaves it in the wallet with a FarmingCenter deposit
+// Fails with ErrNFTNotOwned when the NFT is held by anyone else
+func (b *Blackhole) GetPositionLocation(nftTokenID *big.Int) (types.PositionLocation, error) {
+	if nftTokenID == nil || nftTokenID.Sign() <= 0 {
+		return 0, fmt.Errorf("validation failed: invalid token ID")
+	}
+	nftManagerClient, err := b.registry.Client(nonfungiblePositionManager)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get NFT manager client: %w", err)
+	}
+	farmingCenterClient, err := b.registry.Client(farmingCenter)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get FarmingCenter client: %w", err)
+	}
+
+	ownerResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "ownerOf", nftTokenID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to verify NFT ownership: %w", err)
+	}
+	owner := ownerResult[0].(common.Address)
+
+	if gaugeAddr, err := b.registry.GetAddress(gauge); err == nil && owner == gaugeAddr {
+		return types.InGauge, nil
+	}
+	if owner == *farmingCenterClient.ContractAddress() {
+		return types.InFarming, nil
+	}
+	if owner != b.myAddr {
+		return 0, fmt.Errorf("NFT %s: %w: owned by %s", nftTokenID, ErrNFTNotOwned, owner.Hex())
+	}
+
+	depositsResult, err := farmingCenterClient.CallCtx(b.rpcContext(), &b.myAddr, "deposits", nftTokenID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to check farming status: %w", err)
+	}
+	if incentiveID := depositsResult[0].([32]byte); incentiveID != [32]byte{} {
+		return types.InFarming, nil
+	}
+	return types.InWallet, nil
+}
+
+// withdrawFromGauge takes a deposited NFT back from the gauge into the wallet
+// Returns the withdraw transaction, also when it was sent but failed
+func (b *Blackhole) withdrawFromGauge(nftTokenID *big.Int) ([]types.TransactionRecord, error) {
+	gaugeClient, err := b.registry.Client(gauge)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gauge client: %w", err)
+	}
+
+	// Token ID is the "amount" parameter, as in deposit
+	txHash, err := gaugeClient.SendCtx(b.rpcContext(), types.Standard, &b.myAddr, b.privateKey, "withdraw", nftTokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit gauge withdraw transaction: %w", err)
+	}
+	receipt, err := b.tl.WaitForTransaction(txHash)
+	if err != nil {
+		record := types.TransactionRecord{TxHash: txHash, Timestamp: time.Now(), Operation: "GaugeWithdraw"}
+		return []types.TransactionRecord{record}, fmt.Errorf("gauge withdraw transaction failed: %w", err)
+	}
+	return []types.TransactionRecord{receiptRecord(txHash, receipt, "GaugeWithdraw")}, nil
+}
@@ -0,0 +1,48 @@
+package blackholedex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPositionLocation(t *testing.T) {
+	self := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	other := common.HexToAddress("0x00000000000000000000000000000000000000bb")
+
+	tests := []struct {
+		name      string
+		owner     func(clients map[string]*mockContractClient) common.Address
+		incentive [32]byte
+		location  types.PositionLocation
+		err       error
+	}{
+		{"InWallet", func(map[string]*mockContractClient) common.Address { return self }, [32]byte{}, types.InWallet, nil},
+		{"Farmed", func(map[string]*mockContractClient) common.Address { return self }, [32]byte{1}, types.InFarming, nil},
+		{"HeldByFarmingCenter", func(c map[string]*mockContractClient) common.Address { return *c[farmingCenter].ContractAddress() }, [32]byte{}, types.InFarming, nil},
+		{"InGauge", func(c map[string]*mockContractClient) common.Address { return *c[gauge].ContractAddress() }, [32]byte{}, types.InGauge, nil},
+		{"NotOwned", func(map[string]*mockContractClient) common.Address { return other }, [32]byte{}, 0, ErrNFTNotOwned},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, clients := newRebalanceBlackhole(t)
+			clients[nonfungiblePositionManager].callFn = ownerOverride(clients[nonfungiblePositionManager].callFn, tt.owner(clients))
+			incentive := tt.incentive
+			clients[farmingCenter].callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+				return []interface{}{incentive}, nil
+			}
+
+			location, err := b.GetPositionLocation(big.NewInt(42))
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.location, location)
+		})
+	}
+}
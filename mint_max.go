@@ -0,0 +1,62 @@
+package blackholedex
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+// WithGasReserve keeps wavaxWei of WAVAX out of MintMax, so it stays available to unwrap for gas
+func WithGasReserve(wavaxWei *big.Int) Option {
+	return func(b *Blackhole) {
+		b.gasReserve = wavaxWei
+	}
+}
+
+// MintMax mints a position with as much of the wallet's WAVAX and USDC as the range allows
+// The budgets are the current balances, less the gas reserve for WAVAX (see WithGasReserve)
+// Otherwise behaves like Mint, including its capital utilization logging
+func (b *Blackhole) MintMax(rangeWidth int, slippagePct int) (*types.StakingResult, error) {
+	maxWAVAX, maxUSDC, err := b.mintBudget()
+	if err != nil {
+		return &types.StakingResult{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("failed to read balances: %v", err),
+		}, err
+	}
+	return b.Mint(maxWAVAX, maxUSDC, rangeWidth, slippagePct)
+}
+
+// mintBudget returns the WAVAX and USDC MintMax may spend
+func (b *Blackhole) mintBudget() (*big.Int, *big.Int, error) {
+	wavaxClient, err := b.registry.Client(wavax)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get WAVAX client: %w", err)
+	}
+	usdcClient, err := b.registry.Client(usdc)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get USDC client: %w", err)
+	}
+
+	wavaxBalance, err := b.tokenBalance(wavaxClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("WAVAX: %w", err)
+	}
+	usdcBalance, err := b.tokenBalance(usdcClient)
+	if err != nil {
+		return nil, nil, fmt.Errorf("USDC: %w", err)
+	}
+
+	maxWAVAX := new(big.Int).Set(wavaxBalance)
+	if b.gasReserve != nil {
+		maxWAVAX.Sub(maxWAVAX, b.gasReserve)
+	}
+	if maxWAVAX.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("WAVAX balance %s does not cover the gas reserve %s", wavaxBalance, b.gasReserve)
+	}
+	if usdcBalance.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("no USDC to mint with")
+	}
+	return maxWAVAX, usdcBalance, nil
+}
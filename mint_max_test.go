@@ -0,0 +1,67 @@
+package blackholedex
+
+import (
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMintMax(t *testing.T) {
+	poolABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraPoolState.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	sqrtPriceFloat := new(big.Float).Mul(new(big.Float).SetInt(util.Q96), big.NewFloat(math.Pow(1.0001, 50)))
+	sqrtPrice, _ := sqrtPriceFloat.Int(nil)
+
+	setup := func(reserve *big.Int) (*Blackhole, *mockContractClient) {
+		pool := newABIMock(common.HexToAddress("0x00000000000000000000000000000000000000d1"), poolABI, map[string][]interface{}{
+			"safelyGetStateOfAMM": {sqrtPrice, big.NewInt(100), uint16(0), uint8(0), big.NewInt(0), big.NewInt(200), big.NewInt(0)},
+			"token0":              {wavaxAddr},
+			"token1":              {usdcAddr},
+			"fee":                 {uint16(0)},
+			"tickSpacing":         {big.NewInt(200)},
+			"liquidity":           {big.NewInt(0)},
+		})
+		nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+		b := newTestBlackhole(map[string]ContractClient{
+			wavaxUsdcPair:              pool,
+			wavax:                      newMockToken(wavaxAddr, big.NewInt(3_000_000)),
+			usdc:                       newMockToken(usdcAddr, big.NewInt(1_000_000)),
+			nonfungiblePositionManager: nftManager,
+		}, &mockTxListener{})
+		WithGasReserve(reserve)(b)
+		return b, nftManager
+	}
+
+	t.Run("StaysWithinBalanceLessReserve", func(t *testing.T) {
+		b, nftManager := setup(big.NewInt(1_000_000))
+		_, err := b.MintMax(6, 5)
+		if !assert.NoError(t, err) {
+			return
+		}
+		sent := nftManager.sent
+		if !assert.Len(t, sent, 1) {
+			return
+		}
+		params := sent[0].Args[0].(*types.MintParams)
+		assert.Positive(t, params.Amount0Desired.Sign())
+		assert.LessOrEqual(t, params.Amount0Desired.Cmp(big.NewInt(2_000_000)), 0, "WAVAX above balance less reserve")
+		assert.LessOrEqual(t, params.Amount1Desired.Cmp(big.NewInt(1_000_000)), 0, "USDC above balance")
+	})
+
+	t.Run("ReserveCoversBalance", func(t *testing.T) {
+		b, nftManager := setup(big.NewInt(3_000_000))
+		result, err := b.MintMax(6, 5)
+		assert.ErrorContains(t, err, "gas reserve")
+		assert.False(t, result.Success)
+		assert.Empty(t, nftManager.sentMethods())
+	})
+}
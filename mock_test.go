@@ -0,0 +1,234 @@
+package blackholedex
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/contractclient/mock"
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// sentTx records a transaction submitted through mockContractClient
+type sentTx struct {
+	Method string
+	Value  *big.Int
+	Args   []interface{}
+}
+
+// mockContractClient is an in-memory ContractClient used by unit tests
+// Call results are served from callFn; Send calls are recorded in order
+type mockContractClient struct {
+	mu      sync.Mutex
+	address common.Address
+	abi     *abi.ABI
+	callFn  func(method string, args ...interface{}) ([]interface{}, error)
+	sendErr error
+	sent    []sentTx
+	events  string // ParseReceipt output; "[]" when empty
+}
+
+func newMockContractClient(address common.Address) *mockContractClient {
+	return &mockContractClient{address: address}
+}
+
+func (m *mockContractClient) Send(priority types.Priority, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
+	return m.SendWithValue(priority, nil, from, privateKey, method, args...)
+}
+
+func (m *mockContractClient) SendCtx(ctx context.Context, priority types.Priority, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
+	if err := ctx.Err(); err != nil {
+		return common.Hash{}, err
+	}
+	return m.Send(priority, from, privateKey, method, args...)
+}
+
+func (m *mockContractClient) SendWithValue(priority types.Priority, value *big.Int, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sendErr != nil {
+		return common.Hash{}, m.sendErr
+	}
+	m.sent = append(m.sent, sentTx{Method: method, Value: value, Args: args})
+	return common.BigToHash(big.NewInt(int64(len(m.sent)))), nil
+}
+
+func (m *mockContractClient) Call(from *common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	if m.callFn == nil {
+		return nil, fmt.Errorf("mock: unexpected call to %s", method)
+	}
+	return m.callFn(method, args...)
+}
+
+func (m *mockContractClient) CallCtx(ctx context.Context, from *common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.Call(from, method, args...)
+}
+
+func (m *mockContractClient) CallWithRetry(from *common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	return m.Call(from, method, args...)
+}
+
+func (m *mockContractClient) GetReceipt(txHash common.Hash) (*types.TxReceipt, error) {
+	return mockReceipt(txHash), nil
+}
+
+func (m *mockContractClient) ParseReceipt(receipt *types.TxReceipt) (string, error) {
+	if m.events == "" {
+		return "[]", nil
+	}
+	return m.events, nil
+}
+
+func (m *mockContractClient) DecodeLogs(receipt *types.TxReceipt) ([]types.DecodedEvent, error) {
+	events, _ := m.ParseReceipt(receipt)
+	return mock.DecodeEventsJSON(events)
+}
+
+func (m *mockContractClient) FindEvent(receipt *types.TxReceipt, eventName string) (*types.DecodedEvent, bool) {
+	events, err := m.DecodeLogs(receipt)
+	if err != nil {
+		return nil, false
+	}
+	return types.FindEvent(events, eventName)
+}
+
+func (m *mockContractClient) TransactionData(hash common.Hash) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *mockContractClient) ContractAddress() *common.Address {
+	return &m.address
+}
+
+func (m *mockContractClient) ChainId() *big.Int {
+	return big.NewInt(43114)
+}
+
+func (m *mockContractClient) DecodeTransaction(data []byte) (*types.DecodedTransaction, error) {
+	return nil, fmt.Errorf("mock: decode not supported")
+}
+
+func (m *mockContractClient) DecodeTransactionHex(hexData string) (*types.DecodedTransaction, error) {
+	return nil, fmt.Errorf("mock: decode not supported")
+}
+
+func (m *mockContractClient) DecodeByHash(txHash common.Hash) (*types.DecodedTransaction, error) {
+	return nil, fmt.Errorf("mock: decode not supported")
+}
+
+func (m *mockContractClient) Abi() *abi.ABI {
+	return m.abi
+}
+
+// sentMethods returns the names of the methods sent so far, in order
+func (m *mockContractClient) sentMethods() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	methods := make([]string, len(m.sent))
+	for i, tx := range m.sent {
+		methods[i] = tx.Method
+	}
+	return methods
+}
+
+// mockTxListener confirms every transaction immediately with a successful receipt
+type mockTxListener struct {
+	mu     sync.Mutex
+	waited []common.Hash
+}
+
+func (l *mockTxListener) WaitForTransaction(txHash common.Hash) (*types.TxReceipt, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waited = append(l.waited, txHash)
+	return mockReceipt(txHash), nil
+}
+
+func mockReceipt(txHash common.Hash) *types.TxReceipt {
+	return &types.TxReceipt{
+		TxHash:            txHash,
+		Status:            "0x1",
+		GasUsed:           "0x5208",
+		EffectiveGasPrice: "0x3b9aca00",
+	}
+}
+
+// newMockToken returns an ERC20 client holding balance for any account, with nothing approved
+func newMockToken(addr common.Address, balance *big.Int) *mockContractClient {
+	token := newMockContractClient(addr)
+	token.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "balanceOf":
+			return []interface{}{balance}, nil
+		case "allowance":
+			return []interface{}{big.NewInt(0)}, nil
+		}
+		return nil, fmt.Errorf("mock: unexpected call to %s", method)
+	}
+	return token
+}
+
+// newMockPool returns a pool client whose safelyGetStateOfAMM reports the given price and tick
+func newMockPool(sqrtPrice *big.Int, tick int64) *mockContractClient {
+	pool := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000d1"))
+	pool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method != "safelyGetStateOfAMM" {
+			return nil, fmt.Errorf("mock: unexpected call to %s", method)
+		}
+		return []interface{}{
+			sqrtPrice, big.NewInt(tick), uint16(0), uint8(0),
+			big.NewInt(0), big.NewInt(tick + 200), big.NewInt(tick - 200),
+		}, nil
+	}
+	return pool
+}
+
+// newTestBlackhole builds a Blackhole wired to the given mock clients
+func newTestBlackhole(clients map[string]ContractClient, tl TxListener) *Blackhole {
+	return &Blackhole{
+		poolType: types.CL200,
+		myAddr:   common.HexToAddress("0x00000000000000000000000000000000000000aa"),
+		tl:       tl,
+		registry: NewContractRegistry(clients),
+		// Strategy runs check for upgrades by default; contracts have no code unless a test sets one
+		codeReader: &mockCodeReader{},
+	}
+}
+
+// newMockPairPool returns a pool client that also reports its token ordering, fee, tick spacing and liquidity
+func newMockPairPool(sqrtPrice *big.Int, tick int64, token0, token1 common.Address) *mockContractClient {
+	pool := newMockPool(sqrtPrice, tick)
+	state := pool.callFn
+	pool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "token0":
+			return []interface{}{token0}, nil
+		case "token1":
+			return []interface{}{token1}, nil
+		case "fee":
+			return []interface{}{uint16(0)}, nil
+		case "tickSpacing":
+			return []interface{}{big.NewInt(200)}, nil
+		case "liquidity":
+			return []interface{}{big.NewInt(0)}, nil
+		}
+		return state(method, args...)
+	}
+	return pool
+}
+
+// mockPosition returns a positions() result for a [-400, 400] position of token0/token1 with liquidity 1000
+func mockPosition(token0, token1 common.Address) []interface{} {
+	return []interface{}{
+		big.NewInt(0), common.Address{}, token0, token1, common.Address{},
+		big.NewInt(-400), big.NewInt(400), big.NewInt(1000),
+		big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+	}
+}
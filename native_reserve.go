@@ -0,0 +1,49 @@
+package blackholedex
+
+import (
+	"fmt"
+	"math/big"
+)
+
+// defaultNativeReserve is the native AVAX (0.05) Mint, Stake and Unstake leave for later transactions
+var defaultNativeReserve = big.NewInt(50_000_000_000_000_000)
+
+// Gas units the native reserve check budgets per operation, including approvals
+const (
+	mintGasEstimate    = 800_000
+	stakeGasEstimate   = 400_000
+	unstakeGasEstimate = 500_000
+)
+
+// WithMinNativeReserve sets the native AVAX, in wei, that must remain after an operation's estimated gas
+// Mint, Stake and Unstake fail with ErrInsufficientGas before sending anything otherwise
+// Defaults to 0.05 AVAX; nil or zero disables the check
+func WithMinNativeReserve(wei *big.Int) Option {
+	return func(b *Blackhole) {
+		b.avaxFloor = wei
+	}
+}
+
+// checkNativeReserve fails with ErrInsufficientGas when gasUnits at the suggested gas price
+// would leave less native AVAX than the reserve. Skipped in dry-run mode, where nothing is sent
+func (b *Blackhole) checkNativeReserve(gasUnits uint64) error {
+	if b.avaxFloor == nil || b.avaxFloor.Sign() <= 0 || b.dryRun {
+		return nil
+	}
+
+	balance, err := b.balances.BalanceAt(b.rpcContext(), b.myAddr, nil)
+	if err != nil {
+		return fmt.Errorf("failed to get native AVAX balance: %w", err)
+	}
+	gasPrice, err := b.gasPrices.SuggestGasPrice(b.rpcContext())
+	if err != nil {
+		return fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	gasCost := new(big.Int).Mul(gasPrice, new(big.Int).SetUint64(gasUnits))
+	if remaining := new(big.Int).Sub(balance, gasCost); remaining.Cmp(b.avaxFloor) < 0 {
+		return fmt.Errorf("%w: balance %s wei, estimated gas %s wei, reserve %s wei",
+			ErrInsufficientGas, balance, gasCost, b.avaxFloor)
+	}
+	return nil
+}
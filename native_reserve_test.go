@@ -0,0 +1,60 @@
+package blackholedex
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// mockGasPriceReader suggests a fixed gas price
+type mockGasPriceReader struct {
+	price *big.Int
+}
+
+func (m *mockGasPriceReader) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return m.price, nil
+}
+
+func TestCheckNativeReserve(t *testing.T) {
+	gasPrice := big.NewInt(25_000_000_000) // 25 gwei
+	stakeGas := new(big.Int).Mul(gasPrice, big.NewInt(stakeGasEstimate))
+	// Exactly the reserve is left after the estimated gas
+	threshold := new(big.Int).Add(defaultNativeReserve, stakeGas)
+
+	setup := func(balance *big.Int) *Blackhole {
+		b := newTestBlackhole(map[string]ContractClient{}, &mockTxListener{})
+		WithMinNativeReserve(defaultNativeReserve)(b)
+		b.balances = &mockBalanceReader{balance: balance}
+		b.gasPrices = &mockGasPriceReader{price: gasPrice}
+		return b
+	}
+
+	t.Run("JustAbove", func(t *testing.T) {
+		b := setup(threshold)
+		assert.NoError(t, b.checkNativeReserve(stakeGasEstimate))
+
+		// Stake gets past the pre-flight and fails later on the missing NFT manager
+		_, err := b.Stake(big.NewInt(42))
+		assert.ErrorIs(t, err, ErrClientNotFound)
+		assert.NotErrorIs(t, err, ErrInsufficientGas)
+	})
+
+	t.Run("JustBelow", func(t *testing.T) {
+		b := setup(new(big.Int).Sub(threshold, big.NewInt(1)))
+		assert.ErrorIs(t, b.checkNativeReserve(stakeGasEstimate), ErrInsufficientGas)
+
+		result, err := b.Stake(big.NewInt(42))
+		assert.ErrorIs(t, err, ErrInsufficientGas)
+		assert.False(t, result.Success)
+		_, err = b.Unstake(big.NewInt(42), big.NewInt(0))
+		assert.ErrorIs(t, err, ErrInsufficientGas)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		b := setup(big.NewInt(0))
+		WithMinNativeReserve(nil)(b)
+		assert.NoError(t, b.checkNativeReserve(mintGasEstimate))
+	})
+}
@@ -0,0 +1,58 @@
+package blackholedex
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+// Pause stops the running strategy from starting new operations until Resume is called
+// The position is held as-is; the strategy keeps its state and reports "paused" once it takes effect
+// An operation already in progress finishes first. Safe to call concurrently with RunAutoPositionStrategy
+func (b *Blackhole) Pause() {
+	b.status.requestPause(true)
+}
+
+// Resume lets a paused strategy continue from the phase it was paused in and reports "resumed"
+// Safe to call concurrently with RunAutoPositionStrategy
+func (b *Blackhole) Resume() {
+	b.status.requestPause(false)
+}
+
+// pauseState remembers the phase a paused strategy resumes in
+type pauseState struct {
+	resumePhase types.StrategyPhase
+}
+
+// applyPauseRequest moves the strategy in or out of Paused to match the latest Pause/Resume call
+func (b *Blackhole) applyPauseRequest(state *types.StrategyState, pause *pauseState, reportChan chan<- string) {
+	paused := state.CurrentState == types.Paused
+	if b.status.pauseRequested() == paused {
+		return
+	}
+
+	if paused {
+		state.CurrentState = pause.resumePhase
+		log.Printf("Strategy resumed in %s", state.CurrentState)
+		b.sendReport(reportChan, types.StrategyReport{
+			Timestamp: time.Now(),
+			EventType: "resumed",
+			Message:   fmt.Sprintf("Strategy resumed in %s", state.CurrentState),
+			Phase:     &state.CurrentState,
+		})
+	} else {
+		pause.resumePhase = state.CurrentState
+		state.CurrentState = types.Paused
+		log.Printf("Strategy paused in %s", pause.resumePhase)
+		b.sendReport(reportChan, types.StrategyReport{
+			Timestamp:  time.Now(),
+			EventType:  "paused",
+			Message:    fmt.Sprintf("Strategy paused in %s, position held as-is", pause.resumePhase),
+			Phase:      &state.CurrentState,
+			NFTTokenID: state.NFTTokenID,
+		})
+	}
+	b.status.publish(state)
+}
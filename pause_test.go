@@ -0,0 +1,123 @@
+package blackholedex
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run with -race: Pause and Resume are called while the strategy loop is running
+func TestPauseResume(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+
+	nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+	nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "balanceOf":
+			return []interface{}{big.NewInt(1)}, nil
+		case "tokenOfOwnerByIndex":
+			return []interface{}{big.NewInt(42)}, nil
+		case "positions":
+			return mockPosition(wavaxAddr, usdcAddr), nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+
+	// The pool sits outside the loaded position's [-400, 400] range, so any monitoring tick starts a rebalance
+	var poolReads atomic.Int32
+	pool := newMockPool(util.Q96, 1000)
+	state := pool.callFn
+	pool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		poolReads.Add(1)
+		return state(method, args...)
+	}
+
+	b := newTestBlackhole(map[string]ContractClient{
+		nonfungiblePositionManager: nftManager,
+		wavax:                      newMockContractClient(wavaxAddr),
+		usdc:                       newMockContractClient(usdcAddr),
+		wavaxUsdcPair:              pool,
+	}, &mockTxListener{})
+	b.tickPeriod = 20 * time.Millisecond
+
+	reports := make(chan string, 100)
+	waitFor := func(eventType string) bool {
+		timeout := time.After(2 * time.Second)
+		for {
+			select {
+			case report := <-reports:
+				if strings.Contains(report, `"event_type":"`+eventType+`"`) {
+					return true
+				}
+				if strings.Contains(report, `"event_type":"out_of_range"`) {
+					t.Errorf("rebalance started while waiting for %s", eventType)
+					return false
+				}
+			case <-timeout:
+				t.Errorf("no %s report", eventType)
+				return false
+			}
+		}
+	}
+
+	// Pausing before the first tick takes effect as soon as the loop starts
+	b.Pause()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- b.RunAutoPositionStrategy(ctx, reports, types.DefaultStrategyConfig())
+	}()
+
+	if waitFor("paused") {
+		// Several ticks pass without reading the pool or rebalancing
+		time.Sleep(10 * b.tickPeriod)
+		assert.Equal(t, types.Paused, b.CurrentPhase())
+		assert.Zero(t, poolReads.Load())
+		select {
+		case report := <-reports:
+			t.Errorf("unexpected report while paused: %s", report)
+		default:
+		}
+	}
+
+	b.Resume()
+	if assert.True(t, waitFor("resumed")) {
+		// Monitoring continues where it left off and finds the position out of range
+		deadline := time.After(2 * time.Second)
+	wait:
+		for {
+			select {
+			case report := <-reports:
+				if strings.Contains(report, `"event_type":"out_of_range"`) {
+					break wait
+				}
+			case <-deadline:
+				t.Fatal("no out_of_range report after Resume")
+			}
+		}
+		assert.NotZero(t, poolReads.Load())
+	}
+
+	cancel()
+	// Drain reports so the loop can observe the cancellation
+	for {
+		select {
+		case <-reports:
+			continue
+		case err := <-done:
+			assert.ErrorIs(t, err, context.Canceled)
+			return
+		}
+	}
+}
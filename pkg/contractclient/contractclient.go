@@ -27,8 +27,22 @@ type ContractClient struct {
 	client          *ethclient.Client
 	chainId         *big.Int
 	defaultGasLimit *big.Int
+	gasStrategy     contracttypes.GasStrategy
+	customGas       *contracttypes.CustomGas
+	nonceManager    *NonceManager
+	txType          TxType
+	baseFeeMult     float64  // maxFeePerGas as a multiple of the latest base fee; 0 prices from the suggested gas price
+	baseFeeTip      *big.Int // maxPriorityFeePerGas used with base fee pricing
 }
 
+// TxType selects the transaction envelope built by Send
+type TxType uint8
+
+const (
+	DynamicFee TxType = iota // EIP-1559 DynamicFeeTx (default)
+	Legacy                   // Pre-EIP-1559 LegacyTx with a single gas price, for chains without EIP-1559
+)
+
 /*
 
 func (cm *EvmContractCodec) ChainId() (*big.Int, error) {
@@ -73,6 +87,44 @@ func WithDefaultGasLimit(gasLimit *big.Int) Option {
 	}
 }
 
+// WithGasStrategy selects how Send prices transactions (GasStandard by default)
+func WithGasStrategy(strategy contracttypes.GasStrategy) Option {
+	return func(cc *ContractClient) {
+		cc.gasStrategy = strategy
+	}
+}
+
+// WithCustomGas sets the gas parameters used with the GasCustom strategy
+func WithCustomGas(customGas *contracttypes.CustomGas) Option {
+	return func(cc *ContractClient) {
+		cc.customGas = customGas
+	}
+}
+
+// WithTxType selects the transaction envelope built by Send
+func WithTxType(txType TxType) Option {
+	return func(cc *ContractClient) {
+		cc.txType = txType
+	}
+}
+
+// WithBaseFeePricing prices DynamicFee transactions from the latest block's base fee
+// maxFeePerGas = baseFee * multiplier + tip, maxPriorityFeePerGas = tip
+// GasSlow and GasFast scale the tip and multiplier as in baseFeeScaling; the GasCustom strategy still takes its parameters from CustomGas
+func WithBaseFeePricing(multiplier float64, tip *big.Int) Option {
+	return func(cc *ContractClient) {
+		cc.baseFeeMult = multiplier
+		cc.baseFeeTip = tip
+	}
+}
+
+// WithNonceManager makes Send take nonces from a shared NonceManager instead of querying the node every time
+func WithNonceManager(nm *NonceManager) Option {
+	return func(cc *ContractClient) {
+		cc.nonceManager = nm
+	}
+}
+
 func (cm *ContractClient) CallWithRetry(from *common.Address, method string, args ...interface{}) (rtn []interface{}, err error) {
 	for range 5 {
 		rtn, err = cm.Call(from, method, args...)
@@ -85,6 +137,11 @@ func (cm *ContractClient) CallWithRetry(from *common.Address, method string, arg
 	return rtn, err
 }
 func (cm *ContractClient) Call(from *common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	return cm.CallCtx(context.Background(), from, method, args...)
+}
+
+// CallCtx is Call bound to ctx; cancelling ctx aborts the in-flight eth_call
+func (cm *ContractClient) CallCtx(ctx context.Context, from *common.Address, method string, args ...interface{}) ([]interface{}, error) {
 
 	if from == nil {
 		from = &common.Address{}
@@ -94,13 +151,13 @@ func (cm *ContractClient) Call(from *common.Address, method string, args ...inte
 		return nil, errors.Join(fmt.Errorf("%s Call 시, abi Pack Error", method), err)
 	}
 
-	raw, err := cm.client.CallContract(context.Background(), ethereum.CallMsg{
+	raw, err := cm.client.CallContract(ctx, ethereum.CallMsg{
 		From: *from,
 		To:   &cm.contractAddress,
 		Data: packed,
 	}, nil)
 	if err != nil {
-		return nil, errors.Join(fmt.Errorf("%s Call 시, CallContract Error", method), err)
+		return nil, errors.Join(fmt.Errorf("%s Call 시, CallContract Error", method), wrapRevert(err))
 	}
 
 	rtn, err := cm.abi.Unpack(method, raw)
@@ -112,14 +169,24 @@ func (cm *ContractClient) Call(from *common.Address, method string, args ...inte
 }
 
 func (cm *ContractClient) Send(priority contracttypes.Priority, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
-	return cm.send(priority, nil, from, privateKey, method, args...)
+	return cm.send(context.Background(), priority, nil, from, privateKey, method, args...)
 }
 
 func (cm *ContractClient) SendWithValue(priority contracttypes.Priority, value *big.Int, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
-	return cm.send(priority, value, from, privateKey, method, args...)
+	return cm.send(context.Background(), priority, value, from, privateKey, method, args...)
 }
 
-func (cm *ContractClient) send(priority contracttypes.Priority, value *big.Int, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
+// SendCtx is Send bound to ctx; cancelling ctx aborts gas estimation, nonce lookup and broadcast
+func (cm *ContractClient) SendCtx(ctx context.Context, priority contracttypes.Priority, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
+	return cm.send(ctx, priority, nil, from, privateKey, method, args...)
+}
+
+// SendWithValueCtx is SendWithValue bound to ctx
+func (cm *ContractClient) SendWithValueCtx(ctx context.Context, priority contracttypes.Priority, value *big.Int, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
+	return cm.send(ctx, priority, value, from, privateKey, method, args...)
+}
+
+func (cm *ContractClient) send(ctx context.Context, priority contracttypes.Priority, value *big.Int, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
 	if from == nil {
 		from = &common.Address{}
 	}
@@ -130,20 +197,15 @@ func (cm *ContractClient) send(priority contracttypes.Priority, value *big.Int,
 
 	fmt.Println("packed :", common.Bytes2Hex(packed))
 
-	nonce, err := cm.client.PendingNonceAt(context.Background(), *from)
-	if err != nil {
-		return common.Hash{}, errors.Join(fmt.Errorf("%s Send 시, PendingNonceAt Error", method), err)
-	}
-
 	// Get gas price and estimate gas limit
-	gasPrice, err := cm.client.SuggestGasPrice(context.Background())
+	gasPrice, err := cm.client.SuggestGasPrice(ctx)
 	if err != nil {
 		return common.Hash{}, errors.Join(fmt.Errorf("%s Send 시, SuggestGasPrice Error", method), err)
 	}
 
 	gasLimit := uint64(0)
 	// Estimate gas limit
-	gasLimit, err = cm.client.EstimateGas(context.Background(), ethereum.CallMsg{
+	gasLimit, err = cm.client.EstimateGas(ctx, ethereum.CallMsg{
 		From:  *from,
 		To:    &cm.contractAddress,
 		Data:  packed,
@@ -153,48 +215,193 @@ func (cm *ContractClient) send(priority contracttypes.Priority, value *big.Int,
 		if cm.defaultGasLimit != nil {
 			gasLimit = cm.defaultGasLimit.Uint64()
 		} else {
-			return common.Hash{}, errors.Join(fmt.Errorf("%s Send 시, EstimateGas Error", method), err)
+			return common.Hash{}, errors.Join(fmt.Errorf("%s Send 시, EstimateGas Error", method), wrapRevert(err))
 		}
 	}
 	if priority == contracttypes.High {
 		gasLimit = gasLimit * 2
 	}
 
-	// Calculate gas tip cap (priority fee) - typically 1-2 Gwei
-	gasTipCap := big.NewInt(1500000000) // 1.5 Gwei
+	var baseFee *big.Int
+	if cm.usesBaseFee(cm.gasStrategy) {
+		header, err := cm.client.HeaderByNumber(ctx, nil)
+		if err != nil {
+			return common.Hash{}, errors.Join(fmt.Errorf("%s Send 시, HeaderByNumber Error", method), err)
+		}
+		baseFee = header.BaseFee
+	}
 
-	// Calculate gas fee cap (max fee per gas) - base fee + priority fee
-	// For most networks, base fee + 2 Gwei is reasonable
-	gasFeeCap := new(big.Int).Add(gasPrice, big.NewInt(2000000000)) // base fee + 2 Gwei
 	// EIP-1559에서는 baseFee가 자동으로 소각(burn) => validator에게 별도로 주는 팁이 priorityFee(보통 2Gwei)
+	fees, err := cm.txFees(cm.gasStrategy, gasPrice, baseFee)
+	if err != nil {
+		return common.Hash{}, errors.Join(fmt.Errorf("%s Send 시, gas 설정 Error", method), err)
+	}
 
-	tx := types.NewTx(&types.DynamicFeeTx{
-		ChainID:    cm.chainId,
-		Nonce:      nonce,
-		GasTipCap:  gasTipCap, // a.k.a. maxPriorityFeePerGas
-		GasFeeCap:  gasFeeCap, // a.k.a. maxFeePerGas
-		Gas:        gasLimit,
-		To:         &cm.contractAddress,
-		Value:      value,
-		Data:       packed,
-		AccessList: nil, // Access list는 특정 컨트랙트를 호출할 때, 호출자가 접근할 컨트랙트의 주소 및 slot 키값들의 목록을 미리 저장
-	})
+	nonce, err := cm.nextNonce(ctx, *from)
+	if err != nil {
+		return common.Hash{}, errors.Join(fmt.Errorf("%s Send 시, PendingNonceAt Error", method), err)
+	}
+
+	tx := cm.newTx(nonce, gasLimit, value, packed, fees)
 
 	// Sign transaction
 	signedTx, err := types.SignTx(tx, types.LatestSignerForChainID(cm.chainId), privateKey)
 	if err != nil {
+		cm.resetNonce(*from)
 		return common.Hash{}, errors.Join(fmt.Errorf("%s Send 시, SignTx Error", method), err)
 	}
 
 	// Send transaction
-	err = cm.client.SendTransaction(context.Background(), signedTx)
+	err = cm.client.SendTransaction(ctx, signedTx)
 	if err != nil {
+		cm.resetNonce(*from) // 실패 시, 다음 Send에서 노드 기준으로 재동기화
 		return common.Hash{}, errors.Join(fmt.Errorf("%s Send 시, SendTransaction Error", method), err)
 	}
 
 	return signedTx.Hash(), nil
 }
 
+// nextNonce returns the nonce for the next transaction from the shared manager, or the node's pending nonce
+func (cm *ContractClient) nextNonce(ctx context.Context, from common.Address) (uint64, error) {
+	if cm.nonceManager != nil {
+		return cm.nonceManager.Next(ctx, from)
+	}
+	return cm.client.PendingNonceAt(ctx, from)
+}
+
+func (cm *ContractClient) resetNonce(from common.Address) {
+	if cm.nonceManager != nil {
+		cm.nonceManager.ResetNonce(from)
+	}
+}
+
+// txGas is the gas pricing of a single transaction
+// Legacy transactions use gasPrice; DynamicFee transactions use the tip and fee caps
+type txGas struct {
+	gasPrice  *big.Int
+	gasTipCap *big.Int
+	gasFeeCap *big.Int
+}
+
+// usesBaseFee reports whether Send needs the latest base fee to price a transaction
+func (cm *ContractClient) usesBaseFee(strategy contracttypes.GasStrategy) bool {
+	return cm.txType == DynamicFee && cm.baseFeeMult > 0 && strategy != contracttypes.GasCustom
+}
+
+// txFees prices a transaction for the client's tx type
+// suggested is the node's suggested gas price; baseFee is the latest base fee when usesBaseFee
+func (cm *ContractClient) txFees(strategy contracttypes.GasStrategy, suggested, baseFee *big.Int) (txGas, error) {
+	if cm.txType == Legacy {
+		if strategy == contracttypes.GasCustom && cm.customGas != nil && cm.customGas.GasPrice != nil {
+			return txGas{gasPrice: new(big.Int).Set(cm.customGas.GasPrice)}, nil
+		}
+		return txGas{gasPrice: new(big.Int).Set(suggested)}, nil
+	}
+
+	if cm.usesBaseFee(strategy) {
+		if baseFee == nil {
+			return txGas{}, errors.New("latest block has no base fee: use the Legacy tx type on chains without EIP-1559")
+		}
+		tip := cm.baseFeeTip
+		if tip == nil {
+			tip = big.NewInt(1_500_000_000) // 1.5 Gwei, as the GasStandard strategy
+		}
+		tip, mult := baseFeeScaling(strategy, tip, cm.baseFeeMult)
+		gasFeeCap, _ := new(big.Float).Mul(new(big.Float).SetInt(baseFee), big.NewFloat(mult)).Int(nil)
+		return txGas{gasTipCap: tip, gasFeeCap: gasFeeCap.Add(gasFeeCap, tip)}, nil
+	}
+
+	gasTipCap, gasFeeCap, err := gasParams(strategy, suggested, cm.customGas)
+	if err != nil {
+		return txGas{}, err
+	}
+	return txGas{gasTipCap: gasTipCap, gasFeeCap: gasFeeCap}, nil
+}
+
+// baseFeeScaling applies a gas strategy to the configured base fee pricing
+// GasSlow halves the tip and the base fee headroom (never below the base fee itself), GasFast doubles both
+func baseFeeScaling(strategy contracttypes.GasStrategy, tip *big.Int, mult float64) (*big.Int, float64) {
+	switch strategy {
+	case contracttypes.GasSlow:
+		return new(big.Int).Div(tip, big.NewInt(2)), max(mult/2, 1)
+	case contracttypes.GasFast:
+		return new(big.Int).Mul(tip, big.NewInt(2)), mult * 2
+	default:
+		return new(big.Int).Set(tip), mult
+	}
+}
+
+// newTx builds an unsigned transaction to the contract in the client's tx type
+func (cm *ContractClient) newTx(nonce, gasLimit uint64, value *big.Int, data []byte, fees txGas) *types.Transaction {
+	if cm.txType == Legacy {
+		return types.NewTx(&types.LegacyTx{
+			Nonce:    nonce,
+			GasPrice: fees.gasPrice,
+			Gas:      gasLimit,
+			To:       &cm.contractAddress,
+			Value:    value,
+			Data:     data,
+		})
+	}
+	return types.NewTx(&types.DynamicFeeTx{
+		ChainID:    cm.chainId,
+		Nonce:      nonce,
+		GasTipCap:  fees.gasTipCap, // a.k.a. maxPriorityFeePerGas
+		GasFeeCap:  fees.gasFeeCap, // a.k.a. maxFeePerGas
+		Gas:        gasLimit,
+		To:         &cm.contractAddress,
+		Value:      value,
+		Data:       data,
+		AccessList: nil, // Access list는 특정 컨트랙트를 호출할 때, 호출자가 접근할 컨트랙트의 주소 및 slot 키값들의 목록을 미리 저장
+	})
+}
+
+// gasParams translates a gas strategy into EIP-1559 tip and fee caps given the node's suggested gas price
+func gasParams(strategy contracttypes.GasStrategy, suggested *big.Int, customGas *contracttypes.CustomGas) (gasTipCap, gasFeeCap *big.Int, err error) {
+	gwei := func(n float64) *big.Int {
+		v, _ := new(big.Float).Mul(big.NewFloat(n), big.NewFloat(1e9)).Int(nil)
+		return v
+	}
+
+	switch strategy {
+	case contracttypes.GasSlow:
+		// Minimal tip; fee cap leaves little headroom for base fee increases
+		return gwei(1), new(big.Int).Add(suggested, gwei(1)), nil
+
+	case contracttypes.GasFast:
+		// Higher tip and room for the base fee to double before the tx is priced out
+		gasTipCap = gwei(3)
+		gasFeeCap = new(big.Int).Mul(suggested, big.NewInt(2))
+		return gasTipCap, gasFeeCap.Add(gasFeeCap, gasTipCap), nil
+
+	case contracttypes.GasCustom:
+		if customGas == nil {
+			return nil, nil, errors.New("GasCustom strategy requires CustomGas configuration")
+		}
+		switch {
+		case customGas.GasTipCap != nil && customGas.GasFeeCap != nil:
+			if customGas.GasTipCap.Cmp(customGas.GasFeeCap) > 0 {
+				return nil, nil, fmt.Errorf("gas tip cap %s exceeds fee cap %s", customGas.GasTipCap, customGas.GasFeeCap)
+			}
+			return new(big.Int).Set(customGas.GasTipCap), new(big.Int).Set(customGas.GasFeeCap), nil
+		case customGas.GasPrice != nil:
+			return new(big.Int).Set(customGas.GasPrice), new(big.Int).Set(customGas.GasPrice), nil
+		case customGas.Multiplier > 0:
+			gasFeeCap, _ = new(big.Float).Mul(new(big.Float).SetInt(suggested), big.NewFloat(customGas.Multiplier)).Int(nil)
+			gasTipCap = gwei(1.5)
+			if gasTipCap.Cmp(gasFeeCap) > 0 {
+				gasTipCap = new(big.Int).Set(gasFeeCap)
+			}
+			return gasTipCap, gasFeeCap, nil
+		}
+		return nil, nil, errors.New("CustomGas must set tip/fee caps, a gas price, or a multiplier")
+
+	default:
+		// GasStandard: 1.5 Gwei tip, base fee + 2 Gwei cap
+		return gwei(1.5), new(big.Int).Add(suggested, gwei(2)), nil
+	}
+}
+
 func (cm *ContractClient) unparseTxData(txData string, method string) error {
 
 	// hex to bytes
@@ -302,59 +509,109 @@ func (cm *ContractClient) ParseReceipt(receipt *contracttypes.TxReceipt) (string
 		eventInfo.Address = log.Address
 		eventInfo.Index = log.Index
 
-		var abiEvent *abi.Event
-		for _, event := range cm.abi.Events {
-			// fmt.Printf("event.ID.Hex(): %s | log.Topics[0].Hex(): %s\n", event.ID.Hex(), log.Topics[0].Hex())
-			if event.ID.Hex() == log.Topics[0].Hex() {
-				abiEvent = &event
-				break
-			}
+		abiEvent, paramMap, err := cm.decodeLog(log)
+		if err != nil {
+			return "", err
 		}
 		if abiEvent == nil {
 			continue
 		}
 
 		eventInfo.EventName = abiEvent.Name
-
-		paramMap := make(map[string]interface{})
 		eventInfo.Parameter = paramMap
 
-		err := abiEvent.Inputs.UnpackIntoMap(paramMap, log.Data)
-		if err != nil {
-			return "", err
-		}
-
-		indexed := make([]abi.Argument, len(log.Topics)-1)
+		// []byte 일 때, string 변환 추가
 		idx := 0
 		for _, input := range abiEvent.Inputs {
-			// memo. 자기 자신의 receipt일 경우에는 idx < len(indexed) 필요 없음. log.Topics이 시그니처 + indexed params로 구성되기 때문.
-			// 다만, 컨트랙트 내부에서 다른 컨트랙트 호출되어서 찍히는 로그는 제대로 파싱을 못하기에 여기서 오류가 생김
-			if input.Indexed && idx < len(indexed) {
-				indexed[idx] = input
+			if input.Indexed && idx < len(log.Topics)-1 {
+				if input.Type.T == abi.FixedBytesTy || input.Type.T == abi.BytesTy {
+					paramMap[input.Name] = log.Topics[idx+1].Hex()
+				}
 				idx++
 			}
 		}
 
-		err = abi.ParseTopicsIntoMap(paramMap, indexed, log.Topics[1:])
+	}
+
+	jsonData, err := json.Marshal(events)
+	if err != nil {
+		return "", err
+	}
+	return string(jsonData), nil
+}
+
+// DecodeLogs decodes the receipt's logs emitted by this contract into typed events, in log order
+// Logs from other contracts or with events missing from the ABI are skipped
+func (cm *ContractClient) DecodeLogs(receipt *contracttypes.TxReceipt) ([]contracttypes.DecodedEvent, error) {
+	var events []contracttypes.DecodedEvent
+	for _, log := range receipt.Logs {
+		if log.Address != cm.contractAddress {
+			continue
+		}
+		abiEvent, params, err := cm.decodeLog(log)
 		if err != nil {
-			return "", err
+			return nil, fmt.Errorf("failed to decode log %d: %w", log.Index, err)
+		}
+		if abiEvent == nil {
+			continue
 		}
+		events = append(events, contracttypes.DecodedEvent{
+			Name:    abiEvent.Name,
+			Address: log.Address,
+			Params:  params,
+		})
+	}
+	return events, nil
+}
 
-		// []byte 일 때, string 변환 추가
-		for i, input := range indexed {
-			if input.Type.T == abi.FixedBytesTy || input.Type.T == abi.BytesTy {
-				topic := log.Topics[i+1]
-				paramMap[input.Name] = topic.Hex()
-			}
+// FindEvent returns the first event named eventName that this contract emitted in receipt
+// Returns false when there is none, or when the receipt's logs cannot be decoded
+func (cm *ContractClient) FindEvent(receipt *contracttypes.TxReceipt, eventName string) (*contracttypes.DecodedEvent, bool) {
+	events, err := cm.DecodeLogs(receipt)
+	if err != nil {
+		return nil, false
+	}
+	return contracttypes.FindEvent(events, eventName)
+}
+
+// decodeLog unpacks the indexed and non-indexed parameters of log with the contract's ABI
+// Returns a nil event when the log's event is not in the ABI
+func (cm *ContractClient) decodeLog(log *types.Log) (*abi.Event, map[string]interface{}, error) {
+	if len(log.Topics) == 0 {
+		return nil, nil, nil // Anonymous event
+	}
+
+	var abiEvent *abi.Event
+	for _, event := range cm.abi.Events {
+		if event.ID == log.Topics[0] {
+			abiEvent = &event
+			break
 		}
+	}
+	if abiEvent == nil {
+		return nil, nil, nil
+	}
 
+	paramMap := make(map[string]interface{})
+	if err := abiEvent.Inputs.UnpackIntoMap(paramMap, log.Data); err != nil {
+		return nil, nil, err
 	}
 
-	jsonData, err := json.Marshal(events)
-	if err != nil {
-		return "", err
+	indexed := make([]abi.Argument, len(log.Topics)-1)
+	idx := 0
+	for _, input := range abiEvent.Inputs {
+		// memo. 자기 자신의 receipt일 경우에는 idx < len(indexed) 필요 없음. log.Topics이 시그니처 + indexed params로 구성되기 때문.
+		// 다만, 컨트랙트 내부에서 다른 컨트랙트 호출되어서 찍히는 로그는 제대로 파싱을 못하기에 여기서 오류가 생김
+		if input.Indexed && idx < len(indexed) {
+			indexed[idx] = input
+			idx++
+		}
 	}
-	return string(jsonData), nil
+
+	if err := abi.ParseTopicsIntoMap(paramMap, indexed, log.Topics[1:]); err != nil {
+		return nil, nil, err
+	}
+	return abiEvent, paramMap, nil
 }
 
 func (cm *ContractClient) ContractAddress() *common.Address {
@@ -469,10 +726,10 @@ func (cm *ContractClient) DecodeTransaction(data []byte) (*contracttypes.Decoded
 	}, nil
 }
 
-// DecodeTransactionHex decodes hex-encoded transaction data
+// DecodeTransactionHex decodes hex-encoded transaction data, with or without a 0x prefix
 func (cm *ContractClient) DecodeTransactionHex(hexData string) (*contracttypes.DecodedTransaction, error) {
 	// Remove 0x prefix if present
-	if len(hexData) >= 2 && hexData[:2] == "0x" {
+	if len(hexData) >= 2 && (hexData[:2] == "0x" || hexData[:2] == "0X") {
 		hexData = hexData[2:]
 	}
 
@@ -484,14 +741,19 @@ func (cm *ContractClient) DecodeTransactionHex(hexData string) (*contracttypes.D
 	return cm.DecodeTransaction(data)
 }
 
-// DecodeByHash fetches a transaction by hash and decodes its input data
+// DecodeByHash fetches a transaction by hash and decodes its input data, with its target, sender and value
 func (cm *ContractClient) DecodeByHash(txHash common.Hash) (*contracttypes.DecodedTransaction, error) {
 	tx, _, err := cm.client.TransactionByHash(context.Background(), txHash)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch transaction %s: %w", txHash.Hex(), err)
 	}
 
-	return cm.DecodeTransaction(tx.Data())
+	decoded, err := cm.DecodeTransaction(tx.Data())
+	if err != nil {
+		return nil, err
+	}
+	decoded.SetTransaction(tx)
+	return decoded, nil
 }
 
 /*********************************** internal utils *********************************************/
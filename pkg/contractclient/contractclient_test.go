@@ -8,9 +8,11 @@ import (
 	"strings"
 	"testing"
 
+	contracttypes "github.com/ChoSanghyuk/blackholedex/pkg/types"
 	"github.com/ChoSanghyuk/blackholedex/pkg/util"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/joho/godotenv"
 )
@@ -191,3 +193,240 @@ func TestCallTransaction(t *testing.T) {
 	})
 
 }
+
+func TestGasParams(t *testing.T) {
+	suggested := big.NewInt(25_000_000_000) // 25 Gwei
+
+	// effective price = min(feeCap, baseFee + tip), with the suggested price as base fee
+	effective := func(tip, feeCap *big.Int) *big.Int {
+		price := new(big.Int).Add(suggested, tip)
+		if price.Cmp(feeCap) > 0 {
+			return feeCap
+		}
+		return price
+	}
+
+	slowTip, slowCap, err := gasParams(contracttypes.GasSlow, suggested, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stdTip, stdCap, err := gasParams(contracttypes.GasStandard, suggested, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fastTip, fastCap, err := gasParams(contracttypes.GasFast, suggested, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if effective(fastTip, fastCap).Cmp(effective(stdTip, stdCap)) <= 0 {
+		t.Errorf("Fast effective price %s should exceed Standard %s", effective(fastTip, fastCap), effective(stdTip, stdCap))
+	}
+	if effective(stdTip, stdCap).Cmp(effective(slowTip, slowCap)) <= 0 {
+		t.Errorf("Standard effective price %s should exceed Slow %s", effective(stdTip, stdCap), effective(slowTip, slowCap))
+	}
+
+	// Custom: fixed gas price
+	tip, feeCap, err := gasParams(contracttypes.GasCustom, suggested, &contracttypes.CustomGas{GasPrice: big.NewInt(30_000_000_000)})
+	if err != nil || tip.Cmp(big.NewInt(30_000_000_000)) != 0 || feeCap.Cmp(big.NewInt(30_000_000_000)) != 0 {
+		t.Errorf("fixed gas price: tip=%v feeCap=%v err=%v", tip, feeCap, err)
+	}
+
+	// Custom: multiplier over suggested price
+	_, feeCap, err = gasParams(contracttypes.GasCustom, suggested, &contracttypes.CustomGas{Multiplier: 1.5})
+	if err != nil || feeCap.Cmp(big.NewInt(37_500_000_000)) != 0 {
+		t.Errorf("multiplier: feeCap=%v err=%v", feeCap, err)
+	}
+
+	// Custom without configuration is rejected
+	if _, _, err := gasParams(contracttypes.GasCustom, suggested, nil); err == nil {
+		t.Error("expected error for GasCustom strategy without configuration")
+	}
+}
+
+func TestBaseFeePricedDynamicFeeTx(t *testing.T) {
+	baseFee := big.NewInt(25_000_000_000) // 25 Gwei
+	suggested := big.NewInt(40_000_000_000)
+	tip := big.NewInt(2_000_000_000)
+	cc := NewContractClient(nil, common.HexToAddress("0x01"), nil, WithBaseFeePricing(2, tip))
+
+	fees, err := cc.txFees(contracttypes.GasStandard, suggested, baseFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := cc.newTx(7, 21_000, nil, nil, fees)
+
+	if tx.Type() != types.DynamicFeeTxType {
+		t.Fatalf("tx type = %d, want %d", tx.Type(), types.DynamicFeeTxType)
+	}
+	// 2 * 25 Gwei base fee + 2 Gwei tip, independent of the suggested gas price
+	if want := big.NewInt(52_000_000_000); tx.GasFeeCap().Cmp(want) != 0 {
+		t.Errorf("maxFeePerGas = %s, want %s", tx.GasFeeCap(), want)
+	}
+	if tx.GasTipCap().Cmp(tip) != 0 {
+		t.Errorf("maxPriorityFeePerGas = %s, want %s", tx.GasTipCap(), tip)
+	}
+
+	// Fast and Slow still apply on top of base fee pricing
+	fast, err := cc.txFees(contracttypes.GasFast, suggested, baseFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	slow, err := cc.txFees(contracttypes.GasSlow, suggested, baseFee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fast.gasFeeCap.Cmp(fees.gasFeeCap) <= 0 || fast.gasTipCap.Cmp(fees.gasTipCap) <= 0 {
+		t.Errorf("Fast fees (tip %s, cap %s) should exceed Standard (tip %s, cap %s)", fast.gasTipCap, fast.gasFeeCap, fees.gasTipCap, fees.gasFeeCap)
+	}
+	if slow.gasFeeCap.Cmp(fees.gasFeeCap) >= 0 || slow.gasTipCap.Cmp(fees.gasTipCap) >= 0 {
+		t.Errorf("Slow fees (tip %s, cap %s) should be below Standard (tip %s, cap %s)", slow.gasTipCap, slow.gasFeeCap, fees.gasTipCap, fees.gasFeeCap)
+	}
+	if slow.gasFeeCap.Cmp(baseFee) <= 0 {
+		t.Errorf("Slow fee cap %s must stay above the base fee %s", slow.gasFeeCap, baseFee)
+	}
+
+	// A chain without a base fee cannot be priced this way
+	if _, err := cc.txFees(contracttypes.GasStandard, suggested, nil); err == nil {
+		t.Error("expected error without a base fee")
+	}
+}
+
+func TestLegacyTx(t *testing.T) {
+	suggested := big.NewInt(40_000_000_000)
+	cc := NewContractClient(nil, common.HexToAddress("0x01"), nil, WithTxType(Legacy))
+	if cc.usesBaseFee(contracttypes.GasStandard) {
+		t.Error("legacy transactions must not need a base fee")
+	}
+
+	fees, err := cc.txFees(contracttypes.GasStandard, suggested, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := cc.newTx(7, 21_000, nil, nil, fees)
+
+	if tx.Type() != types.LegacyTxType {
+		t.Fatalf("tx type = %d, want %d", tx.Type(), types.LegacyTxType)
+	}
+	if tx.GasPrice().Cmp(suggested) != 0 {
+		t.Errorf("gasPrice = %s, want %s", tx.GasPrice(), suggested)
+	}
+}
+
+func TestDecodeTransactionHex(t *testing.T) {
+	erc20, err := util.LoadABI("../../blackholedex-contracts/abi/ERC20.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc := NewContractClient(nil, common.HexToAddress("0x01"), erc20)
+
+	// transfer(0x...aa, 1000000)
+	calldata := "a9059cbb" +
+		"00000000000000000000000000000000000000000000000000000000000000aa" +
+		"00000000000000000000000000000000000000000000000000000000000f4240"
+
+	for _, prefix := range []string{"", "0x", "0X"} {
+		decoded, err := cc.DecodeTransactionHex(prefix + calldata)
+		if err != nil {
+			t.Fatalf("prefix %q: %v", prefix, err)
+		}
+		if decoded.MethodName != "transfer" {
+			t.Errorf("prefix %q: MethodName = %q, want transfer", prefix, decoded.MethodName)
+		}
+		params := decoded.Params()
+		if to := params["to"]; to != common.HexToAddress("0xaa").Hex() {
+			t.Errorf("prefix %q: to = %v", prefix, to)
+		}
+		if amount := params["amount"]; amount != "1000000" {
+			t.Errorf("prefix %q: amount = %v", prefix, amount)
+		}
+	}
+
+	if _, err := cc.DecodeTransactionHex("0xzz"); err == nil {
+		t.Error("expected an error for invalid hex")
+	}
+}
+
+func TestDecodeLogs(t *testing.T) {
+	nftManagerABI, err := util.LoadABI("../../blackholedex-contracts/abi/MultiCallNonfungiblePositionManager.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nftManager := common.HexToAddress("0xb1")
+	cc := NewContractClient(nil, nftManager, nftManagerABI)
+
+	recipient := common.HexToAddress("0xaa")
+	transfer := &types.Log{
+		Address: nftManager,
+		Topics: []common.Hash{
+			nftManagerABI.Events["Transfer"].ID,
+			{}, // from: zero address
+			common.BytesToHash(recipient.Bytes()),
+			common.BigToHash(big.NewInt(77)),
+		},
+		Index: 4,
+	}
+	// A log from another contract is skipped
+	foreign := &types.Log{Address: common.HexToAddress("0xc1"), Topics: transfer.Topics}
+
+	events, err := cc.DecodeLogs(&contracttypes.TxReceipt{Logs: []*types.Log{foreign, transfer}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("got %d events, want 1", len(events))
+	}
+	event := events[0]
+	if event.Name != "Transfer" || event.Address != nftManager {
+		t.Errorf("got %s from %s, want Transfer from %s", event.Name, event.Address.Hex(), nftManager.Hex())
+	}
+	tokenID, ok := event.Params["tokenId"].(*big.Int)
+	if !ok {
+		t.Fatalf("tokenId is %T, want *big.Int", event.Params["tokenId"])
+	}
+	if tokenID.Cmp(big.NewInt(77)) != 0 {
+		t.Errorf("tokenId = %s, want 77", tokenID)
+	}
+	if to, ok := event.Params["to"].(common.Address); !ok || to != recipient {
+		t.Errorf("to = %v, want %s", event.Params["to"], recipient.Hex())
+	}
+}
+
+func TestFindEvent(t *testing.T) {
+	nftManagerABI, err := util.LoadABI("../../blackholedex-contracts/abi/MultiCallNonfungiblePositionManager.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	nftManager := common.HexToAddress("0xb1")
+	cc := NewContractClient(nil, nftManager, nftManagerABI)
+
+	recipient := common.HexToAddress("0xaa")
+	tokenTopic := common.BigToHash(big.NewInt(77))
+	collect := nftManagerABI.Events["Collect"]
+	collectData, err := collect.Inputs.NonIndexed().Pack(recipient, big.NewInt(1000), big.NewInt(2000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt := &contracttypes.TxReceipt{Logs: []*types.Log{
+		{Address: nftManager, Topics: []common.Hash{nftManagerABI.Events["Approval"].ID, common.BytesToHash(recipient.Bytes()), {}, tokenTopic}},
+		{Address: nftManager, Topics: []common.Hash{collect.ID, tokenTopic}, Data: collectData},
+		{Address: nftManager, Topics: []common.Hash{nftManagerABI.Events["Transfer"].ID, common.BytesToHash(recipient.Bytes()), {}, tokenTopic}},
+	}}
+
+	event, ok := cc.FindEvent(receipt, "Collect")
+	if !ok {
+		t.Fatal("Collect event not found")
+	}
+	if amount1, _ := event.Params["amount1"].(*big.Int); amount1 == nil || amount1.Cmp(big.NewInt(2000)) != 0 {
+		t.Errorf("amount1 = %v, want 2000", event.Params["amount1"])
+	}
+
+	event, ok = cc.FindEvent(receipt, "Transfer")
+	if !ok || event.Name != "Transfer" {
+		t.Fatalf("Transfer event not found: %v", event)
+	}
+
+	if _, ok := cc.FindEvent(receipt, "DecreaseLiquidity"); ok {
+		t.Error("expected no DecreaseLiquidity event")
+	}
+}
@@ -0,0 +1,277 @@
+// Package mock provides an in-memory ContractClient for unit tests that run without a node
+package mock
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// SentTx records a transaction submitted through MockContractClient
+type SentTx struct {
+	Method string
+	Value  *big.Int
+	Args   []interface{}
+	TxHash common.Hash
+}
+
+// cannedCall is a registered Call response; nil args match any arguments
+type cannedCall struct {
+	method  string
+	args    []interface{}
+	outputs []interface{}
+	err     error
+}
+
+// MockContractClient implements blackholedex.ContractClient in memory
+// Call serves responses registered with OnCall/OnCallError; Send records the transaction and
+// returns TxHash, or a hash derived from the send count when TxHash is zero
+// Safe for concurrent use
+type MockContractClient struct {
+	mu      sync.Mutex
+	address common.Address
+	abi     *abi.ABI
+	calls   []cannedCall
+	sent    []SentTx
+
+	TxHash  common.Hash // Hash returned by Send; zero returns 0x..01, 0x..02, ... in send order
+	SendErr error       // Returned by Send instead of recording the transaction
+	Events  string      // ParseReceipt output; "[]" when empty
+}
+
+// NewMockContractClient returns a mock client for the contract at address; contractABI may be nil
+func NewMockContractClient(address common.Address, contractABI *abi.ABI) *MockContractClient {
+	return &MockContractClient{address: address, abi: contractABI}
+}
+
+// OnCall makes Call(method, args...) return outputs; nil args match any arguments
+// Responses registered for exact arguments take precedence over ones for any arguments
+func (m *MockContractClient) OnCall(method string, args []interface{}, outputs ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, cannedCall{method: method, args: args, outputs: outputs})
+}
+
+// OnCallError makes Call(method, args...) fail with err; nil args match any arguments
+func (m *MockContractClient) OnCallError(method string, args []interface{}, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, cannedCall{method: method, args: args, err: err})
+}
+
+// Sent returns the transactions sent so far, in order
+func (m *MockContractClient) Sent() []SentTx {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]SentTx(nil), m.sent...)
+}
+
+// SentMethods returns the names of the methods sent so far, in order
+func (m *MockContractClient) SentMethods() []string {
+	sent := m.Sent()
+	methods := make([]string, len(sent))
+	for i, tx := range sent {
+		methods[i] = tx.Method
+	}
+	return methods
+}
+
+func (m *MockContractClient) Send(priority types.Priority, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
+	return m.SendWithValue(priority, nil, from, privateKey, method, args...)
+}
+
+// SendCtx fails with ctx.Err() once ctx is done, and otherwise behaves like Send
+func (m *MockContractClient) SendCtx(ctx context.Context, priority types.Priority, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
+	if err := ctx.Err(); err != nil {
+		return common.Hash{}, err
+	}
+	return m.Send(priority, from, privateKey, method, args...)
+}
+
+func (m *MockContractClient) SendWithValue(priority types.Priority, value *big.Int, from *common.Address, privateKey *ecdsa.PrivateKey, method string, args ...interface{}) (common.Hash, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.SendErr != nil {
+		return common.Hash{}, m.SendErr
+	}
+	hash := m.TxHash
+	if hash == (common.Hash{}) {
+		hash = common.BigToHash(big.NewInt(int64(len(m.sent) + 1)))
+	}
+	m.sent = append(m.sent, SentTx{Method: method, Value: value, Args: args, TxHash: hash})
+	return hash, nil
+}
+
+func (m *MockContractClient) Call(from *common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var anyArgs *cannedCall
+	for i := range m.calls {
+		c := &m.calls[i]
+		if c.method != method {
+			continue
+		}
+		if c.args == nil {
+			if anyArgs == nil {
+				anyArgs = c
+			}
+			continue
+		}
+		if argsEqual(c.args, args) {
+			return c.outputs, c.err
+		}
+	}
+	if anyArgs != nil {
+		return anyArgs.outputs, anyArgs.err
+	}
+	return nil, fmt.Errorf("mock: no response registered for %s%v", method, args)
+}
+
+// CallCtx fails with ctx.Err() once ctx is done, and otherwise behaves like Call
+func (m *MockContractClient) CallCtx(ctx context.Context, from *common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return m.Call(from, method, args...)
+}
+
+func (m *MockContractClient) CallWithRetry(from *common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	return m.Call(from, method, args...)
+}
+
+// GetReceipt returns a successful receipt for any hash
+func (m *MockContractClient) GetReceipt(txHash common.Hash) (*types.TxReceipt, error) {
+	return Receipt(txHash), nil
+}
+
+func (m *MockContractClient) ParseReceipt(receipt *types.TxReceipt) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.Events == "" {
+		return "[]", nil
+	}
+	return m.Events, nil
+}
+
+// DecodeLogs decodes Events with DecodeEventsJSON
+func (m *MockContractClient) DecodeLogs(receipt *types.TxReceipt) ([]types.DecodedEvent, error) {
+	events, _ := m.ParseReceipt(receipt)
+	return DecodeEventsJSON(events)
+}
+
+func (m *MockContractClient) FindEvent(receipt *types.TxReceipt, eventName string) (*types.DecodedEvent, bool) {
+	events, err := m.DecodeLogs(receipt)
+	if err != nil {
+		return nil, false
+	}
+	return types.FindEvent(events, eventName)
+}
+
+func (m *MockContractClient) TransactionData(hash common.Hash) ([]byte, error) {
+	return nil, nil
+}
+
+func (m *MockContractClient) ContractAddress() *common.Address {
+	return &m.address
+}
+
+func (m *MockContractClient) ChainId() *big.Int {
+	return big.NewInt(43114)
+}
+
+func (m *MockContractClient) DecodeTransaction(data []byte) (*types.DecodedTransaction, error) {
+	return nil, fmt.Errorf("mock: decode not supported")
+}
+
+func (m *MockContractClient) DecodeTransactionHex(hexData string) (*types.DecodedTransaction, error) {
+	return nil, fmt.Errorf("mock: decode not supported")
+}
+
+func (m *MockContractClient) DecodeByHash(txHash common.Hash) (*types.DecodedTransaction, error) {
+	return nil, fmt.Errorf("mock: decode not supported")
+}
+
+func (m *MockContractClient) Abi() *abi.ABI {
+	return m.abi
+}
+
+// Receipt returns a successful receipt for txHash with 21000 gas used at 1 gwei
+func Receipt(txHash common.Hash) *types.TxReceipt {
+	return &types.TxReceipt{
+		TxHash:            txHash,
+		Status:            "0x1",
+		GasUsed:           "0x5208",
+		EffectiveGasPrice: "0x3b9aca00",
+	}
+}
+
+// DecodeEventsJSON converts ParseReceipt-style JSON into typed events, as ContractClient.DecodeLogs returns them
+// Integer parameters become *big.Int and 0x-prefixed 20-byte hex strings become common.Address;
+// entries without an event name (logs ParseReceipt could not decode) are skipped
+func DecodeEventsJSON(events string) ([]types.DecodedEvent, error) {
+	var infos []types.EventInfo
+	decoder := json.NewDecoder(strings.NewReader(events))
+	decoder.UseNumber()
+	if err := decoder.Decode(&infos); err != nil {
+		return nil, fmt.Errorf("mock: invalid events JSON: %w", err)
+	}
+
+	var decoded []types.DecodedEvent
+	for _, info := range infos {
+		if info.EventName == "" {
+			continue
+		}
+		params := make(map[string]interface{}, len(info.Parameter))
+		for name, value := range info.Parameter {
+			params[name] = typedParam(value)
+		}
+		decoded = append(decoded, types.DecodedEvent{Name: info.EventName, Address: info.Address, Params: params})
+	}
+	return decoded, nil
+}
+
+// typedParam converts a JSON-decoded event parameter to the Go type the ABI decoder produces
+func typedParam(value interface{}) interface{} {
+	switch v := value.(type) {
+	case json.Number:
+		if n, ok := new(big.Int).SetString(v.String(), 10); ok {
+			return n
+		}
+	case string:
+		if len(v) == 42 && common.IsHexAddress(v) {
+			return common.HexToAddress(v)
+		}
+	}
+	return value
+}
+
+// argsEqual compares call arguments, treating *big.Int values with the same value as equal
+func argsEqual(want, got []interface{}) bool {
+	if len(want) != len(got) {
+		return false
+	}
+	for i := range want {
+		wantInt, ok1 := want[i].(*big.Int)
+		gotInt, ok2 := got[i].(*big.Int)
+		if ok1 && ok2 {
+			if wantInt.Cmp(gotInt) != 0 {
+				return false
+			}
+			continue
+		}
+		if !reflect.DeepEqual(want[i], got[i]) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,54 @@
+package contractclient
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NonceSource returns the node's pending nonce for an address. Satisfied by *ethclient.Client
+type NonceSource interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+}
+
+// NonceManager hands out sequential nonces per sender so back-to-back sends don't rely on a stale pending nonce
+// Share one instance across all ContractClients that sign for the same account
+type NonceManager struct {
+	mu     sync.Mutex
+	source NonceSource
+	nonces map[common.Address]uint64
+}
+
+func NewNonceManager(source NonceSource) *NonceManager {
+	return &NonceManager{
+		source: source,
+		nonces: make(map[common.Address]uint64),
+	}
+}
+
+// Next reserves the next nonce for addr, syncing from the node only when no local nonce is tracked
+func (nm *NonceManager) Next(ctx context.Context, addr common.Address) (uint64, error) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+
+	nonce, ok := nm.nonces[addr]
+	if !ok {
+		pending, err := nm.source.PendingNonceAt(ctx, addr)
+		if err != nil {
+			return 0, errors.Join(errors.New("PendingNonceAt Error"), err)
+		}
+		nonce = pending
+	}
+	nm.nonces[addr] = nonce + 1
+	return nonce, nil
+}
+
+// ResetNonce drops the tracked nonce for addr so the next send resyncs from the node
+// Call after a failed send or when transactions were sent outside this manager
+func (nm *NonceManager) ResetNonce(addr common.Address) {
+	nm.mu.Lock()
+	defer nm.mu.Unlock()
+	delete(nm.nonces, addr)
+}
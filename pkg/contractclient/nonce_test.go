@@ -0,0 +1,198 @@
+package contractclient
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	contracttypes "github.com/ChoSanghyuk/blackholedex/pkg/types"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// countingNonceSource reports a fixed pending nonce and counts node queries
+type countingNonceSource struct {
+	pending uint64
+	calls   int
+}
+
+func (s *countingNonceSource) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	s.calls++
+	return s.pending, nil
+}
+
+func TestNonceManager(t *testing.T) {
+	source := &countingNonceSource{pending: 7}
+	nm := NewNonceManager(source)
+	sender := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	first, err := nm.Next(context.Background(), sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := nm.Next(context.Background(), sender)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if first != 7 || second != 8 {
+		t.Errorf("nonces = %d, %d; want 7, 8", first, second)
+	}
+	if source.calls != 1 {
+		t.Errorf("node queried %d times, want 1", source.calls)
+	}
+
+	// Other senders are tracked independently
+	other, _ := nm.Next(context.Background(), common.HexToAddress("0xbb"))
+	if other != 7 || source.calls != 2 {
+		t.Errorf("other sender nonce = %d after %d queries; want 7 after 2", other, source.calls)
+	}
+
+	// After a reset the node is queried again
+	source.pending = 12
+	nm.ResetNonce(sender)
+	resynced, _ := nm.Next(context.Background(), sender)
+	if resynced != 12 || source.calls != 3 {
+		t.Errorf("resynced nonce = %d after %d queries; want 12 after 3", resynced, source.calls)
+	}
+}
+
+// sendBackend is an in-process eth RPC namespace that counts nonce queries and records sent nonces
+type sendBackend struct {
+	pending    uint64
+	nonceCalls int
+	sent       []uint64
+}
+
+func (b *sendBackend) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(43114))
+}
+
+func (b *sendBackend) GasPrice() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(25_000_000_000))
+}
+
+func (b *sendBackend) EstimateGas(args map[string]interface{}, block *string) hexutil.Uint64 {
+	return 21_000
+}
+
+func (b *sendBackend) GetTransactionCount(addr common.Address, block string) hexutil.Uint64 {
+	b.nonceCalls++
+	return hexutil.Uint64(b.pending)
+}
+
+func (b *sendBackend) SendRawTransaction(data hexutil.Bytes) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(data); err != nil {
+		return common.Hash{}, err
+	}
+	b.sent = append(b.sent, tx.Nonce())
+	return tx.Hash(), nil
+}
+
+func TestSendWithNonceManager(t *testing.T) {
+	backend := &sendBackend{pending: 7}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", backend); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	client, err := ethclient.Dial(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	contractABI, err := abi.JSON(strings.NewReader(`[{"type":"function","name":"ping","inputs":[],"outputs":[]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	cc := NewContractClient(client, common.HexToAddress("0x01"), &contractABI, WithNonceManager(NewNonceManager(client)))
+
+	for range 2 {
+		if _, err := cc.Send(contracttypes.Standard, &from, key, "ping"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(backend.sent) != 2 || backend.sent[0] != 7 || backend.sent[1] != 8 {
+		t.Errorf("sent nonces = %v; want [7 8]", backend.sent)
+	}
+	if backend.nonceCalls != 1 {
+		t.Errorf("node queried for the nonce %d times, want 1", backend.nonceCalls)
+	}
+}
+
+// stuckBackend is an eth RPC namespace whose eth_call never answers until release is closed
+type stuckBackend struct {
+	release chan struct{}
+}
+
+func (b *stuckBackend) ChainId() *hexutil.Big {
+	return (*hexutil.Big)(big.NewInt(43114))
+}
+
+func (b *stuckBackend) Call(args map[string]interface{}, block *string) hexutil.Bytes {
+	<-b.release
+	return nil
+}
+
+func TestCallCtxCancel(t *testing.T) {
+	backend := &stuckBackend{release: make(chan struct{})}
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", backend); err != nil {
+		t.Fatal(err)
+	}
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+	defer close(backend.release)
+	client, err := ethclient.Dial(httpServer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+
+	contractABI, err := abi.JSON(strings.NewReader(`[{"type":"function","name":"ping","inputs":[],"outputs":[{"type":"uint256"}]}]`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cc := NewContractClient(client, common.HexToAddress("0x01"), &contractABI)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = cc.CallCtx(ctx, nil, "ping")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CallCtx error = %v; want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("CallCtx returned after %v; want it to stop at the deadline", elapsed)
+	}
+
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	from := crypto.PubkeyToAddress(key.PublicKey)
+	cancelled, cancelNow := context.WithCancel(context.Background())
+	cancelNow()
+	if _, err := cc.SendCtx(cancelled, contracttypes.Standard, &from, key, "ping"); !errors.Is(err, context.Canceled) {
+		t.Errorf("SendCtx error = %v; want context.Canceled", err)
+	}
+}
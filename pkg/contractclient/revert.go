@@ -0,0 +1,116 @@
+package contractclient
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	errorSelector = []byte{0x08, 0xc3, 0x79, 0xa0} // Error(string)
+	panicSelector = []byte{0x4e, 0x48, 0x7b, 0x71} // Panic(uint256)
+)
+
+// panicReasons maps Solidity Panic(uint256) codes to descriptions
+var panicReasons = map[uint64]string{
+	0x00: "generic compiler panic",
+	0x01: "assertion failed",
+	0x11: "arithmetic overflow",
+	0x12: "division or modulo by zero",
+	0x21: "invalid enum value",
+	0x22: "invalid storage byte array encoding",
+	0x31: "pop on empty array",
+	0x32: "array index out of bounds",
+	0x41: "out of memory",
+	0x51: "call to zero-initialized function",
+}
+
+// CallRevertError carries the decoded revert reason of a failed call or gas estimation
+// Unlike txlistener.RevertError, the transaction never reached the chain
+type CallRevertError struct {
+	Reason string // e.g. "execution reverted: STF" or "panic: arithmetic overflow (0x11)"
+	Data   []byte // Raw revert payload
+	err    error
+}
+
+func (e *CallRevertError) Error() string {
+	return e.Reason
+}
+
+func (e *CallRevertError) Unwrap() error {
+	return e.err
+}
+
+// DecodeRevertReason decodes a standard Error(string) or Panic(uint256) revert payload
+// Returns false if data is not one of those encodings
+func DecodeRevertReason(data []byte) (string, bool) {
+	if len(data) < 4 {
+		return "", false
+	}
+	selector, payload := data[:4], data[4:]
+
+	switch {
+	case string(selector) == string(errorSelector):
+		stringTy, _ := abi.NewType("string", "", nil)
+		values, err := abi.Arguments{{Type: stringTy}}.Unpack(payload)
+		if err != nil || len(values) != 1 {
+			return "", false
+		}
+		return "execution reverted: " + values[0].(string), true
+
+	case string(selector) == string(panicSelector):
+		if len(payload) != 32 {
+			return "", false
+		}
+		code := new(big.Int).SetBytes(payload)
+		reason := "unknown panic"
+		if code.IsUint64() {
+			if r, ok := panicReasons[code.Uint64()]; ok {
+				reason = r
+			}
+		}
+		return fmt.Sprintf("panic: %s (0x%02x)", reason, code), true
+	}
+	return "", false
+}
+
+// wrapRevert replaces an RPC error carrying revert data with a CallRevertError holding the decoded reason
+// Errors without decodable revert data are returned unchanged
+func wrapRevert(err error) error {
+	var dataErr rpc.DataError
+	if err == nil || !errors.As(err, &dataErr) {
+		return err
+	}
+
+	data, ok := revertData(dataErr.ErrorData())
+	if !ok {
+		return err
+	}
+	reason, ok := DecodeRevertReason(data)
+	if !ok {
+		return err
+	}
+	return &CallRevertError{Reason: reason, Data: data, err: err}
+}
+
+// revertData extracts revert bytes from the error data returned by the node (usually a hex string)
+func revertData(v interface{}) ([]byte, bool) {
+	switch data := v.(type) {
+	case string:
+		if !strings.HasPrefix(data, "0x") {
+			return nil, false
+		}
+		b, err := hexutil.Decode(data)
+		return b, err == nil
+	case []byte:
+		return data, true
+	case hexutil.Bytes:
+		return data, true
+	}
+	return nil, false
+}
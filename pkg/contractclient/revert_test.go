@@ -0,0 +1,80 @@
+package contractclient
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// dataError mimics the JSON-RPC error returned by a node for a reverted eth_call
+type dataError struct {
+	data interface{}
+}
+
+func (e *dataError) Error() string          { return "execution reverted" }
+func (e *dataError) ErrorData() interface{} { return e.data }
+
+func TestDecodeRevertReason(t *testing.T) {
+	tests := []struct {
+		name    string
+		payload string
+		want    string
+	}{
+		{
+			name: "ErrorString",
+			// Error("STF")
+			payload: "0x08c379a0" +
+				"0000000000000000000000000000000000000000000000000000000000000020" +
+				"0000000000000000000000000000000000000000000000000000000000000003" +
+				"5354460000000000000000000000000000000000000000000000000000000000",
+			want: "execution reverted: STF",
+		},
+		{
+			name:    "PanicOverflow",
+			payload: "0x4e487b71" + "0000000000000000000000000000000000000000000000000000000000000011",
+			want:    "panic: arithmetic overflow (0x11)",
+		},
+		{
+			name:    "PanicDivisionByZero",
+			payload: "0x4e487b71" + "0000000000000000000000000000000000000000000000000000000000000012",
+			want:    "panic: division or modulo by zero (0x12)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DecodeRevertReason(common.FromHex(tt.payload))
+			if !ok || got != tt.want {
+				t.Errorf("DecodeRevertReason = %q, %v; want %q", got, ok, tt.want)
+			}
+		})
+	}
+
+	// Custom errors are not decoded
+	if _, ok := DecodeRevertReason(common.FromHex("0xdeadbeef")); ok {
+		t.Error("expected custom error selector to be left undecoded")
+	}
+}
+
+func TestWrapRevert(t *testing.T) {
+	rpcErr := &dataError{data: "0x4e487b710000000000000000000000000000000000000000000000000000000000000011"}
+
+	err := wrapRevert(rpcErr)
+	var revertErr *CallRevertError
+	if !errors.As(err, &revertErr) {
+		t.Fatalf("expected CallRevertError, got %T: %v", err, err)
+	}
+	if revertErr.Error() != "panic: arithmetic overflow (0x11)" {
+		t.Errorf("reason = %q", revertErr.Error())
+	}
+	if !errors.Is(err, rpcErr) {
+		t.Error("CallRevertError should unwrap to the original RPC error")
+	}
+
+	// Errors without revert data pass through unchanged
+	plain := errors.New("connection refused")
+	if wrapRevert(plain) != plain {
+		t.Error("plain error should be returned unchanged")
+	}
+}
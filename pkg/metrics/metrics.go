@@ -0,0 +1,97 @@
+// Package metrics exposes Prometheus metrics for strategy operations
+// All metrics are registered with the default Prometheus registry
+package metrics
+
+import (
+	"math/big"
+	"net/http"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "blackholedex"
+
+var (
+	transactionsSent = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "transactions_sent_total",
+		Help:      "Confirmed transactions by operation (ApproveWAVAX, Mint, Stake, ...).",
+	}, []string{"operation"})
+
+	gasSpent = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "gas_spent_wei_total",
+		Help:      "Total gas cost of confirmed transactions in wei.",
+	})
+
+	currentPhase = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "strategy_phase",
+		Help:      "Current strategy phase (0=Initializing, 1=ActiveMonitoring, 2=RebalancingRequired, 3=WaitingForStability, 4=Halted, 5=Paused).",
+	})
+
+	rebalances = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rebalances_total",
+		Help:      "Completed rebalancing workflows.",
+	})
+
+	circuitBreakerTrips = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "circuit_breaker_trips_total",
+		Help:      "Times the circuit breaker halted the strategy.",
+	})
+
+	positionLiquidity = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "position_liquidity",
+		Help:      "Liquidity of the active position (0 when no position is open).",
+	})
+)
+
+// Handler returns an http.Handler serving the default registry in the Prometheus text format
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RecordTransactions counts records per operation and adds their gas cost to the gas total
+func RecordTransactions(records []types.TransactionRecord) {
+	for _, record := range records {
+		transactionsSent.WithLabelValues(record.Operation).Inc()
+		if record.GasCost != nil {
+			gasSpent.Add(bigToFloat(record.GasCost))
+		}
+	}
+}
+
+// SetPhase reports the current strategy phase
+func SetPhase(phase types.StrategyPhase) {
+	currentPhase.Set(float64(phase))
+}
+
+// IncRebalance counts a completed rebalancing workflow
+func IncRebalance() {
+	rebalances.Inc()
+}
+
+// IncCircuitBreakerTrip counts a circuit breaker halt
+func IncCircuitBreakerTrip() {
+	circuitBreakerTrips.Inc()
+}
+
+// SetPositionLiquidity reports the liquidity of the active position (nil = no position)
+func SetPositionLiquidity(liquidity *big.Int) {
+	if liquidity == nil {
+		positionLiquidity.Set(0)
+		return
+	}
+	positionLiquidity.Set(bigToFloat(liquidity))
+}
+
+func bigToFloat(v *big.Int) float64 {
+	f, _ := new(big.Float).SetInt(v).Float64()
+	return f
+}
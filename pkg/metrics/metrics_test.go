@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"io"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerAfterMint(t *testing.T) {
+	// The transactions Mint records for one position
+	RecordTransactions([]types.TransactionRecord{
+		{Operation: "ApproveWAVAX", GasCost: big.NewInt(1_000)},
+		{Operation: "ApproveUSDC", GasCost: big.NewInt(2_000)},
+		{Operation: "Mint", GasCost: big.NewInt(30_000)},
+	})
+	SetPhase(types.ActiveMonitoring)
+	SetPositionLiquidity(big.NewInt(123_456))
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, err := io.ReadAll(rec.Body)
+	if !assert.NoError(t, err) {
+		return
+	}
+	scrape := string(body)
+
+	for _, name := range []string{
+		"blackholedex_transactions_sent_total",
+		"blackholedex_gas_spent_wei_total",
+		"blackholedex_strategy_phase",
+		"blackholedex_rebalances_total",
+		"blackholedex_circuit_breaker_trips_total",
+		"blackholedex_position_liquidity",
+	} {
+		assert.Contains(t, scrape, "# TYPE "+name)
+	}
+	assert.Contains(t, scrape, `blackholedex_transactions_sent_total{operation="Mint"} 1`)
+	assert.Contains(t, scrape, `blackholedex_transactions_sent_total{operation="ApproveWAVAX"} 1`)
+	assert.Contains(t, scrape, "blackholedex_gas_spent_wei_total 33000")
+	assert.Contains(t, scrape, "blackholedex_strategy_phase 1")
+	assert.Contains(t, scrape, "blackholedex_position_liquidity 123456")
+}
@@ -0,0 +1,187 @@
+// Package notify forwards selected strategy reports to external alerting endpoints
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultEventTypes are the report event types posted when no filter is given
+// The circuit breaker halting the strategy is reported as "shutdown"
+var DefaultEventTypes = []string{"error", "shutdown", "rebalance_start", "profit"}
+
+// WebhookNotifier POSTs strategy reports of the selected event types to a webhook URL
+// The body is the report JSON as sent on the strategy's report channel
+// Failed posts are retried; a report identical to one posted within the dedupe window is skipped
+// Safe for concurrent use
+type WebhookNotifier struct {
+	url          string
+	eventTypes   map[string]bool
+	client       *http.Client
+	attempts     int
+	retryDelay   time.Duration
+	dedupeWindow time.Duration
+	now          func() time.Time
+
+	mu       sync.Mutex
+	lastSent map[string]time.Time // Dedupe key → time it was last posted
+}
+
+// Option configures a WebhookNotifier
+type Option func(*WebhookNotifier)
+
+// WithHTTPClient sets the client used for posting (default: 10s timeout)
+func WithHTTPClient(client *http.Client) Option {
+	return func(n *WebhookNotifier) {
+		n.client = client
+	}
+}
+
+// WithRetry sets how many times a post is attempted in total and the delay between attempts (default: 3, 2s)
+func WithRetry(attempts int, delay time.Duration) Option {
+	return func(n *WebhookNotifier) {
+		if attempts < 1 {
+			attempts = 1
+		}
+		n.attempts = attempts
+		n.retryDelay = delay
+	}
+}
+
+// WithDedupeWindow sets how long an identical report is suppressed after being posted (default: 10m, 0 = disabled)
+func WithDedupeWindow(window time.Duration) Option {
+	return func(n *WebhookNotifier) {
+		n.dedupeWindow = window
+	}
+}
+
+// NewWebhookNotifier returns a notifier posting reports whose event type is in eventTypes to url
+// An empty eventTypes selects DefaultEventTypes
+func NewWebhookNotifier(url string, eventTypes []string, opts ...Option) *WebhookNotifier {
+	if len(eventTypes) == 0 {
+		eventTypes = DefaultEventTypes
+	}
+	n := &WebhookNotifier{
+		url:          url,
+		eventTypes:   make(map[string]bool, len(eventTypes)),
+		client:       &http.Client{Timeout: 10 * time.Second},
+		attempts:     3,
+		retryDelay:   2 * time.Second,
+		dedupeWindow: 10 * time.Minute,
+		now:          time.Now,
+		lastSent:     make(map[string]time.Time),
+	}
+	for _, eventType := range eventTypes {
+		n.eventTypes[eventType] = true
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// reportKey holds the report fields used for filtering and deduplication
+type reportKey struct {
+	EventType string `json:"event_type"`
+	Message   string `json:"message"`
+	Error     string `json:"error"`
+}
+
+// Notify posts report when its event type is selected and it is not a duplicate
+// report is a StrategyReport in JSON; reports of other event types are ignored without error
+func (n *WebhookNotifier) Notify(ctx context.Context, report string) error {
+	var key reportKey
+	if err := json.Unmarshal([]byte(report), &key); err != nil {
+		return fmt.Errorf("invalid report JSON: %w", err)
+	}
+	if !n.eventTypes[key.EventType] {
+		return nil
+	}
+
+	dedupeKey := key.EventType + "\x00" + key.Message + "\x00" + key.Error
+	n.mu.Lock()
+	if last, ok := n.lastSent[dedupeKey]; ok && n.dedupeWindow > 0 && n.now().Sub(last) < n.dedupeWindow {
+		n.mu.Unlock()
+		return nil
+	}
+	n.mu.Unlock()
+
+	if err := n.post(ctx, []byte(report)); err != nil {
+		return fmt.Errorf("failed to post %s report: %w", key.EventType, err)
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.lastSent[dedupeKey] = n.now()
+	// Forget entries that can no longer suppress anything
+	for k, sent := range n.lastSent {
+		if n.now().Sub(sent) >= n.dedupeWindow {
+			delete(n.lastSent, k)
+		}
+	}
+	return nil
+}
+
+// post sends body to the webhook, retrying transport errors, 429 and 5xx responses
+func (n *WebhookNotifier) post(ctx context.Context, body []byte) error {
+	var lastErr error
+	for attempt := 0; attempt < n.attempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(n.retryDelay):
+			}
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := n.client.Do(req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			lastErr = err
+			continue
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+			return nil
+		}
+		lastErr = fmt.Errorf("webhook responded %s", resp.Status)
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < 500 {
+			return lastErr
+		}
+	}
+	return fmt.Errorf("%d attempts failed: %w", n.attempts, lastErr)
+}
+
+// Consume notifies for every report received on reports until ctx is cancelled or reports is closed
+// Failures are logged and do not stop the loop
+func (n *WebhookNotifier) Consume(ctx context.Context, reports <-chan string) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case report, ok := <-reports:
+			if !ok {
+				return
+			}
+			if err := n.Notify(ctx, report); err != nil {
+				log.Printf("Webhook notification failed: %v", err)
+			}
+		}
+	}
+}
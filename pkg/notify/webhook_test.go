@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// webhookServer records the event types posted to it; the first failures requests get a 500
+func webhookServer(t *testing.T, failures int) (*httptest.Server, func() []string) {
+	var mu sync.Mutex
+	var posted []string
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		requests++
+		if requests <= failures {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		body, _ := io.ReadAll(r.Body)
+		var report struct {
+			EventType string `json:"event_type"`
+		}
+		if err := json.Unmarshal(body, &report); err != nil {
+			t.Errorf("posted body is not JSON: %s", body)
+		}
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		posted = append(posted, report.EventType)
+	}))
+	t.Cleanup(server.Close)
+	return server, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), posted...)
+	}
+}
+
+func TestWebhookNotifierFilter(t *testing.T) {
+	server, posted := webhookServer(t, 0)
+	notifier := NewWebhookNotifier(server.URL, []string{"error", "shutdown", "rebalance_start"})
+
+	reports := make(chan string)
+	done := make(chan struct{})
+	go func() {
+		notifier.Consume(context.Background(), reports)
+		close(done)
+	}()
+	for _, report := range []string{
+		`{"event_type":"monitoring","message":"tick 10"}`,
+		`{"event_type":"rebalance_start","message":"out of range"}`,
+		`{"event_type":"stability_check","message":"1/5"}`,
+		`{"event_type":"error","message":"mint failed","error":"reverted"}`,
+		`{"event_type":"shutdown","message":"halted"}`,
+	} {
+		reports <- report
+	}
+	close(reports)
+	<-done
+
+	assert.Equal(t, []string{"rebalance_start", "error", "shutdown"}, posted())
+}
+
+func TestWebhookNotifierDedupe(t *testing.T) {
+	server, posted := webhookServer(t, 0)
+	now := time.Now()
+	notifier := NewWebhookNotifier(server.URL, nil, WithDedupeWindow(time.Minute))
+	notifier.now = func() time.Time { return now }
+
+	report := `{"event_type":"error","message":"mint failed","error":"reverted"}`
+	assert.NoError(t, notifier.Notify(context.Background(), report))
+	assert.NoError(t, notifier.Notify(context.Background(), report))
+	// A different error is not a duplicate
+	assert.NoError(t, notifier.Notify(context.Background(), `{"event_type":"error","message":"mint failed","error":"timeout"}`))
+	assert.Len(t, posted(), 2)
+
+	now = now.Add(time.Minute)
+	assert.NoError(t, notifier.Notify(context.Background(), report))
+	assert.Len(t, posted(), 3)
+}
+
+func TestWebhookNotifierRetry(t *testing.T) {
+	server, posted := webhookServer(t, 2)
+	notifier := NewWebhookNotifier(server.URL, nil, WithRetry(3, time.Millisecond))
+	assert.NoError(t, notifier.Notify(context.Background(), `{"event_type":"profit","message":"claimed"}`))
+	assert.Equal(t, []string{"profit"}, posted())
+
+	// Every attempt fails
+	server, posted = webhookServer(t, 3)
+	notifier = NewWebhookNotifier(server.URL, nil, WithRetry(3, time.Millisecond))
+	err := notifier.Notify(context.Background(), `{"event_type":"profit","message":"claimed"}`)
+	assert.ErrorContains(t, err, "3 attempts failed")
+	assert.Empty(t, posted())
+}
@@ -0,0 +1,138 @@
+// Package reportsink persists strategy reports outside the database
+package reportsink
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// activeFile is the name of the file reports are appended to; rotated files get a timestamp suffix
+const activeFile = "reports.jsonl"
+
+// FileSink appends reports, one JSON object per line, to reports.jsonl in its directory
+// When the file would grow past the size limit it is renamed to reports-<timestamp>.jsonl and a new one is started
+// Safe for concurrent use
+type FileSink struct {
+	mu      sync.Mutex
+	dir     string
+	maxSize int64
+	file    *os.File
+	size    int64
+}
+
+// NewFileSink opens (or creates) dir/reports.jsonl for appending, creating dir if needed
+// maxSizeBytes <= 0 disables rotation
+func NewFileSink(dir string, maxSizeBytes int64) (*FileSink, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create report directory: %w", err)
+	}
+	s := &FileSink{dir: dir, maxSize: maxSizeBytes}
+	if err := s.open(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// open opens the active file and picks up its current size
+func (s *FileSink) open() error {
+	file, err := os.OpenFile(filepath.Join(s.dir, activeFile), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open report file: %w", err)
+	}
+	info, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return fmt.Errorf("failed to stat report file: %w", err)
+	}
+	s.file = file
+	s.size = info.Size()
+	return nil
+}
+
+// rotate closes the active file, renames it with a timestamp and opens a fresh one
+func (s *FileSink) rotate() error {
+	if err := s.closeFile(); err != nil {
+		return err
+	}
+	rotated := fmt.Sprintf("reports-%s.jsonl", time.Now().UTC().Format("20060102T150405.000000000"))
+	if err := os.Rename(filepath.Join(s.dir, activeFile), filepath.Join(s.dir, rotated)); err != nil {
+		return fmt.Errorf("failed to rotate report file: %w", err)
+	}
+	return s.open()
+}
+
+// closeFile syncs and closes the active file
+func (s *FileSink) closeFile() error {
+	if s.file == nil {
+		return nil
+	}
+	syncErr := s.file.Sync()
+	closeErr := s.file.Close()
+	s.file = nil
+	if syncErr != nil {
+		return fmt.Errorf("failed to flush report file: %w", syncErr)
+	}
+	if closeErr != nil {
+		return fmt.Errorf("failed to close report file: %w", closeErr)
+	}
+	return nil
+}
+
+// Write appends report as one line, rotating first if it would push the file past the size limit
+// report is expected to be a single JSON object, as sent on the strategy's report channel; blank reports are skipped
+func (s *FileSink) Write(report string) error {
+	line := strings.TrimSpace(report)
+	if line == "" {
+		return nil
+	}
+	line += "\n"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return fmt.Errorf("report sink is closed")
+	}
+	// A report larger than the limit still gets a file of its own
+	if s.maxSize > 0 && s.size > 0 && s.size+int64(len(line)) > s.maxSize {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+	}
+	n, err := s.file.WriteString(line)
+	s.size += int64(n)
+	if err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// Consume writes every report received on reports until ctx is cancelled or reports is closed, then closes the sink
+// Write failures are returned after closing; reports already queued when ctx is cancelled are not written
+func (s *FileSink) Consume(ctx context.Context, reports <-chan string) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return s.Close()
+		case report, ok := <-reports:
+			if !ok {
+				return s.Close()
+			}
+			if err := s.Write(report); err != nil {
+				s.Close()
+				return err
+			}
+		}
+	}
+}
+
+// Close flushes and closes the active file; further writes fail
+func (s *FileSink) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.closeFile()
+}
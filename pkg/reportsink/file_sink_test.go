@@ -0,0 +1,105 @@
+package reportsink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// readLines returns the lines of path, failing the test unless each is a JSON object
+func readLines(t *testing.T, path string) []map[string]interface{} {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	var objects []map[string]interface{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var object map[string]interface{}
+		if err := json.Unmarshal(scanner.Bytes(), &object); err != nil {
+			t.Fatalf("line %q is not a JSON object: %v", scanner.Text(), err)
+		}
+		objects = append(objects, object)
+	}
+	assert.NoError(t, scanner.Err())
+	return objects
+}
+
+func TestFileSinkConsume(t *testing.T) {
+	dir := t.TempDir()
+	sink, err := NewFileSink(dir, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	reports := make(chan string)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- sink.Consume(ctx, reports) }()
+
+	for i := 0; i < 5; i++ {
+		reports <- fmt.Sprintf(`{"event_type":"monitoring","message":"report %d"}`, i)
+	}
+	cancel()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(time.Second):
+		t.Fatal("Consume did not return after cancellation")
+	}
+
+	objects := readLines(t, filepath.Join(dir, activeFile))
+	if !assert.Len(t, objects, 5) {
+		return
+	}
+	for i, object := range objects {
+		assert.Equal(t, fmt.Sprintf("report %d", i), object["message"])
+	}
+	assert.Error(t, sink.Write(`{"event_type":"late"}`), "writes after Consume returns must fail")
+}
+
+func TestFileSinkRotation(t *testing.T) {
+	dir := t.TempDir()
+	report := `{"event_type":"monitoring","message":"rotate"}`
+	// Two reports fit in a file, the third starts a new one
+	sink, err := NewFileSink(dir, int64(2*(len(report)+1)))
+	if !assert.NoError(t, err) {
+		return
+	}
+	for i := 0; i < 5; i++ {
+		assert.NoError(t, sink.Write(report))
+	}
+	assert.NoError(t, sink.Close())
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.jsonl"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, files, 3)
+	total := 0
+	for _, file := range files {
+		lines := readLines(t, file)
+		assert.LessOrEqual(t, len(lines), 2)
+		total += len(lines)
+	}
+	assert.Equal(t, 5, total)
+
+	// Reopening appends to the active file
+	sink, err = NewFileSink(dir, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NoError(t, sink.Write(report))
+	assert.NoError(t, sink.Close())
+	assert.Len(t, readLines(t, filepath.Join(dir, activeFile)), 2)
+}
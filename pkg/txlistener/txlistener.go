@@ -4,13 +4,20 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
 	"time"
 
 	contracttypes "github.com/ChoSanghyuk/blackholedex/pkg/types"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
 )
 
 var (
@@ -19,13 +26,59 @@ var (
 
 	// ErrTransactionFailed is returned when the transaction status is 0 (failed)
 	ErrTransactionFailed = errors.New("transaction failed")
+
+	// ErrReorged is returned when the block a transaction was mined in leaves the canonical chain
+	ErrReorged = errors.New("transaction reorged out")
 )
 
+// RPCCaller performs raw JSON-RPC calls. Satisfied by *rpc.Client
+type RPCCaller interface {
+	CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error
+}
+
+// RevertError is returned when a mined transaction has a failed status
+// errors.Is(err, ErrTransactionFailed) also matches a RevertError
+type RevertError struct {
+	TxHash  common.Hash
+	Status  string // Receipt status as reported by the node (e.g. "0x0")
+	GasUsed string // Gas consumed by the reverted transaction (hex)
+}
+
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("%v: transaction %s status is %s (gas used %s)", ErrTransactionFailed, e.TxHash.Hex(), e.Status, e.GasUsed)
+}
+
+func (e *RevertError) Unwrap() error {
+	return ErrTransactionFailed
+}
+
+// ReorgError is returned when a transaction's block is replaced while waiting for confirmations
+// The transaction may be back in the mempool or dropped; callers can resubmit it
+// errors.Is(err, ErrReorged) also matches a ReorgError
+type ReorgError struct {
+	TxHash      common.Hash
+	BlockNumber string      // Height the transaction was first mined at (hex)
+	BlockHash   common.Hash // Orphaned block the transaction was first mined in
+}
+
+func (e *ReorgError) Error() string {
+	return fmt.Sprintf("%v: transaction %s was in block %s (%s), which is no longer canonical", ErrReorged, e.TxHash.Hex(), e.BlockNumber, e.BlockHash.Hex())
+}
+
+func (e *ReorgError) Unwrap() error {
+	return ErrReorged
+}
+
 // TxListener waits for transactions to be mined on the blockchain
 type TxListener struct {
-	client       *ethclient.Client
+	client       RPCCaller
 	PollInterval time.Duration
 	Timeout      time.Duration
+	MaxRetries   int           // Retries allowed for transient RPC errors (0 = fail fast)
+	RetryDelay   time.Duration // Base delay for exponential backoff between retries
+
+	BatchConcurrency int // Maximum receipts polled in parallel by WaitForTransactions
+	Confirmations    int // Blocks, including the receipt's own, required before a receipt is returned (0 or 1 = as soon as mined)
 }
 
 // Option is a functional option for configuring TxListener
@@ -45,13 +98,48 @@ func WithTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithRetry retries receipt fetches that fail with transient RPC errors
+// The delay doubles after each attempt starting from baseDelay; the overall timeout still applies
+func WithRetry(maxRetries int, baseDelay time.Duration) Option {
+	return func(tl *TxListener) {
+		tl.MaxRetries = maxRetries
+		tl.RetryDelay = baseDelay
+	}
+}
+
+// WithBatchConcurrency sets how many receipts WaitForTransactions polls in parallel
+func WithBatchConcurrency(n int) Option {
+	return func(tl *TxListener) {
+		tl.BatchConcurrency = n
+	}
+}
+
+// WithConfirmations waits until the transaction's block is n blocks deep, counting the block itself
+// Fails with a *ReorgError if the block leaves the canonical chain before then
+func WithConfirmations(n int) Option {
+	return func(tl *TxListener) {
+		tl.Confirmations = n
+	}
+}
+
 // NewTxListener creates a new transaction listener with the given client and options
-// Default configuration: 2s poll interval, 5min timeout
+// Default configuration: 2s poll interval, 5min timeout, 4 concurrent batch polls
 func NewTxListener(client *ethclient.Client, opts ...Option) *TxListener {
+	var caller RPCCaller
+	if client != nil {
+		caller = client.Client()
+	}
+	return NewTxListenerWithCaller(caller, opts...)
+}
+
+// NewTxListenerWithCaller creates a transaction listener on top of a raw RPC caller
+func NewTxListenerWithCaller(client RPCCaller, opts ...Option) *TxListener {
 	tl := &TxListener{
 		client:       client,
 		PollInterval: 2 * time.Second, // Default 2s poll interval
 		Timeout:      5 * time.Minute, // Default 5min poll interval
+
+		BatchConcurrency: 4,
 	}
 
 	for _, opt := range opts {
@@ -66,28 +154,95 @@ func (tl *TxListener) WaitForTransaction(txHash common.Hash) (*contracttypes.TxR
 	ctx, cancel := context.WithTimeout(context.Background(), tl.Timeout)
 	defer cancel()
 
+	return tl.waitForTransaction(ctx, txHash)
+}
+
+// WaitForTransactions waits for several transactions concurrently and returns receipts in input order
+// At most BatchConcurrency receipts are polled at once and the timeout applies to the whole batch
+// Per-hash errors are joined; receipts of failed hashes may be nil
+func (tl *TxListener) WaitForTransactions(hashes []common.Hash) ([]*contracttypes.TxReceipt, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), tl.Timeout)
+	defer cancel()
+
+	concurrency := tl.BatchConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	receipts := make([]*contracttypes.TxReceipt, len(hashes))
+	errs := make([]error, len(hashes))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+
+	for i, txHash := range hashes {
+		wg.Add(1)
+		go func(i int, txHash common.Hash) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			receipts[i], errs[i] = tl.waitForTransaction(ctx, txHash)
+		}(i, txHash)
+	}
+	wg.Wait()
+
+	return receipts, errors.Join(errs...)
+}
+
+// waitForTransaction polls for the receipt of txHash until ctx is done
+func (tl *TxListener) waitForTransaction(ctx context.Context, txHash common.Hash) (*contracttypes.TxReceipt, error) {
 	ticker := time.NewTicker(tl.PollInterval)
 	defer ticker.Stop()
 
+	retries := 0
+	var mined *contracttypes.TxReceipt // First receipt seen while waiting for confirmations
 	for {
 		select {
 		case <-ctx.Done():
 			return nil, fmt.Errorf("%w: transaction %s not mined within %v", ErrTimeout, txHash.Hex(), tl.Timeout)
 
 		case <-ticker.C:
-			receipt, err := tl.getReceipt(txHash)
+			receipt, err := tl.getReceipt(ctx, txHash)
+			if tl.Confirmations > 1 {
+				// A receipt that vanished or moved means its block was orphaned
+				if mined != nil && (errors.Is(err, ethereum.NotFound) || (err == nil && receipt.BlockHash != mined.BlockHash)) {
+					return nil, &ReorgError{TxHash: txHash, BlockNumber: mined.BlockNumber, BlockHash: mined.BlockHash}
+				}
+				if err == nil {
+					mined = receipt
+					var depth uint64
+					depth, err = tl.confirmations(ctx, receipt)
+					if errors.Is(err, ErrReorged) {
+						return nil, err
+					}
+					if err == nil && depth < uint64(tl.Confirmations) {
+						continue
+					}
+				}
+			}
 			if err != nil {
 				// If receipt not found, continue polling
 				if errors.Is(err, ethereum.NotFound) {
 					continue
 				}
+				// Transient RPC errors are retried with exponential backoff
+				if retries < tl.MaxRetries && isTransientError(err) {
+					delay := tl.RetryDelay << retries
+					retries++
+					select {
+					case <-ctx.Done():
+						return nil, fmt.Errorf("%w: transaction %s not mined within %v (last error: %v)", ErrTimeout, txHash.Hex(), tl.Timeout, err)
+					case <-time.After(delay):
+					}
+					continue
+				}
 				// Other errors should be returned
 				return nil, fmt.Errorf("failed to get receipt for transaction %s: %w", txHash.Hex(), err)
 			}
 			fmt.Printf("%v\n", receipt)
 			// Receipt found - check if transaction was successful
 			if receipt.Status == "0x0" {
-				return receipt, fmt.Errorf("%w: transaction %s status is 0x0", ErrTransactionFailed, txHash.Hex())
+				return receipt, &RevertError{TxHash: txHash, Status: receipt.Status, GasUsed: receipt.GasUsed}
 			}
 			// time.Sleep(1 * time.Second) // memo. RPC State Lag 문제 해결.
 			return receipt, nil
@@ -96,13 +251,72 @@ func (tl *TxListener) WaitForTransaction(txHash common.Hash) (*contracttypes.TxR
 }
 
 // getReceipt retrieves the transaction receipt from the blockchain
-func (tl *TxListener) getReceipt(txHash common.Hash) (*contracttypes.TxReceipt, error) {
+func (tl *TxListener) getReceipt(ctx context.Context, txHash common.Hash) (*contracttypes.TxReceipt, error) {
 	var receipt *contracttypes.TxReceipt
 
-	err := tl.client.Client().CallContext(context.Background(), &receipt, "eth_getTransactionReceipt", txHash)
+	err := tl.client.CallContext(ctx, &receipt, "eth_getTransactionReceipt", txHash)
 	if err == nil && receipt == nil {
 		return nil, ethereum.NotFound
 	}
 
 	return receipt, err
 }
+
+// blockRef is the part of an eth_getBlockByNumber response used to check a receipt's block is canonical
+type blockRef struct {
+	Hash common.Hash `json:"hash"`
+}
+
+// confirmations returns how many blocks deep the receipt's block is, counting the block itself
+// Fails with a *ReorgError if the canonical block at the receipt's height is a different one
+func (tl *TxListener) confirmations(ctx context.Context, receipt *contracttypes.TxReceipt) (uint64, error) {
+	mined, err := hexutil.DecodeUint64(receipt.BlockNumber)
+	if err != nil {
+		return 0, fmt.Errorf("invalid receipt block number %q: %w", receipt.BlockNumber, err)
+	}
+	var block *blockRef
+	if err := tl.client.CallContext(ctx, &block, "eth_getBlockByNumber", receipt.BlockNumber, false); err != nil {
+		return 0, fmt.Errorf("failed to get block %s: %w", receipt.BlockNumber, err)
+	}
+	if block == nil {
+		// The node serving this call is behind the one that returned the receipt
+		return 0, nil
+	}
+	if block.Hash != receipt.BlockHash {
+		return 0, &ReorgError{TxHash: receipt.TxHash, BlockNumber: receipt.BlockNumber, BlockHash: receipt.BlockHash}
+	}
+
+	var latest hexutil.Uint64
+	if err := tl.client.CallContext(ctx, &latest, "eth_blockNumber"); err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	if uint64(latest) < mined {
+		// The node serving this call is behind the one that returned the receipt
+		return 0, nil
+	}
+	return uint64(latest) - mined + 1, nil
+}
+
+// isTransientError reports whether an RPC error is likely to succeed on retry
+// (timeouts, dropped connections, rate limiting and gateway errors)
+func isTransientError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var httpErr rpc.HTTPError
+	if errors.As(err, &httpErr) {
+		switch httpErr.StatusCode {
+		case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,341 @@
+package txlistener
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+
+	contracttypes "github.com/ChoSanghyuk/blackholedex/pkg/types"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockCaller fails the first `failures` receipt calls with err, then returns receipt
+type mockCaller struct {
+	mu       sync.Mutex
+	failures int
+	err      error
+	receipt  *contracttypes.TxReceipt
+	calls    int
+}
+
+func (m *mockCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if m.calls <= m.failures {
+		return m.err
+	}
+	*(result.(**contracttypes.TxReceipt)) = m.receipt
+	return nil
+}
+
+func (m *mockCaller) callCount() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.calls
+}
+
+func TestWaitForTransactionRetry(t *testing.T) {
+	txHash := common.HexToHash("0x01")
+	connReset := &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET}
+
+	t.Run("RetriesTransientErrors", func(t *testing.T) {
+		caller := &mockCaller{failures: 3, err: connReset, receipt: &contracttypes.TxReceipt{TxHash: txHash, Status: "0x1"}}
+		tl := NewTxListenerWithCaller(caller,
+			WithPollInterval(time.Millisecond),
+			WithTimeout(5*time.Second),
+			WithRetry(5, time.Millisecond),
+		)
+
+		receipt, err := tl.WaitForTransaction(txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, txHash, receipt.TxHash)
+		assert.Equal(t, 4, caller.callCount())
+	})
+
+	t.Run("GivesUpAfterMaxRetries", func(t *testing.T) {
+		caller := &mockCaller{failures: 10, err: connReset}
+		tl := NewTxListenerWithCaller(caller,
+			WithPollInterval(time.Millisecond),
+			WithTimeout(5*time.Second),
+			WithRetry(2, time.Millisecond),
+		)
+
+		_, err := tl.WaitForTransaction(txHash)
+		assert.ErrorIs(t, err, syscall.ECONNRESET)
+		assert.Equal(t, 3, caller.callCount())
+	})
+
+	t.Run("PermanentErrorNotRetried", func(t *testing.T) {
+		caller := &mockCaller{failures: 10, err: errors.New("invalid argument 0: hex string has length 2")}
+		tl := NewTxListenerWithCaller(caller,
+			WithPollInterval(time.Millisecond),
+			WithRetry(5, time.Millisecond),
+		)
+
+		_, err := tl.WaitForTransaction(txHash)
+		assert.Error(t, err)
+		assert.Equal(t, 1, caller.callCount())
+	})
+
+	t.Run("RespectsTimeout", func(t *testing.T) {
+		caller := &mockCaller{failures: 1000, err: connReset}
+		tl := NewTxListenerWithCaller(caller,
+			WithPollInterval(time.Millisecond),
+			WithTimeout(100*time.Millisecond),
+			WithRetry(100, 20*time.Millisecond),
+		)
+
+		start := time.Now()
+		_, err := tl.WaitForTransaction(txHash)
+		assert.ErrorIs(t, err, ErrTimeout)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestIsTransientError(t *testing.T) {
+	transient := []error{
+		&net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET},
+		fmt.Errorf("receipt: %w", context.DeadlineExceeded),
+		rpc.HTTPError{StatusCode: http.StatusTooManyRequests, Status: "429 Too Many Requests"},
+		fmt.Errorf("receipt: %w", rpc.HTTPError{StatusCode: http.StatusServiceUnavailable, Status: "503 Service Unavailable"}),
+	}
+	for _, err := range transient {
+		assert.True(t, isTransientError(err), "%v should be transient", err)
+	}
+
+	permanent := []error{
+		rpc.HTTPError{StatusCode: http.StatusUnauthorized, Status: "401 Unauthorized"},
+		// Status codes in messages are not matched as substrings
+		errors.New("invalid argument 0: hex string 0x503 has odd length"),
+	}
+	for _, err := range permanent {
+		assert.False(t, isTransientError(err), "%v should not be transient", err)
+	}
+}
+
+func TestWaitForTransactionRevert(t *testing.T) {
+	txHash := common.HexToHash("0x02")
+	caller := &mockCaller{receipt: &contracttypes.TxReceipt{TxHash: txHash, Status: "0x0", GasUsed: "0x5208"}}
+	tl := NewTxListenerWithCaller(caller, WithPollInterval(time.Millisecond))
+
+	receipt, err := tl.WaitForTransaction(txHash)
+	assert.NotNil(t, receipt)
+
+	wrapped := fmt.Errorf("mint transaction failed: %w", err)
+	var revertErr *RevertError
+	if assert.True(t, errors.As(wrapped, &revertErr)) {
+		assert.Equal(t, txHash, revertErr.TxHash)
+		assert.Equal(t, "0x0", revertErr.Status)
+		assert.Equal(t, "0x5208", revertErr.GasUsed)
+	}
+	assert.ErrorIs(t, wrapped, ErrTransactionFailed)
+}
+
+// delayedCaller returns each receipt only after its configured delay has elapsed
+type delayedCaller struct {
+	start    time.Time
+	delays   map[common.Hash]time.Duration
+	statuses map[common.Hash]string
+}
+
+func (d *delayedCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	txHash := args[0].(common.Hash)
+	if time.Since(d.start) < d.delays[txHash] {
+		return nil // not mined yet
+	}
+	status := d.statuses[txHash]
+	if status == "" {
+		status = "0x1"
+	}
+	*(result.(**contracttypes.TxReceipt)) = &contracttypes.TxReceipt{TxHash: txHash, Status: status, GasUsed: "0x5208"}
+	return nil
+}
+
+func TestWaitForTransactions(t *testing.T) {
+	hashes := []common.Hash{common.HexToHash("0x0a"), common.HexToHash("0x0b"), common.HexToHash("0x0c")}
+
+	t.Run("OrderedReceipts", func(t *testing.T) {
+		caller := &delayedCaller{
+			start: time.Now(),
+			delays: map[common.Hash]time.Duration{
+				hashes[0]: 60 * time.Millisecond,
+				hashes[1]: 10 * time.Millisecond,
+				hashes[2]: 30 * time.Millisecond,
+			},
+		}
+		tl := NewTxListenerWithCaller(caller, WithPollInterval(5*time.Millisecond), WithBatchConcurrency(2))
+
+		receipts, err := tl.WaitForTransactions(hashes)
+		assert.NoError(t, err)
+		assert.Len(t, receipts, len(hashes))
+		for i, receipt := range receipts {
+			assert.Equal(t, hashes[i], receipt.TxHash)
+		}
+	})
+
+	t.Run("JoinsPerHashErrors", func(t *testing.T) {
+		caller := &delayedCaller{
+			start:    time.Now(),
+			delays:   map[common.Hash]time.Duration{hashes[2]: time.Hour},
+			statuses: map[common.Hash]string{hashes[0]: "0x0"},
+		}
+		tl := NewTxListenerWithCaller(caller, WithPollInterval(5*time.Millisecond), WithTimeout(100*time.Millisecond))
+
+		receipts, err := tl.WaitForTransactions(hashes)
+		var revertErr *RevertError
+		assert.True(t, errors.As(err, &revertErr))
+		assert.Equal(t, hashes[0], revertErr.TxHash)
+		assert.ErrorIs(t, err, ErrTimeout)
+		assert.NotNil(t, receipts[1])
+		assert.Nil(t, receipts[2])
+	})
+}
+
+// rawCaller decodes a raw eth_getTransactionReceipt response the way the RPC client does
+type rawCaller struct {
+	response string
+}
+
+func (r *rawCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	return json.Unmarshal([]byte(r.response), result)
+}
+
+func TestWaitForTransactionLogs(t *testing.T) {
+	txHash := common.HexToHash("0x03")
+	pool := common.HexToAddress("0x41100c6d2c6920b10d12cd8d59c8a9aa2ef56fc7")
+	topic := common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+	caller := &rawCaller{response: `{
+		"transactionHash": "` + txHash.Hex() + `",
+		"status": "0x1",
+		"gasUsed": "0x5208",
+		"logs": [{
+			"address": "` + pool.Hex() + `",
+			"topics": ["` + topic.Hex() + `"],
+			"data": "0x2a",
+			"blockNumber": "0x10",
+			"transactionHash": "` + txHash.Hex() + `",
+			"transactionIndex": "0x0",
+			"blockHash": "0x0000000000000000000000000000000000000000000000000000000000000001",
+			"logIndex": "0x0",
+			"removed": false
+		}]
+	}`}
+	tl := NewTxListenerWithCaller(caller, WithPollInterval(time.Millisecond))
+
+	receipt, err := tl.WaitForTransaction(txHash)
+	assert.NoError(t, err)
+	if assert.Len(t, receipt.Logs, 1) {
+		assert.Equal(t, pool, receipt.Logs[0].Address)
+		assert.Equal(t, []common.Hash{topic}, receipt.Logs[0].Topics)
+		assert.Equal(t, []byte{0x2a}, receipt.Logs[0].Data)
+	}
+}
+
+// chainCaller mines txHash at minedAt and advances the head by one block on every receipt poll
+// From reorgAt on, the block at minedAt is replaced by one without the transaction; a lagging node
+// still returns the old receipt unless dropReceipt is set
+type chainCaller struct {
+	mu          sync.Mutex
+	txHash      common.Hash
+	minedAt     uint64
+	reorgAt     uint64
+	dropReceipt bool
+	head        uint64
+}
+
+func (c *chainCaller) reorged() bool {
+	return c.reorgAt > 0 && c.head >= c.reorgAt
+}
+
+func (c *chainCaller) CallContext(ctx context.Context, result interface{}, method string, args ...interface{}) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	blockHash := common.BigToHash(new(big.Int).SetUint64(c.minedAt))
+	switch method {
+	case "eth_blockNumber":
+		*(result.(*hexutil.Uint64)) = hexutil.Uint64(c.head)
+	case "eth_getBlockByNumber":
+		hash := blockHash
+		if c.reorged() {
+			hash = common.HexToHash("0xbad")
+		}
+		*(result.(**blockRef)) = &blockRef{Hash: hash}
+	case "eth_getTransactionReceipt":
+		c.head++
+		if c.head < c.minedAt || (c.reorged() && c.dropReceipt) {
+			return nil // not mined
+		}
+		*(result.(**contracttypes.TxReceipt)) = &contracttypes.TxReceipt{
+			TxHash:      c.txHash,
+			Status:      "0x1",
+			BlockHash:   blockHash,
+			BlockNumber: hexutil.EncodeUint64(c.minedAt),
+		}
+	}
+	return nil
+}
+
+func (c *chainCaller) latest() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head
+}
+
+func TestWaitForTransactionConfirmations(t *testing.T) {
+	txHash := common.HexToHash("0x04")
+
+	t.Run("WaitsForDepth", func(t *testing.T) {
+		caller := &chainCaller{txHash: txHash, minedAt: 1}
+		tl := NewTxListenerWithCaller(caller, WithPollInterval(time.Millisecond), WithConfirmations(3))
+
+		receipt, err := tl.WaitForTransaction(txHash)
+		assert.NoError(t, err)
+		assert.Equal(t, "0x1", receipt.BlockNumber)
+		assert.Equal(t, uint64(3), caller.latest(), "returned once block 1 is 3 blocks deep")
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		caller := &chainCaller{txHash: txHash, minedAt: 1}
+		tl := NewTxListenerWithCaller(caller,
+			WithPollInterval(time.Millisecond),
+			WithTimeout(50*time.Millisecond),
+			WithConfirmations(1_000_000),
+		)
+
+		_, err := tl.WaitForTransaction(txHash)
+		assert.ErrorIs(t, err, ErrTimeout)
+	})
+}
+
+func TestWaitForTransactionReorg(t *testing.T) {
+	txHash := common.HexToHash("0x05")
+
+	for _, dropReceipt := range []bool{false, true} {
+		caller := &chainCaller{txHash: txHash, minedAt: 1, reorgAt: 2, dropReceipt: dropReceipt}
+		tl := NewTxListenerWithCaller(caller, WithPollInterval(time.Millisecond), WithConfirmations(3))
+
+		receipt, err := tl.WaitForTransaction(txHash)
+		assert.Nil(t, receipt)
+		var reorgErr *ReorgError
+		if assert.ErrorAs(t, err, &reorgErr, "dropReceipt=%v", dropReceipt) {
+			assert.Equal(t, txHash, reorgErr.TxHash)
+			assert.Equal(t, "0x1", reorgErr.BlockNumber)
+			assert.Equal(t, common.BigToHash(big.NewInt(1)), reorgErr.BlockHash)
+		}
+		assert.ErrorIs(t, err, ErrReorged)
+		assert.Equal(t, uint64(2), caller.latest(), "detected at the first poll after the reorg")
+	}
+}
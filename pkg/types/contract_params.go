@@ -7,6 +7,8 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// Deadline fields throughout are Unix seconds, as compared against block.timestamp (see util.DeadlineFromNow)
+
 // Route represents a single swap route in the BlackholeDEX router
 // Matches the Solidity struct: IRouter.route
 type Route struct {
@@ -35,6 +37,16 @@ type SWAPExactTokensForTokensParams struct {
 	Deadline     *big.Int       `json:"deadline"`
 }
 
+// SWAPExactTokensForETHParams represents parameters for swapExactTokensForETH function
+// The last route must end at WAVAX, which the router unwraps to native AVAX for To
+type SWAPExactTokensForETHParams struct {
+	AmountIn     *big.Int       `json:"amountIn"`
+	AmountOutMin *big.Int       `json:"amountOutMin"`
+	Routes       []Route        `json:"routes"`
+	To           common.Address `json:"to"`
+	Deadline     *big.Int       `json:"deadline"`
+}
+
 // MintParams represents parameters for mint function in NonfungiblePositionManager
 // Matches the Solidity struct: INonfungiblePositionManager.MintParams
 type MintParams struct {
@@ -134,6 +146,12 @@ type AMMState struct {
 	PreviousTick    int32    `json:"previousTick"`    // int24 - Previous initialized tick
 }
 
+// FeeFraction returns the current dynamic fee as a fraction of the swapped amount
+// Algebra fees are in hundredths of a bip (1e-6), so a LastFee of 500 is 0.0005 (0.05%)
+func (s *AMMState) FeeFraction() float64 {
+	return float64(s.LastFee) / 1e6
+}
+
 // Liquidity Staking types
 
 // Unstake types
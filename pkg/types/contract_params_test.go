@@ -0,0 +1,13 @@
+package types
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAMMStateFeeFraction(t *testing.T) {
+	assert.InDelta(t, 0.0005, (&AMMState{LastFee: 500}).FeeFraction(), 1e-12)
+	assert.InDelta(t, 0.003, (&AMMState{LastFee: 3000}).FeeFraction(), 1e-12)
+	assert.Equal(t, 0.0, (&AMMState{}).FeeFraction())
+}
@@ -17,6 +17,101 @@ type TransactionRecord struct {
 	Operation string      // Operation type ("ApproveWAVAX", "ApproveUSDC", "Mint")
 }
 
+// GasTracker accumulates the gas cost of transaction records, in total and per Operation
+// Records without a GasCost are skipped. The zero value is an empty tracker
+type GasTracker struct {
+	total       *big.Int
+	byOperation map[string]*big.Int
+}
+
+// NewGasTracker returns a tracker holding records
+func NewGasTracker(records ...TransactionRecord) *GasTracker {
+	g := &GasTracker{}
+	g.AddAll(records)
+	return g
+}
+
+// Add adds the gas cost of record
+func (g *GasTracker) Add(record TransactionRecord) {
+	if record.GasCost == nil {
+		return
+	}
+	if g.total == nil {
+		g.total = new(big.Int)
+		g.byOperation = make(map[string]*big.Int)
+	}
+	g.total.Add(g.total, record.GasCost)
+	if total, ok := g.byOperation[record.Operation]; ok {
+		total.Add(total, record.GasCost)
+	} else {
+		g.byOperation[record.Operation] = new(big.Int).Set(record.GasCost)
+	}
+}
+
+// AddAll adds the gas cost of every record
+func (g *GasTracker) AddAll(records []TransactionRecord) {
+	for _, record := range records {
+		g.Add(record)
+	}
+}
+
+// Total returns the gas cost of all records added so far (wei)
+func (g *GasTracker) Total() *big.Int {
+	if g.total == nil {
+		return big.NewInt(0)
+	}
+	return new(big.Int).Set(g.total)
+}
+
+// ByOperation returns the gas cost per Operation (wei); the map is a copy
+func (g *GasTracker) ByOperation() map[string]*big.Int {
+	totals := make(map[string]*big.Int, len(g.byOperation))
+	for operation, total := range g.byOperation {
+		totals[operation] = new(big.Int).Set(total)
+	}
+	return totals
+}
+
+// AggregateGasByOperation sums the gas cost of records per Operation
+// Records without a GasCost are skipped
+func AggregateGasByOperation(records []TransactionRecord) map[string]*big.Int {
+	return NewGasTracker(records...).ByOperation()
+}
+
+// OperationState records the transactions a multi-step operation has sent, keyed by step (the
+// TransactionRecord Operation, e.g. "ApproveWAVAX"). Passed back to Mint, Stake or Unstake on retry,
+// it resumes the operation: a sent step is awaited instead of sent again, then checked on-chain
+// Serializable, so it can be persisted across restarts. A nil state records nothing
+type OperationState struct {
+	ID    string                 `json:"id"`
+	Steps map[string]common.Hash `json:"steps"`
+}
+
+// NewOperationState returns an empty state for the operation id
+func NewOperationState(id string) *OperationState {
+	return &OperationState{ID: id, Steps: make(map[string]common.Hash)}
+}
+
+// Record stores the transaction sent for step
+func (s *OperationState) Record(step string, txHash common.Hash) {
+	if s == nil {
+		return
+	}
+	if s.Steps == nil {
+		s.Steps = make(map[string]common.Hash)
+	}
+	s.Steps[step] = txHash
+}
+
+// Sent returns the transaction recorded for step, if any
+func (s *OperationState) Sent(step string) (common.Hash, bool) {
+	if s == nil {
+		return common.Hash{}, false
+	}
+	txHash, ok := s.Steps[step]
+	return txHash, ok
+}
+
 // StakingResult represents the complete output of staking operation
 type StakingResult struct {
 	NFTTokenID     *big.Int            // Liquidity position NFT token ID
@@ -28,6 +123,7 @@ type StakingResult struct {
 	TotalGasCost   *big.Int            // Sum of all gas costs (wei)
 	Success        bool                // Whether operation succeeded
 	ErrorMessage   string              // Error message if failed (empty if success)
+	DryRun         bool                // Result of an eth_call simulation; nothing was broadcast
 }
 
 // UnstakeResult represents the complete output of unstake operation
@@ -38,6 +134,7 @@ type UnstakeResult struct {
 	TotalGasCost *big.Int            // Sum of all gas costs (wei)
 	Success      bool                // Whether operation succeeded
 	ErrorMessage string              // Error message if failed (empty if success)
+	DryRun       bool                // Result of an eth_call simulation; nothing was broadcast
 }
 
 // Withdraw types
@@ -53,6 +150,14 @@ type WithdrawResult struct {
 	ErrorMessage string              // Error message if failed (empty if success)
 }
 
+// CollectFeesResult represents the output of a fee collection, including any conversion swap
+type CollectFeesResult struct {
+	NFTTokenID   *big.Int            // Position the fees were collected from
+	Amount0      *big.Int            // WAVAX collected (wei), after any conversion; nil if the amounts are unknown
+	Amount1      *big.Int            // USDC collected (smallest unit), after any conversion; nil if the amounts are unknown
+	Transactions []TransactionRecord // The collect transaction followed by any conversion swap
+}
+
 // RewardAmounts tracks rewards collected during unstake operation
 type RewardAmounts struct {
 	Reward           *big.Int       `json:"reward"`           // Primary reward amount
@@ -60,3 +165,22 @@ type RewardAmounts struct {
 	RewardToken      common.Address `json:"rewardToken"`      // Primary reward token address
 	BonusRewardToken common.Address `json:"bonusRewardToken"` // Bonus reward token address
 }
+
+// Rebalance types
+
+// RebalanceResult represents the complete output of a rebalance (unstake → withdraw → swap → mint → stake)
+// Completed steps are already on-chain and are not undone on failure: CompletedSteps and
+// FailedStep tell the caller where the funds are (e.g. in the wallet after a failed swap,
+// or in an unstaked NewNFTTokenID after a failed stake)
+type RebalanceResult struct {
+	OldNFTTokenID  *big.Int            // Position that was closed
+	NewNFTTokenID  *big.Int            // Position that was opened (nil if mint did not complete)
+	TickLower      int32               // Lower tick of the new position
+	TickUpper      int32               // Upper tick of the new position
+	CompletedSteps []string            // Steps completed, in order ("unstake", "withdraw", "swap", "mint", "stake")
+	FailedStep     string              // Step that failed (empty if success)
+	Transactions   []TransactionRecord // All transactions executed
+	TotalGasCost   *big.Int            // Sum of all gas costs (wei)
+	Success        bool                // Whether every step succeeded
+	ErrorMessage   string              // Error message if failed (empty if success)
+}
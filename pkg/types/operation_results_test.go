@@ -0,0 +1,66 @@
+package types
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregateGasByOperation(t *testing.T) {
+	records := []TransactionRecord{
+		{Operation: "ApproveWAVAX", GasCost: big.NewInt(100)},
+		{Operation: "ApproveUSDC", GasCost: big.NewInt(120)},
+		{Operation: "Mint", GasCost: big.NewInt(900)},
+		{Operation: "ApproveWAVAX", GasCost: big.NewInt(110)},
+		{Operation: "Mint", GasCost: big.NewInt(950)},
+		{Operation: "Swap"}, // no gas cost recorded
+	}
+
+	totals := AggregateGasByOperation(records)
+
+	assert.Len(t, totals, 3)
+	assert.Equal(t, "210", totals["ApproveWAVAX"].String())
+	assert.Equal(t, "120", totals["ApproveUSDC"].String())
+	assert.Equal(t, "1850", totals["Mint"].String())
+	// The input records are not modified
+	assert.Equal(t, "100", records[0].GasCost.String())
+}
+
+func TestStrategyStateRecordGas(t *testing.T) {
+	state := &StrategyState{}
+	state.RecordGas([]TransactionRecord{{Operation: "Mint", GasCost: big.NewInt(900)}})
+	state.RecordGas([]TransactionRecord{
+		{Operation: "Mint", GasCost: big.NewInt(100)},
+		{Operation: "Withdraw", GasCost: big.NewInt(300)},
+	})
+
+	assert.Equal(t, "1000", state.GasByOperation["Mint"].String())
+	assert.Equal(t, "300", state.GasByOperation["Withdraw"].String())
+	assert.Equal(t, "1300", state.CumulativeGas.String())
+}
+
+func TestGasTracker(t *testing.T) {
+	var tracker GasTracker
+	assert.Equal(t, "0", tracker.Total().String())
+	assert.Empty(t, tracker.ByOperation())
+
+	tracker.Add(TransactionRecord{Operation: "ApproveWAVAX", GasCost: big.NewInt(100)})
+	tracker.Add(TransactionRecord{Operation: "ApproveWAVAX", GasCost: big.NewInt(110)})
+	tracker.AddAll([]TransactionRecord{
+		{Operation: "Mint", GasCost: big.NewInt(900)},
+		{Operation: "Swap"}, // no gas cost recorded
+	})
+
+	byOperation := tracker.ByOperation()
+	assert.Len(t, byOperation, 2)
+	assert.Equal(t, "210", byOperation["ApproveWAVAX"].String())
+	assert.Equal(t, "900", byOperation["Mint"].String())
+	assert.Equal(t, "1110", tracker.Total().String())
+
+	// Returned values are copies
+	byOperation["Mint"].SetInt64(0)
+	tracker.Total().SetInt64(0)
+	assert.Equal(t, "900", tracker.ByOperation()["Mint"].String())
+	assert.Equal(t, "1110", tracker.Total().String())
+}
@@ -51,3 +51,24 @@ func (p PoolType) TickSpacing() int {
 		return 200
 	}
 }
+
+// PositionLocation is where a position NFT is held (see GetPositionLocation)
+type PositionLocation int
+
+const (
+	// InWallet: Held by the wallet and not farmed; can be withdrawn directly
+	InWallet PositionLocation = iota
+	// InGauge: Deposited in the GaugeV2 contract, which holds the NFT
+	InGauge
+	// InFarming: Entered into FarmingCenter farming; must be unstaked before withdrawing
+	InFarming
+)
+
+// String returns human-readable location name
+func (pl PositionLocation) String() string {
+	return [...]string{
+		"InWallet",
+		"InGauge",
+		"InFarming",
+	}[pl]
+}
@@ -1,5 +1,7 @@
 package types
 
+import "math/big"
+
 type Priority uint8
 
 const (
@@ -7,3 +9,23 @@ const (
 	Standard
 	High
 )
+
+// GasStrategy selects how a transaction is priced. GasStandard is the default
+// It is independent of Priority, which only scales the gas limit
+type GasStrategy uint8
+
+const (
+	GasStandard GasStrategy = iota
+	GasSlow
+	GasFast
+	GasCustom // Gas parameters come from the client's CustomGas configuration
+)
+
+// CustomGas configures the GasCustom strategy
+// Exactly one pricing mode is used, checked in order: EIP-1559 caps, fixed gas price, multiplier
+type CustomGas struct {
+	GasTipCap  *big.Int // EIP-1559 max priority fee per gas (wei)
+	GasFeeCap  *big.Int // EIP-1559 max fee per gas (wei)
+	GasPrice   *big.Int // Fixed gas price used as both tip and fee cap (wei)
+	Multiplier float64  // Fee cap as a multiple of the node's suggested gas price
+}
@@ -16,6 +16,8 @@ type StrategyConfig struct {
 	StabilityThreshold float64
 	// StabilityIntervals specifies consecutive stable intervals required before re-entry (default: 5, minimum: 3)
 	StabilityIntervals int
+	// MaxCumulativeDrift defines max total price change % over the stability window, catching slow trends (default: 0.01 = 1%, 0 = disabled)
+	MaxCumulativeDrift float64
 	// RangeWidth defines position tick width, e.g. 10 = ±5 ticks from center (default: 10, must be even)
 	RangeWidth int
 	// SlippagePct defines slippage tolerance percentage (default: 1%, range: 1-5%)
@@ -28,10 +30,51 @@ type StrategyConfig struct {
 	CircuitBreakerWindow time.Duration
 	// CircuitBreakerThreshold defines max errors allowed in window before halting (default: 5, minimum: 3)
 	CircuitBreakerThreshold int
+	// CodeHashCheckInterval defines how often deployed contract code is compared against startup hashes (default: 1 hour, 0 disables)
+	CodeHashCheckInterval time.Duration
+	// GasTopUpFloor is the native AVAX balance in wei below which WAVAX is unwrapped for gas (default: nil = disabled)
+	GasTopUpFloor *big.Int
+	// GasTopUpAmount is the amount of WAVAX in wei unwrapped per top-up (required when GasTopUpFloor is set)
+	GasTopUpAmount *big.Int
+	// SnapshotInterval defines the minimum time between asset snapshots recorded on monitoring ticks (default: 2 hours, 0 = every tick)
+	SnapshotInterval time.Duration
+	// NearEdgeThreshold sends a near_edge report when the tick comes within this fraction of the range width of either bound (default: 0.1 = 10%, 0 = disabled)
+	NearEdgeThreshold float64
+	// WithdrawOnShutdown unstakes and withdraws the position to the wallet when the strategy's context is cancelled (default: false = leave it staked)
+	WithdrawOnShutdown bool
+	// AutoReentry resumes a strategy halted with its funds withdrawn once CircuitBreakerWindow passes without errors, re-entering after the stability wait (default: false = halt and return)
+	AutoReentry bool
+	// MaxPriceImpact is the largest price impact, in percent, accepted for the swap before entering a position; larger swaps are shrunk or skipped (default: 1 = 1%, 0 = disabled)
+	MaxPriceImpact float64
+	// ReportVerbosity selects which per-interval reports are sent; state changes, errors and profits are always reported (default: Normal)
+	ReportVerbosity ReportVerbosity
+	// MinRewardThreshold is the pending BLACK reward, in wei, a position must have beyond the estimated rebalance gas before it is rebalanced (default: nil = always rebalance)
+	MinRewardThreshold *big.Int
 
 	// InitPhase StrategyPhase
 }
 
+// ReportVerbosity controls how many per-interval reports the strategy sends
+type ReportVerbosity int
+
+const (
+	// Normal: Stability progress and near-edge warnings are reported, price checks are only logged
+	Normal ReportVerbosity = iota
+	// Quiet: Only state-changing events are reported
+	Quiet
+	// Verbose: Every price check sends a monitoring report with a PositionSnapshot
+	Verbose
+)
+
+// String returns human-readable verbosity name
+func (rv ReportVerbosity) String() string {
+	return [...]string{
+		"Normal",
+		"Quiet",
+		"Verbose",
+	}[rv]
+}
+
 // DefaultStrategyConfig returns a StrategyConfig with sensible defaults
 // User must still set MaxWAVAX and MaxUSDC based on their wallet balance
 func DefaultStrategyConfig() *StrategyConfig {
@@ -39,12 +82,17 @@ func DefaultStrategyConfig() *StrategyConfig {
 		MonitoringInterval: 60 * time.Second, // Constitutional minimum
 		StabilityThreshold: 0.005,            // 0.5% price change
 		StabilityIntervals: 5,                // 5 consecutive stable intervals
+		MaxCumulativeDrift: 0.01,             // 1% total change over the window
 		RangeWidth:         10,               // ±5 ticks from center
 		SlippagePct:        5,                // 1% slippage tolerance
 		// MaxWAVAX:                nil,              // Must be set by user
 		// MaxUSDC:                 nil,              // Must be set by user
 		CircuitBreakerWindow:    5 * time.Minute, // 5-minute error window
 		CircuitBreakerThreshold: 5,               // 5 errors before halt
+		CodeHashCheckInterval:   time.Hour,       // Check for contract upgrades hourly
+		SnapshotInterval:        2 * time.Hour,   // Asset snapshot every 2 hours
+		NearEdgeThreshold:       0.1,             // Warn within 10% of the range width from a bound
+		MaxPriceImpact:          1,               // Shrink entry swaps that would move the price more than 1%
 		// InitPhase:               Initializing,
 	}
 }
@@ -67,6 +115,11 @@ func (sc *StrategyConfig) Validate() error {
 		return fmt.Errorf("StabilityIntervals must be >= 3, got %d", sc.StabilityIntervals)
 	}
 
+	// MaxCumulativeDrift must be 0 (disabled) or at least StabilityThreshold
+	if sc.MaxCumulativeDrift < 0 || (sc.MaxCumulativeDrift > 0 && sc.MaxCumulativeDrift < sc.StabilityThreshold) {
+		return fmt.Errorf("MaxCumulativeDrift must be 0 or >= StabilityThreshold (%f), got %f", sc.StabilityThreshold, sc.MaxCumulativeDrift)
+	}
+
 	// RangeWidth must be even and > 0
 	if sc.RangeWidth <= 0 || sc.RangeWidth%2 != 0 {
 		return fmt.Errorf("RangeWidth must be even and > 0, got %d", sc.RangeWidth)
@@ -97,40 +150,99 @@ func (sc *StrategyConfig) Validate() error {
 		return fmt.Errorf("CircuitBreakerThreshold must be >= 3, got %d", sc.CircuitBreakerThreshold)
 	}
 
+	// CodeHashCheckInterval must be >= 0 (0 disables the check)
+	if sc.CodeHashCheckInterval < 0 {
+		return fmt.Errorf("CodeHashCheckInterval must be >= 0, got %v", sc.CodeHashCheckInterval)
+	}
+
+	// SnapshotInterval must be >= 0 (0 records on every monitoring tick)
+	if sc.SnapshotInterval < 0 {
+		return fmt.Errorf("SnapshotInterval must be >= 0, got %v", sc.SnapshotInterval)
+	}
+
+	// MaxPriceImpact must be in [0, 100); 0 disables the check
+	if sc.MaxPriceImpact < 0 || sc.MaxPriceImpact >= 100 {
+		return fmt.Errorf("MaxPriceImpact must be in range [0, 100), got %f", sc.MaxPriceImpact)
+	}
+
+	// MinRewardThreshold must not be negative; nil disables the check
+	if sc.MinRewardThreshold != nil && sc.MinRewardThreshold.Sign() < 0 {
+		return fmt.Errorf("MinRewardThreshold must be >= 0, got %s", sc.MinRewardThreshold)
+	}
+
+	// ReportVerbosity must be one of the defined levels
+	if sc.ReportVerbosity < Normal || sc.ReportVerbosity > Verbose {
+		return fmt.Errorf("ReportVerbosity must be Normal, Quiet or Verbose, got %d", sc.ReportVerbosity)
+	}
+
+	// NearEdgeThreshold must be in [0, 0.5); at 0.5 every in-range tick would be near an edge
+	if sc.NearEdgeThreshold < 0 || sc.NearEdgeThreshold >= 0.5 {
+		return fmt.Errorf("NearEdgeThreshold must be in range [0, 0.5), got %f", sc.NearEdgeThreshold)
+	}
+
+	// GasTopUpAmount must be > 0 when gas top-up is enabled
+	if sc.GasTopUpFloor != nil && sc.GasTopUpFloor.Sign() > 0 &&
+		(sc.GasTopUpAmount == nil || sc.GasTopUpAmount.Sign() <= 0) {
+		return fmt.Errorf("GasTopUpAmount must be > 0 when GasTopUpFloor is set")
+	}
+
 	return nil
 }
 
 // StrategyState tracks the current operational state and position information during strategy execution
 type StrategyState struct {
-	CurrentState      StrategyPhase // Current phase of execution
-	CurrentStep       StrategyStep  // Current substep within the phase (for checkpoint/resume)
-	NFTTokenID        *big.Int      // Active position NFT ID
-	TickLower         int32         // Active position lower bound
-	TickUpper         int32         // Active position upper bound
-	LastPrice         *big.Int      // Last observed pool price (sqrtPrice)
-	StableCount       int           // Consecutive stable intervals counted
-	CumulativeGas     *big.Int      // Total gas spent (wei)
-	CumulativeRewards *big.Int      // Total rewards collected (BLACK tokens)
-	TotalSwapFees     *big.Int      // Cumulative swap fees paid
-	ErrorCount        int           // Errors in current circuit breaker window
-	LastErrorTime     time.Time     // Timestamp of most recent error
-	StartTime         time.Time     // Strategy start timestamp
-	PositionCreatedAt time.Time     // When current position was created
+	CurrentState      StrategyPhase       // Current phase of execution
+	CurrentStep       StrategyStep        // Current substep within the phase (for checkpoint/resume)
+	NFTTokenID        *big.Int            // Active position NFT ID
+	TickLower         int32               // Active position lower bound
+	TickUpper         int32               // Active position upper bound
+	LastPrice         *big.Int            // Last observed pool price (sqrtPrice)
+	StableCount       int                 // Consecutive stable intervals counted
+	CumulativeGas     *big.Int            // Total gas spent (wei)
+	CumulativeRewards *big.Int            // Total rewards collected (BLACK tokens)
+	TotalSwapFees     *big.Int            // Cumulative swap fees paid
+	GasByOperation    map[string]*big.Int // Total gas spent per TransactionRecord.Operation (wei)
+	ErrorCount        int                 // Errors in current circuit breaker window
+	LastErrorTime     time.Time           // Timestamp of most recent error
+	StartTime         time.Time           // Strategy start timestamp
+	PositionCreatedAt time.Time           // When current position was created
+	NearEdge          bool                // A near_edge report was sent and the tick has not moved away since
+}
+
+// RecordGas adds the gas cost of records to CumulativeGas and GasByOperation
+// Both totals come from the same records, so they always agree
+func (s *StrategyState) RecordGas(records []TransactionRecord) {
+	batch := NewGasTracker(records...)
+	if s.CumulativeGas == nil {
+		s.CumulativeGas = new(big.Int)
+	}
+	s.CumulativeGas = new(big.Int).Add(s.CumulativeGas, batch.Total())
+	if s.GasByOperation == nil {
+		s.GasByOperation = make(map[string]*big.Int)
+	}
+	for operation, cost := range batch.ByOperation() {
+		if total, ok := s.GasByOperation[operation]; ok {
+			s.GasByOperation[operation] = new(big.Int).Add(total, cost)
+		} else {
+			s.GasByOperation[operation] = cost
+		}
+	}
 }
 
 // StrategyReport represents a structured message sent via the reporting channel
 type StrategyReport struct {
-	Timestamp       time.Time         `json:"timestamp"`
-	EventType       string            `json:"event_type"`
-	Message         string            `json:"message"`
-	Phase           *StrategyPhase    `json:"phase,omitempty"`
-	GasCost         *big.Int          `json:"gas_cost,omitempty"`
-	CumulativeGas   *big.Int          `json:"cumulative_gas,omitempty"`
-	Profit          *big.Int          `json:"profit,omitempty"`
-	NetPnL          *big.Int          `json:"net_pnl,omitempty"`
-	Error           string            `json:"error,omitempty"`
-	NFTTokenID      *big.Int          `json:"nft_token_id,omitempty"`
-	PositionDetails *PositionSnapshot `json:"position_details,omitempty"`
+	Timestamp       time.Time           `json:"timestamp"`
+	EventType       string              `json:"event_type"`
+	Message         string              `json:"message"`
+	Phase           *StrategyPhase      `json:"phase,omitempty"`
+	GasCost         *big.Int            `json:"gas_cost,omitempty"`
+	CumulativeGas   *big.Int            `json:"cumulative_gas,omitempty"`
+	Profit          *big.Int            `json:"profit,omitempty"`
+	NetPnL          *big.Int            `json:"net_pnl,omitempty"`
+	Error           string              `json:"error,omitempty"`
+	NFTTokenID      *big.Int            `json:"nft_token_id,omitempty"`
+	PositionDetails *PositionSnapshot   `json:"position_details,omitempty"`
+	GasByOperation  map[string]*big.Int `json:"gas_by_operation,omitempty"`
 }
 
 // ToJSON serializes StrategyReport to JSON string (T009)
@@ -142,6 +254,111 @@ func (sr *StrategyReport) ToJSON() (string, error) {
 	return string(bytes), nil
 }
 
+// strategyReportJSON shadows the *big.Int fields of StrategyReport with decimal strings
+// so values above 2^53 survive JSON consumers that parse numbers as float64
+type strategyReportJSON struct {
+	*strategyReportAlias
+	GasCost       *string `json:"gas_cost,omitempty"`
+	CumulativeGas *string `json:"cumulative_gas,omitempty"`
+	Profit        *string `json:"profit,omitempty"`
+	NetPnL        *string `json:"net_pnl,omitempty"`
+	NFTTokenID    *string `json:"nft_token_id,omitempty"`
+
+	GasByOperation map[string]string `json:"gas_by_operation,omitempty"`
+}
+
+type strategyReportAlias StrategyReport
+
+// MarshalJSON renders the *big.Int fields as decimal strings
+func (sr StrategyReport) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strategyReportJSON{
+		strategyReportAlias: (*strategyReportAlias)(&sr),
+		GasCost:             bigToString(sr.GasCost),
+		CumulativeGas:       bigToString(sr.CumulativeGas),
+		Profit:              bigToString(sr.Profit),
+		NetPnL:              bigToString(sr.NetPnL),
+		NFTTokenID:          bigToString(sr.NFTTokenID),
+		GasByOperation:      bigMapToStrings(sr.GasByOperation),
+	})
+}
+
+// UnmarshalJSON parses the decimal string fields written by MarshalJSON
+func (sr *StrategyReport) UnmarshalJSON(data []byte) error {
+	aux := strategyReportJSON{strategyReportAlias: (*strategyReportAlias)(sr)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	var err error
+	if sr.GasCost, err = stringToBig(aux.GasCost); err != nil {
+		return fmt.Errorf("invalid gas_cost: %w", err)
+	}
+	if sr.CumulativeGas, err = stringToBig(aux.CumulativeGas); err != nil {
+		return fmt.Errorf("invalid cumulative_gas: %w", err)
+	}
+	if sr.Profit, err = stringToBig(aux.Profit); err != nil {
+		return fmt.Errorf("invalid profit: %w", err)
+	}
+	if sr.NetPnL, err = stringToBig(aux.NetPnL); err != nil {
+		return fmt.Errorf("invalid net_pnl: %w", err)
+	}
+	if sr.NFTTokenID, err = stringToBig(aux.NFTTokenID); err != nil {
+		return fmt.Errorf("invalid nft_token_id: %w", err)
+	}
+	if sr.GasByOperation, err = stringsToBigMap(aux.GasByOperation); err != nil {
+		return fmt.Errorf("invalid gas_by_operation: %w", err)
+	}
+	return nil
+}
+
+// bigToString formats v as a decimal string, or nil when v is nil
+func bigToString(v *big.Int) *string {
+	if v == nil {
+		return nil
+	}
+	s := v.String()
+	return &s
+}
+
+// bigMapToStrings formats every value of m as a decimal string, or returns nil when m is empty
+func bigMapToStrings(m map[string]*big.Int) map[string]string {
+	if len(m) == 0 {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		out[k] = v.String()
+	}
+	return out
+}
+
+// stringsToBigMap parses the decimal string values of m, or returns nil when m is empty
+func stringsToBigMap(m map[string]string) (map[string]*big.Int, error) {
+	if len(m) == 0 {
+		return nil, nil
+	}
+	out := make(map[string]*big.Int, len(m))
+	for k, s := range m {
+		v, err := stringToBig(&s)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", k, err)
+		}
+		out[k] = v
+	}
+	return out, nil
+}
+
+// stringToBig parses a decimal string, or returns nil when s is nil
+func stringToBig(s *string) (*big.Int, error) {
+	if s == nil {
+		return nil, nil
+	}
+	v, ok := new(big.Int).SetString(*s, 10)
+	if !ok {
+		return nil, fmt.Errorf("not a decimal integer: %q", *s)
+	}
+	return v, nil
+}
+
 // StrategyPhase represents the current execution phase of RunStrategy1
 type StrategyPhase int
 
@@ -158,6 +375,8 @@ const (
 	// ExecutingRebalancing
 	// Halted: Strategy stopped due to error or shutdown signal
 	Halted
+	// Paused: Operator paused the strategy; no operations run and the position is held as-is
+	Paused
 )
 
 // String returns human-readable phase name
@@ -168,6 +387,7 @@ func (sp StrategyPhase) String() string {
 		"RebalancingRequired",
 		"WaitingForStability",
 		"Halted",
+		"Paused",
 	}[sp]
 }
 
@@ -196,6 +416,33 @@ func (ss StrategyStep) String() string {
 	}[ss]
 }
 
+// StrategyAction is the decision the strategy makes for a pool state (see EvaluateStrategyStep)
+type StrategyAction int
+
+const (
+	// Hold: Nothing to do this interval
+	Hold StrategyAction = iota
+	// Rebalance: Position is out of range; withdraw and wait for stability
+	Rebalance
+	// Reenter: No position is open and price is stable; create a new position
+	Reenter
+	// EmergencyExit: Price gapped far past the position; withdraw without re-entering
+	EmergencyExit
+	// Pause: Operator paused the strategy; the position is held as-is
+	Pause
+)
+
+// String returns human-readable action name
+func (sa StrategyAction) String() string {
+	return [...]string{
+		"Hold",
+		"Rebalance",
+		"Reenter",
+		"EmergencyExit",
+		"Pause",
+	}[sa]
+}
+
 // PositionSnapshot captures position details at a point in time
 type CurrentAssetSnapshot struct {
 	Timestamp     time.Time
@@ -206,6 +453,13 @@ type CurrentAssetSnapshot struct {
 	AmountUsdc    *big.Int
 	AmountBlack   *big.Int
 	AmountAvax    *big.Int
+	// Strategy-wide running totals at snapshot time (zero outside RunStrategy1)
+	CumulativeGas     *big.Int // Total gas spent (wei)
+	CumulativeRewards *big.Int // Total rewards collected (BLACK tokens)
+	// Active position at snapshot time (NFTTokenID is nil when no position is open)
+	NFTTokenID *big.Int
+	TickLower  int32
+	TickUpper  int32
 }
 
 type PositionSnapshot struct {
@@ -251,6 +505,18 @@ func (pr *PositionRange) Width() int32 {
 	return pr.TickUpper - pr.TickLower
 }
 
+// DistanceToEdge returns how many ticks currentTick is above TickLower and below TickUpper,
+// and the smaller of the two as a fraction of Width (0.5 at the center, 0 at a bound)
+// Distances and the fraction are negative once currentTick is out of range
+func (pr *PositionRange) DistanceToEdge(currentTick int32) (toLower int32, toUpper int32, nearestPct float64) {
+	toLower = currentTick - pr.TickLower
+	toUpper = pr.TickUpper - currentTick
+	if width := pr.Width(); width > 0 {
+		nearestPct = float64(min(toLower, toUpper)) / float64(width)
+	}
+	return toLower, toUpper, nearestPct
+}
+
 // Center returns the center tick of this range (T011)
 func (pr *PositionRange) Center() int32 {
 	return (pr.TickLower + pr.TickUpper) / 2
@@ -258,50 +524,62 @@ func (pr *PositionRange) Center() int32 {
 
 // StabilityWindow implements the price stability detection algorithm
 type StabilityWindow struct {
-	Threshold         float64  // Maximum acceptable price change (0.005 = 0.5%)
-	RequiredIntervals int      // Number of consecutive stable intervals needed
-	LastPrice         *big.Int // Previous interval's price (sqrtPrice from AMMState)
-	StableCount       int      // Current count of consecutive stable intervals
+	Threshold          float64  // Maximum acceptable price change per interval (0.005 = 0.5%)
+	RequiredIntervals  int      // Number of consecutive stable intervals needed
+	MaxCumulativeDrift float64  // Maximum price change since WindowStartPrice (0 = disabled)
+	LastPrice          *big.Int // Previous interval's price (sqrtPrice from AMMState)
+	WindowStartPrice   *big.Int // Price at the start of the current stable streak
+	StableCount        int      // Current count of consecutive stable intervals
 }
 
 // CheckStability evaluates whether current price is stable (T012)
 // Returns true if price has been stable for RequiredIntervals consecutive checks
-// Resets counter if price change exceeds Threshold
+// Resets counter if price change exceeds Threshold, or if the total change since the
+// streak started exceeds MaxCumulativeDrift (a slow trend made of small steps)
 // Uses sliding window algorithm from research.md R2
 func (sw *StabilityWindow) CheckStability(currentPrice *big.Int) bool {
 	if sw.LastPrice == nil {
 		sw.LastPrice = new(big.Int).Set(currentPrice)
+		sw.WindowStartPrice = new(big.Int).Set(currentPrice)
 		sw.StableCount = 1
 		return false
 	}
+	if sw.WindowStartPrice == nil {
+		sw.WindowStartPrice = new(big.Int).Set(sw.LastPrice)
+	}
 
-	// Calculate percentage change: |currentPrice - lastPrice| / lastPrice
-	diff := new(big.Int).Sub(currentPrice, sw.LastPrice)
-	absDiff := new(big.Int).Abs(diff)
-
-	// Convert to float64 for percentage calculation
-	pctChange := new(big.Float).Quo(
-		new(big.Float).SetInt(absDiff),
-		new(big.Float).SetInt(sw.LastPrice),
-	)
-	pctChangeFloat, _ := pctChange.Float64()
+	pctChangeFloat := relativeChange(sw.LastPrice, currentPrice)
+	drifting := sw.MaxCumulativeDrift > 0 && relativeChange(sw.WindowStartPrice, currentPrice) > sw.MaxCumulativeDrift
 
-	if math.Abs(pctChangeFloat) <= sw.Threshold {
+	if math.Abs(pctChangeFloat) <= sw.Threshold && !drifting {
 		sw.StableCount++
 		if sw.StableCount >= sw.RequiredIntervals {
 			return true // Stable!
 		}
 	} else {
-		sw.StableCount = 0 // Reset on volatility
+		sw.StableCount = 0 // Reset on volatility or drift
+		sw.WindowStartPrice = new(big.Int).Set(currentPrice)
 	}
 
 	sw.LastPrice = new(big.Int).Set(currentPrice)
 	return false
 }
 
+// relativeChange returns |to - from| / from
+func relativeChange(from, to *big.Int) float64 {
+	absDiff := new(big.Int).Abs(new(big.Int).Sub(to, from))
+	pctChange := new(big.Float).Quo(
+		new(big.Float).SetInt(absDiff),
+		new(big.Float).SetInt(from),
+	)
+	pctChangeFloat, _ := pctChange.Float64()
+	return pctChangeFloat
+}
+
 // Reset clears the stability window state (T012)
 func (sw *StabilityWindow) Reset() {
 	sw.LastPrice = nil
+	sw.WindowStartPrice = nil
 	sw.StableCount = 0
 }
 
@@ -369,3 +647,24 @@ func (cb *CircuitBreaker) ErrorRate() float64 {
 	hoursInWindow := cb.ErrorWindow.Hours()
 	return float64(len(cb.LastErrors)) / hoursInWindow
 }
+
+// PnLPrices are the prices DecomposePnL values amounts at, in USDC smallest units per smallest token unit
+type PnLPrices struct {
+	EntryWAVAX  *big.Float // WAVAX price when the position was opened
+	ExitWAVAX   *big.Float // WAVAX price when the position was closed (also values fees)
+	Reward      *big.Float // Reward token price (nil values rewards at zero)
+	BonusReward *big.Float // Bonus reward token price (nil values bonus rewards at zero)
+}
+
+// PnLBreakdown separates a closed position's result into its sources, in USDC smallest units
+// NetPnL = PriceChange - ImpermanentLoss + FeeIncome + RewardIncome
+type PnLBreakdown struct {
+	EntryValue      *big.Float `json:"entry_value"`      // Entry amounts at the entry price
+	ExitValue       *big.Float `json:"exit_value"`       // Exit amounts at the exit price
+	HoldValue       *big.Float `json:"hold_value"`       // Entry amounts at the exit price
+	PriceChange     *big.Float `json:"price_change"`     // HoldValue - EntryValue
+	ImpermanentLoss *big.Float `json:"impermanent_loss"` // HoldValue - ExitValue (positive is a loss)
+	FeeIncome       *big.Float `json:"fee_income"`       // Collected swap fees
+	RewardIncome    *big.Float `json:"reward_income"`    // Farming rewards
+	NetPnL          *big.Float `json:"net_pnl"`          // ExitValue - EntryValue + FeeIncome + RewardIncome
+}
@@ -0,0 +1,134 @@
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStrategyReportJSONRoundTrip(t *testing.T) {
+	// Well above 2^53, where float64 JSON decoders lose precision
+	cumulativeGas, _ := new(big.Int).SetString("123456789012345678901234567", 10)
+	phase := ActiveMonitoring
+	report := StrategyReport{
+		Timestamp:     time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+		EventType:     "gas_cost",
+		Message:       "stake",
+		Phase:         &phase,
+		GasCost:       big.NewInt(21000),
+		CumulativeGas: cumulativeGas,
+		GasByOperation: map[string]*big.Int{
+			"Mint": big.NewInt(900),
+		},
+	}
+
+	jsonStr, err := report.ToJSON()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Contains(t, jsonStr, `"cumulative_gas":"123456789012345678901234567"`)
+	assert.Contains(t, jsonStr, `"gas_cost":"21000"`)
+	// nil omitempty fields are left out
+	assert.NotContains(t, jsonStr, "profit")
+	assert.NotContains(t, jsonStr, "nft_token_id")
+	assert.NotContains(t, jsonStr, "position_details")
+
+	var decoded StrategyReport
+	if !assert.NoError(t, json.Unmarshal([]byte(jsonStr), &decoded)) {
+		return
+	}
+	assert.Equal(t, 0, cumulativeGas.Cmp(decoded.CumulativeGas))
+	assert.Equal(t, 0, report.GasCost.Cmp(decoded.GasCost))
+	assert.Nil(t, decoded.Profit)
+	assert.Contains(t, jsonStr, `"gas_by_operation":{"Mint":"900"}`)
+	if assert.Contains(t, decoded.GasByOperation, "Mint") {
+		assert.Equal(t, "900", decoded.GasByOperation["Mint"].String())
+	}
+	assert.Equal(t, report.EventType, decoded.EventType)
+	assert.Equal(t, report.Message, decoded.Message)
+	assert.True(t, report.Timestamp.Equal(decoded.Timestamp))
+	if assert.NotNil(t, decoded.Phase) {
+		assert.Equal(t, phase, *decoded.Phase)
+	}
+}
+
+func TestStrategyReportUnmarshalInvalidAmount(t *testing.T) {
+	var decoded StrategyReport
+	err := json.Unmarshal([]byte(`{"cumulative_gas":"12x"}`), &decoded)
+	assert.Error(t, err)
+}
+
+func TestStabilityWindowCumulativeDrift(t *testing.T) {
+	// Each step moves the price up 0.4%: within the 0.5% per-step threshold,
+	// but about 2% in total over five intervals
+	series := func() []*big.Int {
+		prices := []*big.Int{big.NewInt(1_000_000)}
+		for i := 0; i < 10; i++ {
+			last := prices[len(prices)-1]
+			prices = append(prices, new(big.Int).Div(new(big.Int).Mul(last, big.NewInt(1004)), big.NewInt(1000)))
+		}
+		return prices
+	}
+
+	// Without the cumulative bound the drift is declared stable
+	perStepOnly := &StabilityWindow{Threshold: 0.005, RequiredIntervals: 5}
+	stable := false
+	for _, price := range series() {
+		stable = stable || perStepOnly.CheckStability(price)
+	}
+	assert.True(t, stable)
+
+	// With a 1% cumulative bound it never is
+	window := &StabilityWindow{Threshold: 0.005, RequiredIntervals: 5, MaxCumulativeDrift: 0.01}
+	for i, price := range series() {
+		assert.False(t, window.CheckStability(price), "declared stable at interval %d", i)
+	}
+
+	// A flat series with small chop is still stable
+	window.Reset()
+	stable = false
+	for _, price := range []int64{1_000_000, 1_003_000, 999_000, 1_002_000, 1_000_000, 1_001_000} {
+		stable = window.CheckStability(big.NewInt(price))
+	}
+	assert.True(t, stable)
+}
+
+func TestStrategyConfigValidateCumulativeDrift(t *testing.T) {
+	config := DefaultStrategyConfig()
+	assert.NoError(t, config.Validate())
+
+	config.MaxCumulativeDrift = 0
+	assert.NoError(t, config.Validate())
+
+	config.MaxCumulativeDrift = config.StabilityThreshold / 2
+	assert.Error(t, config.Validate())
+
+	config.MaxCumulativeDrift = -0.01
+	assert.Error(t, config.Validate())
+}
+
+func TestPositionRangeDistanceToEdge(t *testing.T) {
+	pr := &PositionRange{TickLower: -250800, TickUpper: -249600}
+
+	tests := []struct {
+		tick       int32
+		toLower    int32
+		toUpper    int32
+		nearestPct float64
+	}{
+		{-250200, 600, 600, 0.5},
+		{-250680, 120, 1080, 0.1},
+		{-249660, 1140, 60, 0.05},
+		{-250800, 0, 1200, 0},
+		{-249400, 1400, -200, -200.0 / 1200},
+	}
+	for _, tt := range tests {
+		toLower, toUpper, nearestPct := pr.DistanceToEdge(tt.tick)
+		assert.Equal(t, tt.toLower, toLower, "tick %d", tt.tick)
+		assert.Equal(t, tt.toUpper, toUpper, "tick %d", tt.tick)
+		assert.InDelta(t, tt.nearestPct, nearestPct, 1e-9, "tick %d", tt.tick)
+	}
+}
@@ -1,6 +1,9 @@
 package types
 
 import (
+	"fmt"
+	"math/big"
+
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 )
@@ -13,12 +16,41 @@ type DecodedParam struct {
 }
 
 // DecodedTransaction represents a fully decoded transaction
+// To, From and Value are set only when decoding a transaction fetched by hash; they stay zero for raw calldata
 type DecodedTransaction struct {
 	ContractAddress common.Address `json:"contract"`
 	MethodName      string         `json:"method"`
 	MethodSignature string         `json:"signature"`
 	Parameters      []DecodedParam `json:"parameters"`
 	RawData         []byte         `json:"rawData,omitempty"`
+	To              common.Address `json:"to,omitempty"`
+	From            common.Address `json:"from,omitempty"`
+	Value           *big.Int       `json:"value,omitempty"` // Native AVAX sent, in wei
+}
+
+// SetTransaction fills To, From and Value from the transaction the calldata was taken from
+// From is left zero if the sender cannot be recovered from the signature
+func (d *DecodedTransaction) SetTransaction(tx *types.Transaction) {
+	if tx.To() != nil {
+		d.To = *tx.To()
+	}
+	d.Value = new(big.Int).Set(tx.Value())
+	if from, err := types.Sender(types.LatestSignerForChainID(tx.ChainId()), tx); err == nil {
+		d.From = from
+	}
+}
+
+// Params returns the decoded parameters keyed by name; unnamed parameters are keyed arg0, arg1, ...
+func (d *DecodedTransaction) Params() map[string]interface{} {
+	params := make(map[string]interface{}, len(d.Parameters))
+	for i, p := range d.Parameters {
+		name := p.Name
+		if name == "" {
+			name = fmt.Sprintf("arg%d", i)
+		}
+		params[name] = p.Value
+	}
+	return params
 }
 
 // TxReceipt represents a transaction receipt with additional fields
@@ -30,7 +62,7 @@ type TxReceipt struct {
 	EffectiveGasPrice string       `json:"effectiveGasPrice"`
 	From              string       `json:"from"`
 	GasUsed           string       `json:"gasUsed"`
-	Logs              []*types.Log `json:"logs"`
+	Logs              []*types.Log `json:"logs"` // Emitted logs; ParseReceipt and DecodeLogs decode these without refetching
 	Bloom             types.Bloom  `json:"logsBloom"`
 	RevertReason      string       `json:"revertReason"`
 	Status            string       `json:"status"`
@@ -47,3 +79,21 @@ type EventInfo struct {
 	Index     uint                   `json:"index"`
 	Parameter map[string]interface{} `json:"parameter"`
 }
+
+// DecodedEvent is an event log decoded with the contract's ABI
+// Params keep their ABI Go types: common.Address for addresses, *big.Int for integers, [32]byte for bytes32
+type DecodedEvent struct {
+	Name    string
+	Address common.Address
+	Params  map[string]interface{}
+}
+
+// FindEvent returns the first event in events named eventName
+func FindEvent(events []DecodedEvent, eventName string) (*DecodedEvent, bool) {
+	for i := range events {
+		if events[i].Name == eventName {
+			return &events[i], true
+		}
+	}
+	return nil, false
+}
@@ -1,6 +1,8 @@
 package util
 
 import (
+	"fmt"
+	"math"
 	"math/big"
 )
 
@@ -26,18 +28,41 @@ var Q96 = new(big.Int).Lsh(big.NewInt(1), 96)
 // 	return out
 // }
 
+// MinTick and MaxTick bound the ticks an Algebra pool accepts
+const (
+	MinTick = -887272
+	MaxTick = 887272
+)
+
+// CheckTick returns an error if tick is outside [MinTick, MaxTick]
+func CheckTick(tick int) error {
+	if tick < MinTick || tick > MaxTick {
+		return fmt.Errorf("tick %d out of range [%d, %d]", tick, MinTick, MaxTick)
+	}
+	return nil
+}
+
+// TickToSqrtPriceX96 converts a tick to sqrt(price) in Q96 format
+// Panics if tick is outside [MinTick, MaxTick]; use TickToSqrtPriceX96Checked for untrusted ticks
 func TickToSqrtPriceX96(tick int) *big.Int {
+	sqrtPrice, err := TickToSqrtPriceX96Checked(tick)
+	if err != nil {
+		panic(err)
+	}
+	return sqrtPrice
+}
+
+// TickToSqrtPriceX96Checked converts a tick to sqrt(price) in Q96 format
+// Returns an error if tick is outside [MinTick, MaxTick]
+func TickToSqrtPriceX96Checked(tick int) (*big.Int, error) {
+	if err := CheckTick(tick); err != nil {
+		return nil, err
+	}
 	absTick := tick
 	if tick < 0 {
 		absTick = -tick
 	}
 
-	// Clamp to allowed tick range
-	maxTick := 887272
-	if absTick > maxTick {
-		panic("Tick out of range")
-	}
-
 	ratio := new(big.Int)
 	ratio.SetString("340282366920938463463374607431768211456", 10) // 1 << 128
 
@@ -86,7 +111,7 @@ func TickToSqrtPriceX96(tick int) *big.Int {
 	ratio.Add(ratio, new(big.Int).SetUint64(0xFFFFFFFF))
 	ratio.Rsh(ratio, 32)
 
-	return ratio
+	return ratio, nil
 }
 
 // big division: (a * b) / c  with rounding down
@@ -106,6 +131,7 @@ func mulDiv(a, b, c *big.Int) *big.Int {
 //   - amount0Max, amount1Max: how much you WANT to supply (your budgets)
 //
 // Returns: amount0Required, amount1Required, liquidity L
+// All three are zero if tickLower or tickUpper is outside [MinTick, MaxTick]; use ComputeAmountsChecked to get the error
 // -----------------------------------------------------------------------------
 func ComputeAmounts(
 	sqrtPriceX96 *big.Int,
@@ -115,10 +141,32 @@ func ComputeAmounts(
 	amount0Max *big.Int,
 	amount1Max *big.Int,
 ) (amount0 *big.Int, amount1 *big.Int, L *big.Int) {
+	amount0, amount1, L, err := ComputeAmountsChecked(sqrtPriceX96, tick, tickLower, tickUpper, amount0Max, amount1Max)
+	if err != nil {
+		return big.NewInt(0), big.NewInt(0), big.NewInt(0)
+	}
+	return amount0, amount1, L
+}
+
+// ComputeAmountsChecked is ComputeAmounts returning an error for tick bounds outside [MinTick, MaxTick]
+func ComputeAmountsChecked(
+	sqrtPriceX96 *big.Int,
+	tick int,
+	tickLower int,
+	tickUpper int,
+	amount0Max *big.Int,
+	amount1Max *big.Int,
+) (amount0 *big.Int, amount1 *big.Int, L *big.Int, err error) {
 
 	// compute sqrtPriceLower / sqrtPriceUpper
-	sqrtLower := TickToSqrtPriceX96(tickLower)
-	sqrtUpper := TickToSqrtPriceX96(tickUpper)
+	sqrtLower, err := TickToSqrtPriceX96Checked(tickLower)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid tickLower: %w", err)
+	}
+	sqrtUpper, err := TickToSqrtPriceX96Checked(tickUpper)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid tickUpper: %w", err)
+	}
 
 	// convert to big.Float for intermediate calcs
 	sP := new(big.Float).SetInt(sqrtPriceX96)
@@ -143,7 +191,7 @@ func ComputeAmounts(
 		L = new(big.Int)
 		Lf.Int(L)
 
-		amount0 = amount0Max
+		amount0 = new(big.Int).Set(amount0Max) // copy so callers never alias the budget
 		amount1 = big.NewInt(0)
 		return
 	}
@@ -162,7 +210,7 @@ func ComputeAmounts(
 		L = new(big.Int)
 		Lf.Int(L)
 
-		amount1 = amount1Max
+		amount1 = new(big.Int).Set(amount1Max) // copy so callers never alias the budget
 		amount0 = big.NewInt(0)
 		return
 	}
@@ -225,6 +273,44 @@ func ComputeAmounts(
 	return
 }
 
+// ComputeAmountsForValue is ComputeAmounts with the budgets given as a total USD value and a split
+// Inputs:
+//   - totalValueUSD: total value to supply, in USD
+//   - token0Price, token1Price: USD price of one smallest unit of each token (e.g. AVAX price / 1e18)
+//   - splitRatio: share of totalValueUSD allocated to token0 (0 to 1); the rest goes to token1
+//
+// The allocated values are converted to token units and passed to ComputeAmounts as the budgets
+// Returns: amount0Required, amount1Required, liquidity L, or an error for tick bounds outside [MinTick, MaxTick],
+// a missing or negative value, a price that is missing or not positive, or a split outside [0, 1]
+func ComputeAmountsForValue(
+	sqrtPriceX96 *big.Int,
+	tick int,
+	tickLower int,
+	tickUpper int,
+	totalValueUSD *big.Float,
+	token0Price *big.Float,
+	token1Price *big.Float,
+	splitRatio float64,
+) (amount0 *big.Int, amount1 *big.Int, L *big.Int, err error) {
+	if totalValueUSD == nil || totalValueUSD.Sign() < 0 {
+		return nil, nil, nil, fmt.Errorf("total value must be >= 0")
+	}
+	if token0Price == nil || token0Price.Sign() <= 0 || token1Price == nil || token1Price.Sign() <= 0 {
+		return nil, nil, nil, fmt.Errorf("token prices must be > 0")
+	}
+	if math.IsNaN(splitRatio) || splitRatio < 0 || splitRatio > 1 {
+		return nil, nil, nil, fmt.Errorf("split ratio must be between 0 and 1, got %v", splitRatio)
+	}
+
+	value0 := new(big.Float).Mul(totalValueUSD, big.NewFloat(splitRatio))
+	value1 := new(big.Float).Sub(totalValueUSD, value0)
+
+	amount0Max, _ := new(big.Float).Quo(value0, token0Price).Int(nil)
+	amount1Max, _ := new(big.Float).Quo(value1, token1Price).Int(nil)
+
+	return ComputeAmountsChecked(sqrtPriceX96, tick, tickLower, tickUpper, amount0Max, amount1Max)
+}
+
 /*
 Liquidity is an abstract numeric value used inside the AMM math to relate prices to amounts.
 It is not token0 or token1 amounts — it is the scaling constant of the curve.
@@ -254,8 +340,14 @@ func CalculateTokenAmountsFromLiquidity(
 	}
 
 	// Convert tick bounds to sqrt prices
-	sqrtLower := TickToSqrtPriceX96(int(tickLower))
-	sqrtUpper := TickToSqrtPriceX96(int(tickUpper))
+	sqrtLower, err := TickToSqrtPriceX96Checked(int(tickLower))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid tickLower: %w", err)
+	}
+	sqrtUpper, err := TickToSqrtPriceX96Checked(int(tickUpper))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid tickUpper: %w", err)
+	}
 
 	// Convert to big.Float for calculations
 	L := new(big.Float).SetInt(liquidity)
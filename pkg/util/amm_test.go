@@ -1,6 +1,7 @@
 package util
 
 import (
+	"math"
 	"math/big"
 	"testing"
 
@@ -16,6 +17,45 @@ func TestTickToSqrtPriceX96(t *testing.T) {
 	assert.Equal(t, expected, sqrtPrice)
 }
 
+func TestTickToSqrtPriceX96Checked(t *testing.T) {
+	// Algebra's MIN_SQRT_RATIO and MAX_SQRT_RATIO
+	minSqrt, _ := new(big.Int).SetString("4295128739", 10)
+	maxSqrt, _ := new(big.Int).SetString("1461446703485210103287273052203988822378723970342", 10)
+
+	sqrtPrice, err := TickToSqrtPriceX96Checked(MinTick)
+	assert.NoError(t, err)
+	assert.Equal(t, minSqrt, sqrtPrice)
+
+	sqrtPrice, err = TickToSqrtPriceX96Checked(MaxTick)
+	assert.NoError(t, err)
+	assert.Equal(t, maxSqrt, sqrtPrice)
+
+	_, err = TickToSqrtPriceX96Checked(MinTick - 1)
+	assert.ErrorContains(t, err, "out of range")
+	_, err = TickToSqrtPriceX96Checked(MaxTick + 1)
+	assert.ErrorContains(t, err, "out of range")
+
+	assert.Panics(t, func() { TickToSqrtPriceX96(MaxTick + 1) })
+}
+
+func TestCalculateTickBoundsLimits(t *testing.T) {
+	// Near MaxTick the upper bound is clamped to the last aligned tick in range
+	lower, upper, err := CalculateTickBounds(887000, 6, 200)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(886400), lower)
+	assert.Equal(t, int32(887200), upper)
+
+	lower, upper, err = CalculateTickBounds(-887000, 6, 200)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(-887200), lower)
+	assert.Equal(t, int32(-886400), upper)
+
+	_, _, err = CalculateTickBounds(MaxTick+1, 6, 200)
+	assert.ErrorContains(t, err, "out of range")
+	_, _, err = CalculateTickBounds(0, 6, 0)
+	assert.Error(t, err)
+}
+
 func TestComputeAmounts(t *testing.T) {
 
 	sqrtPriceX96, _ := big.NewInt(0).SetString("275467826341246019486853", 10)
@@ -40,6 +80,76 @@ func TestComputeAmounts(t *testing.T) {
 	// assert.LessOrEqual(t, amount1.Cmp(amount1Max), 0, "amount1 should not exceed amount1Max")
 }
 
+func TestComputeAmountsInvalidTicks(t *testing.T) {
+	sqrtPriceX96 := TickToSqrtPriceX96(0)
+	budget := big.NewInt(1_000_000)
+
+	_, _, _, err := ComputeAmountsChecked(sqrtPriceX96, 0, MinTick-200, 200, budget, budget)
+	assert.ErrorContains(t, err, "invalid tickLower")
+	_, _, _, err = ComputeAmountsChecked(sqrtPriceX96, 0, -200, MaxTick+200, budget, budget)
+	assert.ErrorContains(t, err, "invalid tickUpper")
+
+	amount0, amount1, liquidity := ComputeAmounts(sqrtPriceX96, 0, -200, MaxTick+200, budget, budget)
+	assert.Zero(t, amount0.Sign())
+	assert.Zero(t, amount1.Sign())
+	assert.Zero(t, liquidity.Sign())
+
+	_, _, err = CalculateTokenAmountsFromLiquidity(big.NewInt(1_000_000), sqrtPriceX96, -200, MaxTick+200)
+	assert.ErrorContains(t, err, "invalid tickUpper")
+}
+
+func TestComputeAmountsForValue(t *testing.T) {
+	// 1000 USD split 50/50 around tick -251400, as in TestPriceMovementSimulation
+	tick := -251400
+	tickLower, tickUpper, err := CalculateTickBounds(int32(tick), 6, 200)
+	assert.NoError(t, err)
+	sqrtPrice := TickToSqrtPriceX96(tick)
+
+	avaxPrice := SqrtPriceToHumanPrice(sqrtPrice, 18, 6)
+	token0Price := new(big.Float).Quo(avaxPrice, big.NewFloat(1e18))
+	token1Price := big.NewFloat(1e-6)
+
+	amount0, amount1, liquidity, err := ComputeAmountsForValue(sqrtPrice, tick, int(tickLower), int(tickUpper),
+		big.NewFloat(1000), token0Price, token1Price, 0.5)
+	assert.NoError(t, err)
+	assert.Positive(t, liquidity.Sign())
+
+	value0, _ := new(big.Float).Mul(new(big.Float).SetInt(amount0), token0Price).Float64()
+	value1, _ := new(big.Float).Mul(new(big.Float).SetInt(amount1), token1Price).Float64()
+	// The range is centered on the price, so a 50/50 split is deposited almost entirely
+	assert.InDelta(t, 1000, value0+value1, 0.01)
+	assert.InDelta(t, value0, value1, 0.01)
+}
+
+func TestComputeAmountsForValueInvalidInput(t *testing.T) {
+	tick := -251400
+	sqrtPrice := TickToSqrtPriceX96(tick)
+	price := big.NewFloat(1e-6)
+
+	tests := []struct {
+		name        string
+		total       *big.Float
+		price0      *big.Float
+		price1      *big.Float
+		splitRatio  float64
+		errContains string
+	}{
+		{"NilTotal", nil, price, price, 0.5, "total value"},
+		{"NegativeTotal", big.NewFloat(-1), price, price, 0.5, "total value"},
+		{"ZeroToken0Price", big.NewFloat(1000), big.NewFloat(0), price, 0.5, "prices"},
+		{"NilToken1Price", big.NewFloat(1000), price, nil, 0.5, "prices"},
+		{"SplitBelowZero", big.NewFloat(1000), price, price, -0.1, "split ratio"},
+		{"SplitAboveOne", big.NewFloat(1000), price, price, 1.5, "split ratio"},
+		{"SplitNaN", big.NewFloat(1000), price, price, math.NaN(), "split ratio"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, _, err := ComputeAmountsForValue(sqrtPrice, tick, -252000, -250800, tt.total, tt.price0, tt.price1, tt.splitRatio)
+			assert.ErrorContains(t, err, tt.errContains)
+		})
+	}
+}
+
 func TestCalculateTokenAmountsFromLiquidity(t *testing.T) {
 
 	liquidity := big.NewInt(845179049218237)
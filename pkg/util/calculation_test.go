@@ -6,6 +6,8 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+
 	"github.com/stretchr/testify/assert"
 )
 
@@ -64,6 +66,110 @@ func TestCalculateRebalanceAmounts(t *testing.T) {
 	})
 }
 
+func TestCalculateRebalanceAmountsWithFee(t *testing.T) {
+	// Price of 1 USDC unit per wei keeps the arithmetic readable
+	sqrtPrice := new(big.Int).Set(Q96)
+	fee := (&types.AMMState{LastFee: 500}).FeeFraction() // 0.05%
+
+	t.Run("USDC_TO_WAVAX", func(t *testing.T) {
+		tokenToSwap, swapAmount, err := CalculateRebalanceAmountsWithFee(big.NewInt(0), big.NewInt(1_000_000_000), sqrtPrice, fee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, tokenToSwap)
+		// 1e9 / (2 - 0.0005), slightly more than half to cover the fee
+		assert.Equal(t, "500125031", swapAmount.String())
+
+		// Both sides hold the same value after the fee is taken
+		remaining := new(big.Int).Sub(big.NewInt(1_000_000_000), swapAmount)
+		received := ApplyFee(swapAmount, fee)
+		assert.InDelta(t, remaining.Int64(), received.Int64(), 1)
+	})
+
+	t.Run("WAVAX_TO_USDC", func(t *testing.T) {
+		tokenToSwap, swapAmount, err := CalculateRebalanceAmountsWithFee(big.NewInt(1_000_000_000), big.NewInt(0), sqrtPrice, fee)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 0, tokenToSwap)
+		assert.Equal(t, "500125031", swapAmount.String())
+	})
+
+	t.Run("ZERO_FEE_MATCHES_LEGACY", func(t *testing.T) {
+		_, withFee, err := CalculateRebalanceAmountsWithFee(big.NewInt(0), big.NewInt(1_000_000_000), sqrtPrice, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, legacy, err := CalculateRebalanceAmounts(big.NewInt(0), big.NewInt(1_000_000_000), sqrtPrice)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, "500000000", legacy.String())
+		assert.Equal(t, legacy.String(), withFee.String())
+	})
+
+	t.Run("INVALID_FEE", func(t *testing.T) {
+		_, _, err := CalculateRebalanceAmountsWithFee(big.NewInt(0), big.NewInt(1), sqrtPrice, 1)
+		assert.Error(t, err)
+	})
+}
+
+func TestCalculateRebalanceAmountsWithTolerance(t *testing.T) {
+	// Price of 1 USDC unit per wei keeps the arithmetic readable
+	sqrtPrice := new(big.Int).Set(Q96)
+	tolerance := 0.01
+
+	t.Run("BALANCED", func(t *testing.T) {
+		// 0.5% off 50:50 is within the 1% tolerance
+		tokenToSwap, swapAmount, err := CalculateRebalanceAmountsWithTolerance(big.NewInt(1_002_500), big.NewInt(997_500), sqrtPrice, 0, tolerance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, NoSwap, tokenToSwap)
+		assert.Equal(t, "0", swapAmount.String())
+
+		// The same split is swapped with a tighter tolerance
+		tokenToSwap, swapAmount, err = CalculateRebalanceAmountsWithTolerance(big.NewInt(1_002_500), big.NewInt(997_500), sqrtPrice, 0, 0.001)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 0, tokenToSwap)
+		assert.Equal(t, "2500", swapAmount.String())
+	})
+
+	t.Run("ALL_WAVAX", func(t *testing.T) {
+		tokenToSwap, swapAmount, err := CalculateRebalanceAmountsWithTolerance(big.NewInt(1_000_000), big.NewInt(0), sqrtPrice, 0, tolerance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 0, tokenToSwap)
+		assert.Equal(t, "500000", swapAmount.String())
+	})
+
+	t.Run("ALL_USDC", func(t *testing.T) {
+		tokenToSwap, swapAmount, err := CalculateRebalanceAmountsWithTolerance(big.NewInt(0), big.NewInt(1_000_000), sqrtPrice, 0, tolerance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, 1, tokenToSwap)
+		assert.Equal(t, "500000", swapAmount.String())
+	})
+
+	t.Run("EMPTY", func(t *testing.T) {
+		tokenToSwap, swapAmount, err := CalculateRebalanceAmountsWithTolerance(big.NewInt(0), big.NewInt(0), sqrtPrice, 0, tolerance)
+		if err != nil {
+			t.Fatal(err)
+		}
+		assert.Equal(t, NoSwap, tokenToSwap)
+		assert.Equal(t, "0", swapAmount.String())
+	})
+
+	t.Run("ZERO_PRICE", func(t *testing.T) {
+		_, _, err := CalculateRebalanceAmountsWithTolerance(big.NewInt(1), big.NewInt(1), big.NewInt(0), 0, tolerance)
+		assert.Error(t, err)
+	})
+}
+
 // CalculateTickBounds + TickToSqrtPriceX96 + SqrtPriceToPrice
 func TestCalculatePriceBounds(t *testing.T) {
 
@@ -81,14 +187,37 @@ func TestCalculatePriceBounds(t *testing.T) {
 	lowerSqrtPrice := TickToSqrtPriceX96(int(tickLower))
 	upperSqrtPrice := TickToSqrtPriceX96(int(tickUpper))
 
-	decimalAdjustment := new(big.Float).SetInt64(1_000_000_000_000) // 10^12
-	currentPrice := new(big.Float).Mul(decimalAdjustment, SqrtPriceToPrice(currentSqrtPrice))
-	lowerPrice := new(big.Float).Mul(decimalAdjustment, SqrtPriceToPrice(lowerSqrtPrice))
-	upperPrice := new(big.Float).Mul(decimalAdjustment, SqrtPriceToPrice(upperSqrtPrice))
+	currentPrice := SqrtPriceToHumanPrice(currentSqrtPrice, 18, 6)
+	lowerPrice := SqrtPriceToHumanPrice(lowerSqrtPrice, 18, 6)
+	upperPrice := SqrtPriceToHumanPrice(upperSqrtPrice, 18, 6)
 
 	log.Printf("PriceCurrent: %.02f, PriceLower: %.02f, PriceUpper: %.02f", currentPrice, lowerPrice, upperPrice)
 }
 
+func TestHumanPrice(t *testing.T) {
+	// Prices recorded below for the WAVAX(18)/USDC(6) pool
+	cases := []struct {
+		tick  int
+		price float64
+	}{
+		{-249587, 14.49},
+		{-249800, 14.19},
+		{-249600, 14.47},
+		{-249400, 14.77},
+	}
+	for _, c := range cases {
+		price, _ := SqrtPriceToHumanPrice(TickToSqrtPriceX96(c.tick), 18, 6).Float64()
+		assert.InDelta(t, c.price, price, 0.005, "tick %d", c.tick)
+
+		// Round trip through the exact price lands on the same tick
+		assert.InDelta(t, c.tick, HumanPriceToTick(price, 18, 6), 1, "price %f", price)
+	}
+
+	// Swapping the token order inverts the decimal adjustment
+	inverse, _ := SqrtPriceToHumanPrice(TickToSqrtPriceX96(249587), 6, 18).Float64()
+	assert.InDelta(t, 1/14.49, inverse, 0.0001)
+}
+
 /* -1247 -289400
 2026/01/07 12:51:51 CurrentTick: -249587,TickLower: -249600, TickUpper: -249200
 2026/01/07 12:51:51 PriceCurrent: 14.49, PriceLower: 14.47, PriceUpper: 15.06
@@ -100,3 +229,31 @@ func TestCalculatePriceBounds(t *testing.T) {
 2026/01/07 12:53:26 CurrentTick: -249587,TickLower: -249800, TickUpper: -249000
 2026/01/07 12:53:26 PriceCurrent: 14.49, PriceLower: 14.19, PriceUpper: 15.37
 */
+
+func TestFormatTokenAmount(t *testing.T) {
+	wavax, _ := new(big.Int).SetString("3750793819555087051", 10)
+	tests := []struct {
+		amount   *big.Int
+		decimals int
+		want     string
+	}{
+		// 18 decimals (WAVAX)
+		{wavax, 18, "3.7508"},
+		{new(big.Int).Neg(wavax), 18, "-3.7508"},
+		{big.NewInt(1_000_000_000_000_000_000), 18, "1"},
+		{big.NewInt(99_990_000_000_000), 18, "0.0001"},
+		{big.NewInt(49_999_999_999_999), 18, "0"},
+		{big.NewInt(0), 18, "0"},
+		// 6 decimals (USDC)
+		{big.NewInt(1_500_000), 6, "1.5"},
+		{big.NewInt(1_234_567_890), 6, "1234.5679"},
+		{big.NewInt(999_999_990), 6, "1000"},
+		{big.NewInt(50), 6, "0.0001"},
+		// Fewer decimals than kept places
+		{big.NewInt(1234), 2, "12.34"},
+		{big.NewInt(7), 0, "7"},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, FormatTokenAmount(tt.amount, tt.decimals), "%s with %d decimals", tt.amount, tt.decimals)
+	}
+}
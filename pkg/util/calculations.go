@@ -2,17 +2,16 @@ package util
 
 import (
 	"fmt"
+	"math"
 	"math/big"
+	"strings"
 )
 
 // Strategy calculation functions
 
 // SqrtPriceToPrice converts sqrtPriceX96 to human-readable price (T014)
 // Formula: price = (sqrtPrice / 2^96)^2
-// For WAVAX/USDC pool (assuming token0=WAVAX, token1=USDC):
-//
-//	priceUSDCperWAVAX = price * (10^(18-6)) // Adjust for decimals
-//
+// The result is in smallest units; see SqrtPriceToHumanPrice for a decimals-adjusted price
 // Used for stability detection and ratio calculations from research.md R1
 func SqrtPriceToPrice(sqrtPriceX96 *big.Int) *big.Float {
 	if sqrtPriceX96 == nil || sqrtPriceX96.Sign() == 0 {
@@ -28,32 +27,90 @@ func SqrtPriceToPrice(sqrtPriceX96 *big.Int) *big.Float {
 
 	// Square to get price
 	price := new(big.Float).Mul(sqrtPriceNormalized, sqrtPriceNormalized)
-	// decimalAdjustment := new(big.Float).SetInt64(1_000_000_000_000) // 10^12
 
 	return price
 }
 
+// SqrtPriceToHumanPrice converts sqrtPriceX96 to whole token1 per whole token0
+// e.g. USDC per WAVAX for the WAVAX(18)/USDC(6) pool: price * 10^(18-6)
+func SqrtPriceToHumanPrice(sqrtPrice *big.Int, token0Decimals, token1Decimals int) *big.Float {
+	return new(big.Float).Mul(SqrtPriceToPrice(sqrtPrice), decimalScale(token0Decimals-token1Decimals))
+}
+
+// HumanPriceToTick returns the tick whose price is closest below a price in whole token1 per whole token0
+// It is the inverse of SqrtPriceToHumanPrice(TickToSqrtPriceX96(tick), ...), up to tick rounding
+func HumanPriceToTick(price float64, token0Decimals, token1Decimals int) int {
+	rawPrice := price / math.Pow10(token0Decimals-token1Decimals)
+	return int(math.Floor(math.Log(rawPrice) / math.Log(1.0001)))
+}
+
+// decimalScale returns 10^exp, which may be fractional for negative exp
+func decimalScale(exp int) *big.Float {
+	if exp < 0 {
+		return new(big.Float).Quo(big.NewFloat(1), decimalScale(-exp))
+	}
+	return new(big.Float).SetInt(new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(exp)), nil))
+}
+
+// NoSwap is the tokenToSwap returned when the portfolio is already balanced within tolerance
+const NoSwap = -1
+
+// DefaultRebalanceTolerance is the value imbalance, as a fraction of the portfolio, below which no swap is suggested
+const DefaultRebalanceTolerance = 0.001
+
 // CalculateRebalanceAmounts calculates swap amounts needed to achieve 50:50 value ratio (T017)
 // Uses value-based proportional rebalancing with current pool price from research.md R3
-// Returns: tokenToSwap (0=WAVAX, 1=USDC), swapAmount, error
+// Ignores the swap fee; see CalculateRebalanceAmountsWithFee
+// Returns: tokenToSwap (0=WAVAX, 1=USDC, NoSwap), swapAmount, error
 func CalculateRebalanceAmounts(
 	wavaxBalance *big.Int,
 	usdcBalance *big.Int,
 	sqrtPriceX96 *big.Int,
+) (tokenToSwap int, swapAmount *big.Int, err error) {
+	return CalculateRebalanceAmountsWithFee(wavaxBalance, usdcBalance, sqrtPriceX96, 0)
+}
+
+// CalculateRebalanceAmountsWithFee calculates swap amounts needed to achieve 50:50 value ratio
+// after the pool takes feeFraction of the swapped amount (e.g. AMMState.FeeFraction())
+// Imbalances below DefaultRebalanceTolerance return NoSwap; see CalculateRebalanceAmountsWithTolerance
+// Returns: tokenToSwap (0=WAVAX, 1=USDC, NoSwap), swapAmount, error
+func CalculateRebalanceAmountsWithFee(
+	wavaxBalance *big.Int,
+	usdcBalance *big.Int,
+	sqrtPriceX96 *big.Int,
+	feeFraction float64,
+) (tokenToSwap int, swapAmount *big.Int, err error) {
+	return CalculateRebalanceAmountsWithTolerance(wavaxBalance, usdcBalance, sqrtPriceX96, feeFraction, DefaultRebalanceTolerance)
+}
+
+// CalculateRebalanceAmountsWithTolerance calculates swap amounts needed to achieve 50:50 value ratio net of feeFraction
+// Swapping x of the larger side leaves (larger - x) vs (smaller + x*(1-fee)), so x = diff / (2 - fee)
+// tolerance is the value imbalance, as a fraction of the total value, below which NoSwap is returned
+// An empty portfolio is reported as balanced
+// Returns: tokenToSwap (0=WAVAX, 1=USDC, NoSwap), swapAmount (0 with NoSwap), error
+func CalculateRebalanceAmountsWithTolerance(
+	wavaxBalance *big.Int,
+	usdcBalance *big.Int,
+	sqrtPriceX96 *big.Int,
+	feeFraction float64,
+	tolerance float64,
 ) (tokenToSwap int, swapAmount *big.Int, err error) {
 	if wavaxBalance == nil || usdcBalance == nil || sqrtPriceX96 == nil {
 		return 0, nil, fmt.Errorf("nil input parameters")
 	}
+	if feeFraction < 0 || feeFraction >= 1 {
+		return 0, nil, fmt.Errorf("fee fraction must be in [0, 1), got %f", feeFraction)
+	}
+	if tolerance < 0 || tolerance >= 1 {
+		return 0, nil, fmt.Errorf("tolerance must be in [0, 1), got %f", tolerance)
+	}
+	if sqrtPriceX96.Sign() <= 0 {
+		return 0, nil, fmt.Errorf("invalid pool price %s", sqrtPriceX96)
+	}
 
 	// Get current pool price (USDC per WAVAX)
 	price := SqrtPriceToPrice(sqrtPriceX96)
 
-	// Adjust for decimals: WAVAX has 18 decimals, USDC has 6 decimals
-	// Price needs to be adjusted by 10^(18-6) = 10^12
-	// decimalAdjustment := new(big.Float).SetInt64(1_000_000_000_000) // 10^12 // !IMPORTANT. 필요없음. adjustment를 안 해야 정상 작동
-	// priceUSDCperWAVAX := new(big.Float).Mul(price, decimalAdjustment)
-	// fmt.Printf("priceUSDCperWAVAX: %v\n", priceUSDCperWAVAX)
-
 	// Calculate current values in USDC terms
 	wavaxBalanceFloat := new(big.Float).SetInt(wavaxBalance)
 	usdcBalanceFloat := new(big.Float).SetInt(usdcBalance)
@@ -63,46 +120,115 @@ func CalculateRebalanceAmounts(
 	fmt.Printf("wavaxValueInUSDC: %v\n", wavaxValueInUSDC)
 	fmt.Printf("totalValue: %v\n", totalValue)
 
-	// Target 50% of total value in each token
-	targetUSDC := new(big.Float).Quo(totalValue, big.NewFloat(2))
-	targetWAVAXValue := new(big.Float).Quo(totalValue, big.NewFloat(2))
-	fmt.Printf("targetUSDC: %v\n", targetUSDC)
-	fmt.Printf("targetWAVAXValue: %v\n", targetWAVAXValue)
+	// Nothing to balance, or already within tolerance of 50:50
+	if totalValue.Sign() == 0 {
+		return NoSwap, big.NewInt(0), nil
+	}
+	imbalance := new(big.Float).Sub(usdcBalanceFloat, wavaxValueInUSDC)
+	imbalance.Abs(imbalance).Quo(imbalance, totalValue)
+	if imbalance.Cmp(big.NewFloat(tolerance)) < 0 {
+		return NoSwap, big.NewInt(0), nil
+	}
 
-	// Determine which token to swap and how much
-	usdcDiff := new(big.Float).Sub(usdcBalanceFloat, targetUSDC)
+	// Each unit swapped leaves the larger side and arrives on the smaller side net of the fee
+	divisor := big.NewFloat(2 - feeFraction)
 
-	// If USDC > target, swap USDC to WAVAX
+	// If USDC > WAVAX value, swap USDC to WAVAX
+	usdcDiff := new(big.Float).Sub(usdcBalanceFloat, wavaxValueInUSDC)
 	if usdcDiff.Sign() > 0 {
 		// Swap excess USDC to WAVAX
-		swapAmountFloat := usdcDiff
+		swapAmountFloat := new(big.Float).Quo(usdcDiff, divisor)
 		swapAmount = new(big.Int)
 		swapAmountFloat.Int(swapAmount)
 
 		// Ensure positive and non-zero
 		if swapAmount.Sign() <= 0 {
-			return 0, big.NewInt(0), nil // No swap needed
+			return NoSwap, big.NewInt(0), nil // No swap needed
 		}
 
 		return 1, swapAmount, nil // tokenToSwap=1 (USDC)
 	}
 
-	// If WAVAX > target, swap WAVAX to USDC
-	wavaxDiff := new(big.Float).Sub(wavaxValueInUSDC, targetWAVAXValue)
-	if wavaxDiff.Sign() > 0 {
-		// Convert excess WAVAX value to WAVAX amount
-		excessWAVAXAmount := new(big.Float).Quo(wavaxDiff, price)
-		swapAmount = new(big.Int)
-		excessWAVAXAmount.Int(swapAmount)
+	// Otherwise WAVAX value > USDC, swap WAVAX to USDC
+	// Convert excess WAVAX value to WAVAX amount
+	wavaxDiff := new(big.Float).Neg(usdcDiff)
+	excessWAVAXAmount := new(big.Float).Quo(wavaxDiff, divisor)
+	excessWAVAXAmount.Quo(excessWAVAXAmount, price)
+	swapAmount = new(big.Int)
+	excessWAVAXAmount.Int(swapAmount)
+
+	// Ensure positive and non-zero
+	if swapAmount.Sign() <= 0 {
+		return NoSwap, big.NewInt(0), nil // No swap needed
+	}
 
-		// Ensure positive and non-zero
-		if swapAmount.Sign() <= 0 {
-			return 0, big.NewInt(0), nil // No swap needed
-		}
+	return 0, swapAmount, nil // tokenToSwap=0 (WAVAX)
+}
 
-		return 0, swapAmount, nil // tokenToSwap=0 (WAVAX)
+// ApplyFee returns amount net of a swap fee given as a fraction (e.g. AMMState.FeeFraction())
+func ApplyFee(amount *big.Int, feeFraction float64) *big.Int {
+	if amount == nil {
+		return big.NewInt(0)
 	}
+	net := new(big.Float).Mul(new(big.Float).SetInt(amount), big.NewFloat(1-feeFraction))
+	result, _ := net.Int(nil)
+	return result
+}
+
+// CalculateImpermanentLoss compares the value of a position against simply holding its entry amounts
+// Amounts are in smallest units; price is token1 units per token0 unit at exit (e.g. SqrtPriceToPrice)
+// Returns the loss (hold value - position value, positive when the position underperformed) and the hold value
+func CalculateImpermanentLoss(entry0, entry1, exit0, exit1 *big.Int, price *big.Float) (loss, holdValue *big.Float) {
+	holdValue = valueAt(entry0, entry1, price)
+	return new(big.Float).Sub(holdValue, valueAt(exit0, exit1, price)), holdValue
+}
 
-	// Already balanced
-	return 0, big.NewInt(0), nil
+// valueAt values token0/token1 amounts in token1 units at price
+func valueAt(amount0, amount1 *big.Int, price *big.Float) *big.Float {
+	value := new(big.Float)
+	if amount0 != nil {
+		value.Mul(new(big.Float).SetInt(amount0), price)
+	}
+	if amount1 != nil {
+		value.Add(value, new(big.Float).SetInt(amount1))
+	}
+	return value
+}
+
+// tokenAmountPlaces is the number of fractional digits FormatTokenAmount keeps
+const tokenAmountPlaces = 4
+
+// FormatTokenAmount renders an amount in smallest units as whole tokens, rounded to 4 decimal places
+// Trailing zeros are dropped, e.g. 3750793819555087051 with 18 decimals is "3.7508" and 1500000 with 6 is "1.5"
+func FormatTokenAmount(amount *big.Int, decimals int) string {
+	if amount == nil {
+		return "0"
+	}
+	places := tokenAmountPlaces
+	if decimals < places {
+		places = decimals
+	}
+
+	// Round half away from zero to the kept places
+	abs := new(big.Int).Abs(amount)
+	unit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(decimals-places)), nil)
+	scaled, rem := new(big.Int).QuoRem(abs, unit, new(big.Int))
+	if new(big.Int).Mul(rem, big.NewInt(2)).Cmp(unit) >= 0 {
+		scaled.Add(scaled, big.NewInt(1))
+	}
+
+	digits := scaled.String()
+	if len(digits) <= places {
+		digits = fmt.Sprintf("%0*s", places+1, digits)
+	}
+	whole, frac := digits[:len(digits)-places], strings.TrimRight(digits[len(digits)-places:], "0")
+
+	result := whole
+	if frac != "" {
+		result += "." + frac
+	}
+	if amount.Sign() < 0 && result != "0" {
+		result = "-" + result
+	}
+	return result
 }
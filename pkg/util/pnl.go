@@ -0,0 +1,85 @@
+package util
+
+import (
+	"math/big"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+// DecomposePnL splits the result of a closed position into price movement, impermanent loss and income
+// Snapshot Amount0 is WAVAX (wei) and Amount1 is USDC (smallest unit); collected fees are WAVAX/USDC amounts
+// All values in the breakdown are USDC smallest units
+func DecomposePnL(
+	entrySnapshot, exitSnapshot *types.PositionSnapshot,
+	feesWAVAX, feesUSDC *big.Int,
+	rewards *types.RewardAmounts,
+	prices types.PnLPrices,
+) *types.PnLBreakdown {
+	entryValue := valueAt(entrySnapshot.Amount0, entrySnapshot.Amount1, prices.EntryWAVAX)
+	exitValue := valueAt(exitSnapshot.Amount0, exitSnapshot.Amount1, prices.ExitWAVAX)
+	il, holdValue := CalculateImpermanentLoss(
+		entrySnapshot.Amount0, entrySnapshot.Amount1,
+		exitSnapshot.Amount0, exitSnapshot.Amount1,
+		prices.ExitWAVAX,
+	)
+
+	feeIncome := valueAt(feesWAVAX, feesUSDC, prices.ExitWAVAX)
+	rewardIncome := new(big.Float)
+	if rewards != nil {
+		rewardIncome.Add(tokenValue(rewards.Reward, prices.Reward), tokenValue(rewards.BonusReward, prices.BonusReward))
+	}
+
+	netPnL := new(big.Float).Sub(exitValue, entryValue)
+	netPnL.Add(netPnL, feeIncome)
+	netPnL.Add(netPnL, rewardIncome)
+
+	return &types.PnLBreakdown{
+		EntryValue:      entryValue,
+		ExitValue:       exitValue,
+		HoldValue:       holdValue,
+		PriceChange:     new(big.Float).Sub(holdValue, entryValue),
+		ImpermanentLoss: il,
+		FeeIncome:       feeIncome,
+		RewardIncome:    rewardIncome,
+		NetPnL:          netPnL,
+	}
+}
+
+// tokenValue values amount at price, treating a missing amount or price as zero
+func tokenValue(amount *big.Int, price *big.Float) *big.Float {
+	if amount == nil || price == nil {
+		return new(big.Float)
+	}
+	return new(big.Float).Mul(new(big.Float).SetInt(amount), price)
+}
+
+// ImpermanentLoss compares a position of liquidity in [tickLower, tickUpper] against holding the tokens it was opened with
+// The position is opened at sqrtPriceInitial and valued at sqrtPriceFinal; both sides are valued at price0 and price1,
+// the USD prices of one smallest unit of token0 and token1 at the final price (e.g. AVAX price / 1e18)
+// Returns the loss in USD (positive when the position is worth less than holding) and as a percentage of the hold value,
+// or an error for tick bounds outside [MinTick, MaxTick]
+func ImpermanentLoss(
+	liquidity *big.Int,
+	sqrtPriceInitial, sqrtPriceFinal *big.Int,
+	tickLower, tickUpper int32,
+	price0, price1 *big.Float,
+) (lossUSD *big.Float, lossPct *big.Float, err error) {
+	entry0, entry1, err := CalculateTokenAmountsFromLiquidity(liquidity, sqrtPriceInitial, tickLower, tickUpper)
+	if err != nil {
+		return nil, nil, err
+	}
+	exit0, exit1, err := CalculateTokenAmountsFromLiquidity(liquidity, sqrtPriceFinal, tickLower, tickUpper)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	holdValue := new(big.Float).Add(tokenValue(entry0, price0), tokenValue(entry1, price1))
+	positionValue := new(big.Float).Add(tokenValue(exit0, price0), tokenValue(exit1, price1))
+
+	lossUSD = new(big.Float).Sub(holdValue, positionValue)
+	lossPct = new(big.Float)
+	if holdValue.Sign() != 0 {
+		lossPct.Quo(new(big.Float).Mul(lossUSD, big.NewFloat(100)), holdValue)
+	}
+	return lossUSD, lossPct, nil
+}
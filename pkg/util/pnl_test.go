@@ -0,0 +1,107 @@
+package util
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecomposePnL(t *testing.T) {
+	wavax := func(n float64) *big.Int {
+		v, _ := new(big.Float).Mul(big.NewFloat(n), big.NewFloat(1e18)).Int(nil)
+		return v
+	}
+	usdc := func(n float64) *big.Int {
+		v, _ := new(big.Float).Mul(big.NewFloat(n), big.NewFloat(1e6)).Int(nil)
+		return v
+	}
+	// USDC units per WAVAX wei for a price in USDC per WAVAX
+	rawPrice := func(p float64) *big.Float {
+		return new(big.Float).Quo(big.NewFloat(p*1e6), big.NewFloat(1e18))
+	}
+	inUSDC := func(v *big.Float) float64 {
+		f, _ := new(big.Float).Quo(v, big.NewFloat(1e6)).Float64()
+		return f
+	}
+
+	// 10 WAVAX + 200 USDC opened at 20 USDC, closed at 25 USDC after the pool sold 2 WAVAX for 48 USDC
+	entry := &types.PositionSnapshot{Amount0: wavax(10), Amount1: usdc(200)}
+	exit := &types.PositionSnapshot{Amount0: wavax(8), Amount1: usdc(248)}
+	rewards := &types.RewardAmounts{Reward: wavax(1)} // 1 BLACK at 0.5 USDC
+
+	pnl := DecomposePnL(entry, exit, wavax(0.1), usdc(3), rewards, types.PnLPrices{
+		EntryWAVAX: rawPrice(20),
+		ExitWAVAX:  rawPrice(25),
+		Reward:     rawPrice(0.5),
+	})
+
+	assert.InDelta(t, 400, inUSDC(pnl.EntryValue), 1e-6)
+	assert.InDelta(t, 448, inUSDC(pnl.ExitValue), 1e-6)
+	assert.InDelta(t, 450, inUSDC(pnl.HoldValue), 1e-6)
+	assert.InDelta(t, 50, inUSDC(pnl.PriceChange), 1e-6)
+	assert.InDelta(t, 2, inUSDC(pnl.ImpermanentLoss), 1e-6)
+	assert.InDelta(t, 5.5, inUSDC(pnl.FeeIncome), 1e-6)
+	assert.InDelta(t, 0.5, inUSDC(pnl.RewardIncome), 1e-6)
+	assert.InDelta(t, 54, inUSDC(pnl.NetPnL), 1e-6)
+
+	// The position lost to IL, but fees and rewards more than made up for it
+	assert.Positive(t, pnl.ImpermanentLoss.Sign())
+	income := new(big.Float).Add(pnl.FeeIncome, pnl.RewardIncome)
+	assert.Positive(t, income.Cmp(pnl.ImpermanentLoss))
+	assert.Positive(t, pnl.NetPnL.Sign())
+
+	// Rewards without a price are valued at zero
+	unpriced := DecomposePnL(entry, exit, wavax(0.1), usdc(3), rewards, types.PnLPrices{
+		EntryWAVAX: rawPrice(20),
+		ExitWAVAX:  rawPrice(25),
+	})
+	assert.Zero(t, unpriced.RewardIncome.Sign())
+}
+
+func TestImpermanentLoss(t *testing.T) {
+	// CASE 1 of TestPriceMovementSimulation: 1000 USD split 50/50 at tick -251400, price falls to the lower bound
+	tick := -251400
+	tickLower, tickUpper, err := CalculateTickBounds(int32(tick), 6, 200)
+	assert.NoError(t, err)
+	sqrtPriceCurrent := TickToSqrtPriceX96(tick)
+	sqrtPriceLower := TickToSqrtPriceX96(int(tickLower))
+	priceCurrent := SqrtPriceToHumanPrice(sqrtPriceCurrent, 18, 6)
+	priceLower := SqrtPriceToHumanPrice(sqrtPriceLower, 18, 6)
+
+	avaxWei, _ := new(big.Float).Mul(new(big.Float).Quo(big.NewFloat(500), priceCurrent), big.NewFloat(1e18)).Int(nil)
+	deposited0, deposited1, liquidity := ComputeAmounts(sqrtPriceCurrent, tick, int(tickLower), int(tickUpper), avaxWei, big.NewInt(500_000_000))
+	atLower0, atLower1, _ := CalculateTokenAmountsFromLiquidity(liquidity, sqrtPriceLower, tickLower, tickUpper)
+
+	// The simulation's LP vs hold comparison, in USD
+	usd := func(amount0, amount1 *big.Int) float64 {
+		avax, _ := new(big.Float).Quo(new(big.Float).SetInt(amount0), big.NewFloat(1e18)).Float64()
+		usdc, _ := new(big.Float).Quo(new(big.Float).SetInt(amount1), big.NewFloat(1e6)).Float64()
+		p, _ := priceLower.Float64()
+		return avax*p + usdc
+	}
+	holdValue := usd(deposited0, deposited1)
+	expectedLoss := holdValue - usd(atLower0, atLower1)
+
+	price0 := new(big.Float).Quo(priceLower, big.NewFloat(1e18))
+	lossUSD, lossPct, err := ImpermanentLoss(liquidity, sqrtPriceCurrent, sqrtPriceLower, tickLower, tickUpper, price0, big.NewFloat(1e-6))
+	assert.NoError(t, err)
+
+	loss, _ := lossUSD.Float64()
+	pct, _ := lossPct.Float64()
+	assert.Positive(t, loss)
+	assert.InDelta(t, expectedLoss, loss, 0.01)
+	assert.InDelta(t, expectedLoss/holdValue*100, pct, 0.001)
+
+	// No price movement, no loss
+	lossUSD, _, err = ImpermanentLoss(liquidity, sqrtPriceCurrent, sqrtPriceCurrent, tickLower, tickUpper, price0, big.NewFloat(1e-6))
+	assert.NoError(t, err)
+	loss, _ = lossUSD.Float64()
+	assert.InDelta(t, 0, loss, 1e-9)
+
+	// Bounds outside [MinTick, MaxTick] are an error, not a panic
+	_, _, err = ImpermanentLoss(liquidity, sqrtPriceCurrent, sqrtPriceLower, tickLower, MaxTick+200, price0, big.NewFloat(1e-6))
+	assert.Error(t, err)
+}
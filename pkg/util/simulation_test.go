@@ -38,13 +38,10 @@ func TestPriceMovementSimulation(t *testing.T) {
 	sqrtPriceLower := TickToSqrtPriceX96(int(tickLower))
 	sqrtPriceUpper := TickToSqrtPriceX96(int(tickUpper))
 
-	// Convert to human-readable prices (USDC per AVAX)
-	// Adjustment factor for decimals: AVAX (18) / USDC (6) = 10^12
-	decimalAdjustment := new(big.Float).SetInt64(1_000_000_000_000)
-
-	priceCurrent := new(big.Float).Mul(SqrtPriceToPrice(sqrtPriceCurrent), decimalAdjustment)
-	priceLower := new(big.Float).Mul(SqrtPriceToPrice(sqrtPriceLower), decimalAdjustment)
-	priceUpper := new(big.Float).Mul(SqrtPriceToPrice(sqrtPriceUpper), decimalAdjustment)
+	// Convert to human-readable prices (USDC per AVAX): AVAX has 18 decimals, USDC 6
+	priceCurrent := SqrtPriceToHumanPrice(sqrtPriceCurrent, 18, 6)
+	priceLower := SqrtPriceToHumanPrice(sqrtPriceLower, 18, 6)
+	priceUpper := SqrtPriceToHumanPrice(sqrtPriceUpper, 18, 6)
 
 	t.Logf("\n")
 	t.Logf("PRICE BOUNDS")
@@ -4,19 +4,31 @@ import (
 	"fmt"
 	"math"
 	"math/big"
+	"strings"
+	"time"
 
 	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // Validation and helper functions for liquidity staking operations
 
 // ValidateStakingRequest validates input parameters for staking operation
+// rangeWidth is in units of tickSpacing, the spacing the position's ticks are aligned to
 // Returns error if validation fails, nil otherwise
-func ValidateStakingRequest(maxWAVAX, maxUSDC *big.Int, rangeWidth, slippagePct int) error {
-	// Range width validation (1-20 tick ranges)
-	// if rangeWidth <= 0 || rangeWidth > 20 {
-	// 	return fmt.Errorf("range width must be between 1 and 20, got %d (valid examples: 2, 6, 10)", rangeWidth)
-	// }
+func ValidateStakingRequest(maxWAVAX, maxUSDC *big.Int, rangeWidth, tickSpacing, slippagePct int) error {
+	// Range width validation: ±(rangeWidth/2) spacings around the current tick
+	if rangeWidth <= 0 || rangeWidth%2 != 0 {
+		return fmt.Errorf("range width must be even and > 0, got %d", rangeWidth)
+	}
+	if tickSpacing <= 0 {
+		return fmt.Errorf("invalid tick spacing %d", tickSpacing)
+	}
+	// Each half of the range must fit between tick 0 and MaxTick, or no position can have these bounds
+	if halfTicks := int64(rangeWidth/2) * int64(tickSpacing); halfTicks > MaxTick {
+		return fmt.Errorf("range width %d at tick spacing %d spans ±%d ticks, beyond the maximum tick %d",
+			rangeWidth, tickSpacing, halfTicks, MaxTick)
+	}
 
 	// Slippage validation (1-50 percent)
 	if slippagePct <= 0 || slippagePct > 50 {
@@ -34,12 +46,72 @@ func ValidateStakingRequest(maxWAVAX, maxUSDC *big.Int, rangeWidth, slippagePct
 	return nil
 }
 
+// ValidateRoutes checks a swap route chain before anything is sent
+// Every hop needs a non-zero pair, from and to, and each hop must start where the previous one ended
+// The input token (and the one approved for the router) is routes[0].From
+func ValidateRoutes(routes []types.Route) error {
+	if len(routes) == 0 {
+		return fmt.Errorf("no routes provided")
+	}
+	for i, route := range routes {
+		if route.Pair == (common.Address{}) || route.From == (common.Address{}) || route.To == (common.Address{}) {
+			return fmt.Errorf("route %d has a zero address (pair %s, from %s, to %s)",
+				i, route.Pair.Hex(), route.From.Hex(), route.To.Hex())
+		}
+		if route.From == route.To {
+			return fmt.Errorf("route %d swaps %s to itself", i, route.From.Hex())
+		}
+		if i > 0 && routes[i-1].To != route.From {
+			return fmt.Errorf("route %d starts at %s but route %d ends at %s",
+				i, route.From.Hex(), i-1, routes[i-1].To.Hex())
+		}
+	}
+	return nil
+}
+
+// AssertTickAligned returns an error if tick is not a multiple of the pool tick spacing
+// Misaligned bounds are rejected by the position manager, so check before sending
+func AssertTickAligned(tick int32, spacing int) error {
+	if spacing <= 0 {
+		return fmt.Errorf("invalid tick spacing %d", spacing)
+	}
+	if remainder := ((int(tick) % spacing) + spacing) % spacing; remainder != 0 {
+		below := int(tick) - remainder
+		return fmt.Errorf("tick %d is not aligned to tick spacing %d (nearest aligned: %d, %d)",
+			tick, spacing, below, below+spacing)
+	}
+	return nil
+}
+
+// AlignTick rounds tick to a multiple of spacing, down (towards MinTick) or up (towards MaxTick)
+// The result is clamped to the outermost aligned ticks within [MinTick, MaxTick]
+// Rounding is by value, so AlignTick(-249650, 200, false) is -249800, not -249600
+func AlignTick(tick int32, spacing int, roundUp bool) int32 {
+	if spacing <= 0 {
+		return tick
+	}
+	t := int(tick)
+	// Go's % truncates towards zero; normalize so the remainder is always in [0, spacing)
+	aligned := t - ((t%spacing)+spacing)%spacing
+	if roundUp && aligned != t {
+		aligned += spacing
+	}
+
+	maxAligned := MaxTick / spacing * spacing
+	return int32(max(min(aligned, maxAligned), -maxAligned))
+}
+
 // CalculateTickBounds calculates tick bounds from current tick and range width
 // rangeWidth N means ±(N/2) tick ranges from current tick
 // Returns tickLower, tickUpper, or error if bounds invalid
-// Edge case: For extreme ticks near ±887272, bounds are clamped to valid range
+// Edge case: For extreme ticks near MinTick/MaxTick, bounds are clamped to the outermost aligned ticks in range
 func CalculateTickBounds(currentTick int32, rangeWidth int, tickSpacing int) (int32, int32, error) {
-	const maxTick = 887272
+	if tickSpacing <= 0 {
+		return 0, 0, fmt.Errorf("invalid tick spacing %d", tickSpacing)
+	}
+	if err := CheckTick(int(currentTick)); err != nil {
+		return 0, 0, fmt.Errorf("invalid current tick: %w", err)
+	}
 
 	halfWidth := rangeWidth / 2
 	// tickIndex := int(currentTick) / tickSpacing
@@ -49,30 +121,24 @@ func CalculateTickBounds(currentTick int32, rangeWidth int, tickSpacing int) (in
 	rawTickLower := (tickIndex - halfWidth) * tickSpacing
 	rawTickUpper := (tickIndex + halfWidth) * tickSpacing
 
-	// Clamp to valid tick range for edge cases near ±maxTick
-	// This handles extreme ticks where calculated bounds would exceed limits
-	tickLower := int32(rawTickLower)
-	tickUpper := int32(rawTickUpper)
+	// Clamp to the valid tick range for edge cases near MinTick/MaxTick
+	// The limits are rounded inwards so clamped bounds stay aligned to the tick spacing
+	rawTickLower = int(AlignTick(int32(max(min(rawTickLower, MaxTick), MinTick)), tickSpacing, true))
+	rawTickUpper = int(AlignTick(int32(max(min(rawTickUpper, MaxTick), MinTick)), tickSpacing, false))
 
-	if tickLower < -maxTick {
-		tickLower = -maxTick
-	}
-	if tickLower > maxTick {
-		tickLower = maxTick
-	}
-	if tickUpper < -maxTick {
-		tickUpper = -maxTick
+	// Validate tickLower < tickUpper (should always be true after clamping)
+	if rawTickLower >= rawTickUpper {
+		return 0, 0, fmt.Errorf("tickLower (%d) must be < tickUpper (%d) - current tick %d with range width %d creates invalid bounds", rawTickLower, rawTickUpper, currentTick, rangeWidth)
 	}
-	if tickUpper > maxTick {
-		tickUpper = maxTick
+	// Both bounds must be usable by TickToSqrtPriceX96
+	if err := CheckTick(rawTickLower); err != nil {
+		return 0, 0, fmt.Errorf("invalid tickLower: %w", err)
 	}
-
-	// Validate tickLower < tickUpper (should always be true after clamping)
-	if tickLower >= tickUpper {
-		return 0, 0, fmt.Errorf("tickLower (%d) must be < tickUpper (%d) - current tick %d with range width %d creates invalid bounds", tickLower, tickUpper, currentTick, rangeWidth)
+	if err := CheckTick(rawTickUpper); err != nil {
+		return 0, 0, fmt.Errorf("invalid tickUpper: %w", err)
 	}
 
-	return tickLower, tickUpper, nil
+	return int32(rawTickLower), int32(rawTickUpper), nil
 }
 
 // CalculateOptimalRangeWidthForCL1 calculates optimal tick bounds to minimize wasted tokens
@@ -205,12 +271,58 @@ func CalculateOptimalRangeWidthForCL1(
 	return bestLower, bestUpper, bestAmount0, bestAmount1, nil
 }
 
+// OptimalRangeWidth returns the smallest even range width, in units of tickSpacing, at which ComputeAmounts uses
+// at least targetUtilizationPct percent of both maxToken0 and maxToken1
+// Bounds are placed as CalculateTickBounds places them; widths are tried up to the widest whose bounds fit
+// within [MinTick, MaxTick], and an error is returned if none meets the target
+func OptimalRangeWidth(sqrtPrice *big.Int, tick int32, maxToken0, maxToken1 *big.Int, tickSpacing int, targetUtilizationPct int) (int, error) {
+	if sqrtPrice == nil || sqrtPrice.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid sqrt price")
+	}
+	if maxToken0 == nil || maxToken0.Sign() <= 0 || maxToken1 == nil || maxToken1.Sign() <= 0 {
+		return 0, fmt.Errorf("both token budgets must be > 0")
+	}
+	if tickSpacing <= 0 {
+		return 0, fmt.Errorf("invalid tick spacing %d", tickSpacing)
+	}
+	if targetUtilizationPct <= 0 || targetUtilizationPct > 100 {
+		return 0, fmt.Errorf("target utilization must be between 1 and 100 percent, got %d", targetUtilizationPct)
+	}
+
+	target := big.NewInt(int64(targetUtilizationPct))
+	bestUtilization := int64(-1)
+	maxWidth := MaxTick / tickSpacing * 2
+	for rangeWidth := 2; rangeWidth <= maxWidth; rangeWidth += 2 {
+		tickLower, tickUpper, err := CalculateTickBounds(tick, rangeWidth, tickSpacing)
+		if err != nil {
+			return 0, fmt.Errorf("failed to calculate tick bounds for range width %d: %w", rangeWidth, err)
+		}
+		amount0, amount1, _ := ComputeAmounts(sqrtPrice, int(tick), int(tickLower), int(tickUpper), maxToken0, maxToken1)
+
+		utilization0 := new(big.Int).Mul(amount0, big.NewInt(100))
+		utilization0.Div(utilization0, maxToken0)
+		utilization1 := new(big.Int).Mul(amount1, big.NewInt(100))
+		utilization1.Div(utilization1, maxToken1)
+		if utilization0.Cmp(target) >= 0 && utilization1.Cmp(target) >= 0 {
+			return rangeWidth, nil
+		}
+		bestUtilization = max(bestUtilization, min(utilization0.Int64(), utilization1.Int64()))
+	}
+
+	return 0, fmt.Errorf("no range width up to %d reaches %d%% utilization of both tokens (best %d%%)",
+		maxWidth, targetUtilizationPct, bestUtilization)
+}
+
 // CalculateMinAmount calculates minimum amount with slippage protection
 // amountMin = amountDesired * (100 - slippagePct) / 100
+// This is the single place minimum amounts are derived; amountDesired is never mutated
 func CalculateMinAmount(amountDesired *big.Int, slippagePct int) *big.Int {
-	if amountDesired == nil {
+	if amountDesired == nil || slippagePct >= 100 {
 		return big.NewInt(0)
 	}
+	if slippagePct < 0 {
+		slippagePct = 0
+	}
 
 	// amountMin = amountDesired * (100 - slippagePct) / 100
 	multiplier := big.NewInt(int64(100 - slippagePct))
@@ -222,6 +334,32 @@ func CalculateMinAmount(amountDesired *big.Int, slippagePct int) *big.Int {
 	return result
 }
 
+// DeadlineFromNow returns the transaction deadline d from now in Unix seconds
+// Contracts compare deadlines against block.timestamp, which is in seconds; a millisecond
+// deadline would silently never expire
+func DeadlineFromNow(d time.Duration) *big.Int {
+	return big.NewInt(time.Now().Add(d).Unix())
+}
+
+// ParseReceiptUint parses an unsigned receipt quantity given as 0x-prefixed hex or plain decimal
+// Nodes differ in which they return, and SetString's auto-base would read a leading zero as octal
+// Returns false for empty, negative or malformed input
+func ParseReceiptUint(s string) (*big.Int, bool) {
+	s = strings.TrimSpace(s)
+	base := 10
+	if len(s) >= 2 && s[0] == '0' && (s[1] == 'x' || s[1] == 'X') {
+		s, base = s[2:], 16
+	}
+	if s == "" || s[0] == '-' || s[0] == '+' {
+		return nil, false
+	}
+	n, ok := new(big.Int).SetString(s, base)
+	if !ok {
+		return nil, false
+	}
+	return n, true
+}
+
 // ExtractGasCost extracts gas cost from transaction receipt
 // Returns gas cost in wei (GasUsed * EffectiveGasPrice)
 func ExtractGasCost(receipt *types.TxReceipt) (*big.Int, error) {
@@ -230,15 +368,15 @@ func ExtractGasCost(receipt *types.TxReceipt) (*big.Int, error) {
 	}
 
 	// Parse GasUsed from string
-	gasUsed := new(big.Int)
-	if _, ok := gasUsed.SetString(receipt.GasUsed, 0); !ok {
-		return nil, fmt.Errorf("failed to parse GasUsed: %s", receipt.GasUsed)
+	gasUsed, ok := ParseReceiptUint(receipt.GasUsed)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse GasUsed: %q", receipt.GasUsed)
 	}
 
 	// Parse EffectiveGasPrice from string
-	gasPrice := new(big.Int)
-	if _, ok := gasPrice.SetString(receipt.EffectiveGasPrice, 0); !ok {
-		return nil, fmt.Errorf("failed to parse EffectiveGasPrice: %s", receipt.EffectiveGasPrice)
+	gasPrice, ok := ParseReceiptUint(receipt.EffectiveGasPrice)
+	if !ok {
+		return nil, fmt.Errorf("failed to parse EffectiveGasPrice: %q", receipt.EffectiveGasPrice)
 	}
 
 	// Calculate gas cost
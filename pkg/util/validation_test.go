@@ -2,7 +2,9 @@ package util
 
 import (
 	"math/big"
+	"strings"
 	"testing"
+	"time"
 )
 
 // TestCalculateOptimalRangeWidthForCL1 tests the optimal range width calculation
@@ -203,3 +205,246 @@ func TestCalculateOptimalRangeWidthForCL1_EdgeCases(t *testing.T) {
 		t.Logf("USDC utilization: %d%%", utilization1.Int64())
 	})
 }
+
+// TestCalculateMinAmountDoesNotMutate ensures computing minimums leaves the desired amounts intact
+func TestCalculateMinAmountDoesNotMutate(t *testing.T) {
+	amount0Desired := big.NewInt(1_000_000_000_000_000_000)
+	amount1Desired := big.NewInt(12_490_000)
+
+	amount0Min := CalculateMinAmount(amount0Desired, 5)
+	amount1Min := CalculateMinAmount(amount1Desired, 5)
+
+	if amount0Desired.Cmp(big.NewInt(1_000_000_000_000_000_000)) != 0 {
+		t.Errorf("amount0Desired mutated: %s", amount0Desired)
+	}
+	if amount1Desired.Cmp(big.NewInt(12_490_000)) != 0 {
+		t.Errorf("amount1Desired mutated: %s", amount1Desired)
+	}
+	if amount0Min.Cmp(big.NewInt(950_000_000_000_000_000)) != 0 {
+		t.Errorf("amount0Min = %s, want 950000000000000000", amount0Min)
+	}
+	if amount1Min.Cmp(big.NewInt(11_865_500)) != 0 {
+		t.Errorf("amount1Min = %s, want 11865500", amount1Min)
+	}
+
+	// Out-of-range positions return the budget itself; it must be a copy
+	sqrtPrice := TickToSqrtPriceX96(-251400)
+	maxWAVAX := big.NewInt(1_000_000_000_000_000_000)
+	amount0, _, _ := ComputeAmounts(sqrtPrice, -251400, -251000, -250000, maxWAVAX, big.NewInt(0))
+	CalculateMinAmount(amount0, 5)
+	amount0.SetInt64(0)
+	if maxWAVAX.Cmp(big.NewInt(1_000_000_000_000_000_000)) != 0 {
+		t.Errorf("ComputeAmounts result aliases maxWAVAX")
+	}
+}
+
+// TestAssertTickAligned verifies misaligned bounds are rejected with the nearest aligned ticks
+func TestAssertTickAligned(t *testing.T) {
+	for _, tick := range []int32{-252000, 0, 400, -887200} {
+		if err := AssertTickAligned(tick, 200); err != nil {
+			t.Errorf("tick %d: unexpected error %v", tick, err)
+		}
+	}
+
+	err := AssertTickAligned(-251950, 200)
+	if err == nil {
+		t.Fatal("expected error for misaligned tick -251950")
+	}
+	if !strings.Contains(err.Error(), "nearest aligned: -252000, -251800") {
+		t.Errorf("unexpected error message: %v", err)
+	}
+
+	// Clamped bounds at ±887272 are not multiples of 200
+	if err := AssertTickAligned(887272, 200); err == nil {
+		t.Error("expected error for clamped max tick")
+	}
+	if err := AssertTickAligned(100, 0); err == nil {
+		t.Error("expected error for zero spacing")
+	}
+}
+
+// TestValidateStakingRequest verifies range widths are checked against the pool tick spacing
+func TestValidateStakingRequest(t *testing.T) {
+	maxWAVAX := big.NewInt(1_000_000_000_000_000_000)
+	maxUSDC := big.NewInt(40_000_000)
+
+	if err := ValidateStakingRequest(maxWAVAX, maxUSDC, 10, 200, 5); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	// ±887200 ticks, just inside MaxTick
+	if err := ValidateStakingRequest(maxWAVAX, maxUSDC, 17744, 100, 5); err != nil {
+		t.Errorf("unexpected error for range within MaxTick: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		rangeWidth  int
+		tickSpacing int
+		wantErr     string
+	}{
+		{"OddWidth", 7, 200, "must be even"},
+		{"ZeroWidth", 0, 200, "must be even"},
+		{"NegativeWidth", -4, 200, "must be even"},
+		{"ZeroSpacing", 10, 0, "invalid tick spacing"},
+		{"BeyondMaxTick", 8874, 200, "beyond the maximum tick"},
+		{"WideSpacing", 10, 200_000, "beyond the maximum tick"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateStakingRequest(maxWAVAX, maxUSDC, tt.rangeWidth, tt.tickSpacing, 5)
+			if err == nil {
+				t.Fatalf("expected error for range width %d at spacing %d", tt.rangeWidth, tt.tickSpacing)
+			}
+			if !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("unexpected error message: %v", err)
+			}
+		})
+	}
+}
+
+func TestDeadlineFromNow(t *testing.T) {
+	before := time.Now().Add(20 * time.Minute).Unix()
+	deadline := DeadlineFromNow(20 * time.Minute)
+	after := time.Now().Add(20 * time.Minute).Unix()
+
+	// Unix seconds, not milliseconds
+	if deadline.Int64() < before || deadline.Int64() > after {
+		t.Errorf("DeadlineFromNow(20m) = %s; want Unix seconds in [%d, %d]", deadline, before, after)
+	}
+}
+
+// TestAlignTick checks the rounding direction for the negative ticks the WAVAX/USDC pool trades at
+func TestAlignTick(t *testing.T) {
+	tests := []struct {
+		tick    int32
+		spacing int
+		roundUp bool
+		want    int32
+	}{
+		{-249650, 200, false, -249800},
+		{-249650, 200, true, -249600},
+		{-249600, 200, false, -249600},
+		{-249600, 200, true, -249600},
+		{-1, 200, false, -200},
+		{-1, 200, true, 0},
+		{150, 200, false, 0},
+		{150, 200, true, 200},
+		{-252000, 6, true, -252000},
+		{-252001, 6, false, -252006},
+		// Clamped to the outermost aligned ticks
+		{MinTick, 200, false, -887200},
+		{MaxTick, 200, true, 887200},
+	}
+	for _, tt := range tests {
+		if got := AlignTick(tt.tick, tt.spacing, tt.roundUp); got != tt.want {
+			t.Errorf("AlignTick(%d, %d, %v) = %d; want %d", tt.tick, tt.spacing, tt.roundUp, got, tt.want)
+		}
+	}
+}
+
+func TestParseReceiptUint(t *testing.T) {
+	tests := []struct {
+		input string
+		want  int64
+		ok    bool
+	}{
+		{"0x5208", 21000, true},
+		{"0X5208", 21000, true},
+		{"21000", 21000, true},
+		{"021000", 21000, true}, // decimal, not octal
+		{" 0x3b9aca00 ", 1_000_000_000, true},
+		{"0x0", 0, true},
+		{"", 0, false},
+		{"0x", 0, false},
+		{"0xzz", 0, false},
+		{"21k", 0, false},
+		{"-21000", 0, false},
+		{"1_000", 0, false},
+	}
+	for _, tt := range tests {
+		got, ok := ParseReceiptUint(tt.input)
+		if ok != tt.ok {
+			t.Errorf("ParseReceiptUint(%q) ok = %v; want %v", tt.input, ok, tt.ok)
+			continue
+		}
+		if ok && got.Int64() != tt.want {
+			t.Errorf("ParseReceiptUint(%q) = %s; want %d", tt.input, got, tt.want)
+		}
+	}
+}
+
+// TestOptimalRangeWidth searches widths for the simulation's $1000 50/50 AVAX/USDC split
+func TestOptimalRangeWidth(t *testing.T) {
+	tickSpacing := 200
+	// fiftyFifty returns $500 of AVAX and $500 of USDC at tick
+	fiftyFifty := func(tick int32) (*big.Int, *big.Int, *big.Int) {
+		sqrtPrice := TickToSqrtPriceX96(int(tick))
+		avax := new(big.Float).Quo(big.NewFloat(500), SqrtPriceToHumanPrice(sqrtPrice, 18, 6))
+		avaxWei, _ := avax.Mul(avax, big.NewFloat(1e18)).Int(nil)
+		return sqrtPrice, avaxWei, big.NewInt(500_000_000)
+	}
+
+	t.Run("CenteredTick", func(t *testing.T) {
+		// -251400 is on a spacing boundary, so the range is symmetric and a 50/50 split fits any width
+		sqrtPrice, maxAVAX, maxUSDC := fiftyFifty(-251400)
+		width, err := OptimalRangeWidth(sqrtPrice, -251400, maxAVAX, maxUSDC, tickSpacing, 95)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if width != 2 {
+			t.Errorf("expected the narrowest width 2, got %d", width)
+		}
+	})
+
+	t.Run("OffCenterTick", func(t *testing.T) {
+		// 50 ticks above the nearest boundary skews narrow ranges towards one token
+		tick := int32(-251450)
+		sqrtPrice, maxAVAX, maxUSDC := fiftyFifty(tick)
+		width, err := OptimalRangeWidth(sqrtPrice, tick, maxAVAX, maxUSDC, tickSpacing, 90)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if width <= 2 || width > 20 || width%2 != 0 {
+			t.Fatalf("expected an even width in (2, 20], got %d", width)
+		}
+
+		// utilization returns the lower of the two token utilizations at rangeWidth
+		utilization := func(rangeWidth int) int64 {
+			tickLower, tickUpper, err := CalculateTickBounds(tick, rangeWidth, tickSpacing)
+			if err != nil {
+				t.Fatalf("CalculateTickBounds(%d): %v", rangeWidth, err)
+			}
+			amount0, amount1, _ := ComputeAmounts(sqrtPrice, int(tick), int(tickLower), int(tickUpper), maxAVAX, maxUSDC)
+			util0 := new(big.Int).Div(new(big.Int).Mul(amount0, big.NewInt(100)), maxAVAX).Int64()
+			util1 := new(big.Int).Div(new(big.Int).Mul(amount1, big.NewInt(100)), maxUSDC).Int64()
+			return min(util0, util1)
+		}
+		if got := utilization(width); got < 90 {
+			t.Errorf("width %d reaches only %d%% utilization", width, got)
+		}
+		if got := utilization(width - 2); got >= 90 {
+			t.Errorf("narrower width %d already reaches %d%% utilization", width-2, got)
+		}
+	})
+
+	t.Run("Unreachable", func(t *testing.T) {
+		// A single USDC unit caps liquidity far below the AVAX budget at every width
+		sqrtPrice, maxAVAX, _ := fiftyFifty(-251450)
+		if _, err := OptimalRangeWidth(sqrtPrice, -251450, maxAVAX, big.NewInt(1), tickSpacing, 90); err == nil {
+			t.Error("expected error when no width reaches the target")
+		}
+	})
+
+	t.Run("InvalidInput", func(t *testing.T) {
+		sqrtPrice, maxAVAX, maxUSDC := fiftyFifty(-251400)
+		if _, err := OptimalRangeWidth(sqrtPrice, -251400, maxAVAX, maxUSDC, tickSpacing, 101); err == nil {
+			t.Error("expected error for target above 100%")
+		}
+		if _, err := OptimalRangeWidth(sqrtPrice, -251400, maxAVAX, maxUSDC, 0, 90); err == nil {
+			t.Error("expected error for zero tick spacing")
+		}
+		if _, err := OptimalRangeWidth(sqrtPrice, -251400, big.NewInt(0), maxUSDC, tickSpacing, 90); err == nil {
+			t.Error("expected error for empty token0 budget")
+		}
+	})
+}
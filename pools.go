@@ -0,0 +1,284 @@
+package blackholedex
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/contractclient"
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PoolInfo describes an Algebra pool available for a token pair
+// The embedded AMMState and the token ordering are only filled by GetPoolInfo
+type PoolInfo struct {
+	types.AMMState
+	Address     common.Address
+	Deployer    common.Address // Custom pool deployer (zero for the factory's default pool)
+	Token0      common.Address
+	Token1      common.Address
+	Fee         uint16 // Current fee in hundredths of a bip (1e-6)
+	TickSpacing int
+	Liquidity   *big.Int // In-range liquidity
+}
+
+// GetPoolInfo reads the AMM state, tick spacing, fee and token ordering of a pool
+// This is a read-only operation that does not create a transaction
+func (b *Blackhole) GetPoolInfo(poolAddress common.Address) (*PoolInfo, error) {
+	poolClient, err := b.poolClient(poolAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := readAMMState(b.rpcContext(), poolClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get state of pool %s: %w", poolAddress.Hex(), err)
+	}
+	token0Result, err := poolClient.CallCtx(b.rpcContext(), &b.myAddr, "token0")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token0 of pool %s: %w", poolAddress.Hex(), err)
+	}
+	token1Result, err := poolClient.CallCtx(b.rpcContext(), &b.myAddr, "token1")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token1 of pool %s: %w", poolAddress.Hex(), err)
+	}
+
+	info := &PoolInfo{
+		AMMState: *state,
+		Address:  poolAddress,
+		Token0:   token0Result[0].(common.Address),
+		Token1:   token1Result[0].(common.Address),
+	}
+	if err := b.readPoolInfo(info); err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// ListPoolsForPair discovers the pools for token0/token1 through the Algebra factory
+// It checks the default pool and the custom pool of every known deployer (see WithPoolDeployers)
+// Results are sorted by in-range liquidity, deepest first
+func (b *Blackhole) ListPoolsForPair(token0, token1 common.Address) ([]PoolInfo, error) {
+	factoryClient, err := b.registry.Client(algebraFactory)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Algebra factory client: %w", err)
+	}
+
+	// Algebra pools are keyed by sorted token addresses
+	if bytes.Compare(token0.Bytes(), token1.Bytes()) > 0 {
+		token0, token1 = token1, token0
+	}
+
+	var pools []PoolInfo
+
+	result, err := factoryClient.CallCtx(b.rpcContext(), &b.myAddr, "poolByPair", token0, token1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query default pool: %w", err)
+	}
+	if poolAddr := result[0].(common.Address); poolAddr != (common.Address{}) {
+		pools = append(pools, PoolInfo{Address: poolAddr})
+	}
+
+	for _, deployerAddr := range b.poolDeployers() {
+		result, err := factoryClient.CallCtx(b.rpcContext(), &b.myAddr, "customPoolByPair", deployerAddr, token0, token1)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query custom pool for deployer %s: %w", deployerAddr.Hex(), err)
+		}
+		if poolAddr := result[0].(common.Address); poolAddr != (common.Address{}) {
+			pools = append(pools, PoolInfo{Address: poolAddr, Deployer: deployerAddr})
+		}
+	}
+
+	for i := range pools {
+		if err := b.readPoolInfo(&pools[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	sort.SliceStable(pools, func(i, j int) bool {
+		return pools[i].Liquidity.Cmp(pools[j].Liquidity) > 0
+	})
+	return pools, nil
+}
+
+// poolDeployers returns the configured custom pool deployers, defaulting to the registered deployer
+func (b *Blackhole) poolDeployers() []common.Address {
+	if len(b.deployers) > 0 {
+		return b.deployers
+	}
+	if deployerAddr, err := b.registry.GetAddress(deployer); err == nil {
+		return []common.Address{deployerAddr}
+	}
+	return nil
+}
+
+// usdcIsToken0 reports whether the pool orders USDC before WAVAX
+// Returns error if the pool is not a WAVAX/USDC pool
+func (b *Blackhole) usdcIsToken0(info *PoolInfo) (bool, error) {
+	wavaxAddr, _ := b.registry.GetAddress(wavax)
+	usdcAddr, _ := b.registry.GetAddress(usdc)
+	switch {
+	case info.Token0 == wavaxAddr && info.Token1 == usdcAddr:
+		return false, nil
+	case info.Token0 == usdcAddr && info.Token1 == wavaxAddr:
+		return true, nil
+	}
+	return false, fmt.Errorf("pool %s holds %s/%s, not WAVAX/USDC", info.Address.Hex(), info.Token0.Hex(), info.Token1.Hex())
+}
+
+// pairInfo reads the registered WAVAX/USDC pool and whether it orders USDC before WAVAX
+func (b *Blackhole) pairInfo() (*PoolInfo, bool, error) {
+	pairAddr, err := b.registry.GetAddress(wavaxUsdcPair)
+	if err != nil {
+		return nil, false, err
+	}
+	info, err := b.GetPoolInfo(pairAddr)
+	if err != nil {
+		return nil, false, err
+	}
+	usdcFirst, err := b.usdcIsToken0(info)
+	if err != nil {
+		return nil, false, err
+	}
+	return info, usdcFirst, nil
+}
+
+// wavaxUSDCAmounts maps amount0/amount1 of a token0/token1 position to (WAVAX, USDC)
+// Returns error if the position is not a WAVAX/USDC position
+func (b *Blackhole) wavaxUSDCAmounts(token0, token1 common.Address, amount0, amount1 *big.Int) (*big.Int, *big.Int, error) {
+	wavaxAddr, _ := b.registry.GetAddress(wavax)
+	usdcAddr, _ := b.registry.GetAddress(usdc)
+	switch {
+	case token0 == wavaxAddr && token1 == usdcAddr:
+		return amount0, amount1, nil
+	case token0 == usdcAddr && token1 == wavaxAddr:
+		return amount1, amount0, nil
+	}
+	return nil, nil, fmt.Errorf("position holds %s/%s, not WAVAX/USDC", token0.Hex(), token1.Hex())
+}
+
+// readPoolInfo fills fee, tick spacing and liquidity from the pool contract
+func (b *Blackhole) readPoolInfo(info *PoolInfo) error {
+	poolClient, err := b.poolClient(info.Address)
+	if err != nil {
+		return err
+	}
+
+	feeResult, err := poolClient.CallCtx(b.rpcContext(), &b.myAddr, "fee")
+	if err != nil {
+		return fmt.Errorf("failed to get fee of pool %s: %w", info.Address.Hex(), err)
+	}
+	spacingResult, err := poolClient.CallCtx(b.rpcContext(), &b.myAddr, "tickSpacing")
+	if err != nil {
+		return fmt.Errorf("failed to get tick spacing of pool %s: %w", info.Address.Hex(), err)
+	}
+	liquidityResult, err := poolClient.CallCtx(b.rpcContext(), &b.myAddr, "liquidity")
+	if err != nil {
+		return fmt.Errorf("failed to get liquidity of pool %s: %w", info.Address.Hex(), err)
+	}
+
+	info.Fee = feeResult[0].(uint16)
+	info.TickSpacing = int(spacingResult[0].(*big.Int).Int64())
+	info.Liquidity = liquidityResult[0].(*big.Int)
+	return nil
+}
+
+// poolClient returns the registered client for a pool, or builds one with the configured pool ABI
+func (b *Blackhole) poolClient(poolAddr common.Address) (ContractClient, error) {
+	if c, err := b.registry.ClientByAddress(poolAddr.Hex()); err == nil {
+		return c, nil
+	}
+
+	pairClient, err := b.registry.Client(wavaxUsdcPair)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool ABI from %s: %w", wavaxUsdcPair, err)
+	}
+	if b.client == nil {
+		return nil, fmt.Errorf("no client for unregistered pool %s", poolAddr.Hex())
+	}
+	return contractclient.NewContractClient(b.client, poolAddr, pairClient.Abi()), nil
+}
+
+// PoolMetadata summarizes a pool for choosing position ranges
+// Prices are token1 per token0 in whole token units
+type PoolMetadata struct {
+	Address            common.Address
+	Token0             common.Address
+	Token1             common.Address
+	Decimals0          uint8
+	Decimals1          uint8
+	TickSpacing        int
+	Tick               int32
+	SqrtPrice          *big.Int
+	Price              float64
+	PriceChangePerTick float64 // Price change of one tick at the current price
+	MinPriceStep       float64 // Price change of one tick spacing: the finest range granularity
+}
+
+// String formats the metadata for display
+func (m *PoolMetadata) String() string {
+	return fmt.Sprintf("pool %s: token0 %s (%d decimals), token1 %s (%d decimals), tick spacing %d, tick %d, price %g, %g per tick, %g per spacing",
+		m.Address.Hex(), m.Token0.Hex(), m.Decimals0, m.Token1.Hex(), m.Decimals1,
+		m.TickSpacing, m.Tick, m.Price, m.PriceChangePerTick, m.MinPriceStep)
+}
+
+// PoolInfo reads the tokens, decimals, tick spacing and current price of a pool
+// and derives the price granularity of its ticks
+// This is a read-only operation that does not create a transaction
+func (b *Blackhole) PoolInfo(pool common.Address) (*PoolMetadata, error) {
+	info, err := b.GetPoolInfo(pool)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals0, err := b.tokenDecimals(info.Token0)
+	if err != nil {
+		return nil, err
+	}
+	decimals1, err := b.tokenDecimals(info.Token1)
+	if err != nil {
+		return nil, err
+	}
+
+	// Raw price is in smallest units; scale by 10^(decimals0 - decimals1)
+	rawPrice, _ := util.SqrtPriceToPrice(info.SqrtPrice).Float64()
+	price := rawPrice * math.Pow10(int(decimals0)-int(decimals1))
+
+	// Each tick moves the price by a factor of 1.0001
+	return &PoolMetadata{
+		Address:            pool,
+		Token0:             info.Token0,
+		Token1:             info.Token1,
+		Decimals0:          decimals0,
+		Decimals1:          decimals1,
+		TickSpacing:        info.TickSpacing,
+		Tick:               info.Tick,
+		SqrtPrice:          info.SqrtPrice,
+		Price:              price,
+		PriceChangePerTick: price * 0.0001,
+		MinPriceStep:       price * (math.Pow(1.0001, float64(info.TickSpacing)) - 1),
+	}, nil
+}
+
+// tokenDecimals reads the decimals of an ERC20 token
+// Unregistered tokens are read with the USDC client's ERC20 ABI
+func (b *Blackhole) tokenDecimals(token common.Address) (uint8, error) {
+	tokenClient, err := b.registry.ClientByAddress(token.Hex())
+	if err != nil {
+		usdcClient, usdcErr := b.registry.Client(usdc)
+		if usdcErr != nil || b.client == nil {
+			return 0, fmt.Errorf("no client for token %s: %w", token.Hex(), err)
+		}
+		tokenClient = contractclient.NewContractClient(b.client, token, usdcClient.Abi())
+	}
+
+	result, err := tokenClient.CallCtx(b.rpcContext(), &b.myAddr, "decimals")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get decimals of token %s: %w", token.Hex(), err)
+	}
+	return result[0].(uint8), nil
+}
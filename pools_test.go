@@ -0,0 +1,193 @@
+package blackholedex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListPoolsForPair(t *testing.T) {
+	factoryABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraCLFactory.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	wavaxAddr := common.HexToAddress("0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7")
+	usdcAddr := common.HexToAddress("0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E")
+	cl200Deployer := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	cl1Deployer := common.HexToAddress("0x00000000000000000000000000000000000000e2")
+	noPoolDeployer := common.HexToAddress("0x00000000000000000000000000000000000000e3")
+	cl200Pool := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	cl1Pool := common.HexToAddress("0x00000000000000000000000000000000000000d2")
+
+	customPools := map[common.Address]common.Address{cl200Deployer: cl200Pool, cl1Deployer: cl1Pool}
+
+	// The factory mock ABI-encodes its answers and decodes them as the real client would
+	factory := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000f2"))
+	factory.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		var pool common.Address
+		switch method {
+		case "poolByPair":
+			// No default pool for this pair
+			if args[0].(common.Address) != wavaxAddr {
+				return nil, errors.New("tokens not sorted")
+			}
+		case "customPoolByPair":
+			if args[1].(common.Address) != wavaxAddr {
+				return nil, errors.New("tokens not sorted")
+			}
+			pool = customPools[args[0].(common.Address)]
+		default:
+			return nil, errors.New("unexpected method " + method)
+		}
+		encoded, err := factoryABI.Methods[method].Outputs.Pack(pool)
+		if err != nil {
+			return nil, err
+		}
+		return factoryABI.Unpack(method, encoded)
+	}
+
+	newPool := func(addr common.Address, fee uint16, spacing int64, liquidity int64) *mockContractClient {
+		pool := newMockContractClient(addr)
+		pool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			switch method {
+			case "fee":
+				return []interface{}{fee}, nil
+			case "tickSpacing":
+				return []interface{}{big.NewInt(spacing)}, nil
+			case "liquidity":
+				return []interface{}{big.NewInt(liquidity)}, nil
+			}
+			return nil, errors.New("unexpected method " + method)
+		}
+		return pool
+	}
+
+	b := newTestBlackhole(map[string]ContractClient{
+		algebraFactory: factory,
+		"cl200Pool":    newPool(cl200Pool, 3000, 200, 5_000),
+		"cl1Pool":      newPool(cl1Pool, 100, 1, 80_000),
+	}, &mockTxListener{})
+	WithPoolDeployers(cl200Deployer, cl1Deployer, noPoolDeployer)(b)
+
+	// Unsorted input: WAVAX sorts before USDC
+	pools, err := b.ListPoolsForPair(usdcAddr, wavaxAddr)
+	assert.NoError(t, err)
+	if assert.Len(t, pools, 2) {
+		// Deepest pool first
+		assert.Equal(t, PoolInfo{Address: cl1Pool, Deployer: cl1Deployer, Fee: 100, TickSpacing: 1, Liquidity: big.NewInt(80_000)}, pools[0])
+		assert.Equal(t, PoolInfo{Address: cl200Pool, Deployer: cl200Deployer, Fee: 3000, TickSpacing: 200, Liquidity: big.NewInt(5_000)}, pools[1])
+	}
+}
+
+func TestGetPoolInfo(t *testing.T) {
+	poolABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraPoolState.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	wavaxAddr := common.HexToAddress("0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7")
+	usdcAddr := common.HexToAddress("0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E")
+	poolAddr := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+
+	answers := map[string][]interface{}{
+		"safelyGetStateOfAMM": {util.Q96, big.NewInt(-400), uint16(2500), uint8(0), big.NewInt(9_000), big.NewInt(-200), big.NewInt(-600)},
+		"token0":              {wavaxAddr},
+		"token1":              {usdcAddr},
+		"fee":                 {uint16(2500)},
+		"tickSpacing":         {big.NewInt(200)},
+		"liquidity":           {big.NewInt(9_000)},
+	}
+	pool := newABIMock(poolAddr, poolABI, answers)
+
+	b := newTestBlackhole(map[string]ContractClient{wavaxUsdcPair: pool}, &mockTxListener{})
+
+	info, err := b.GetPoolInfo(poolAddr)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, poolAddr, info.Address)
+	assert.Equal(t, wavaxAddr, info.Token0)
+	assert.Equal(t, usdcAddr, info.Token1)
+	assert.Equal(t, 200, info.TickSpacing)
+	assert.Equal(t, uint16(2500), info.Fee)
+	assert.Equal(t, int32(-400), info.Tick)
+	assert.Equal(t, util.Q96, info.SqrtPrice)
+	assert.Equal(t, big.NewInt(9_000), info.Liquidity)
+}
+
+// newABIMock returns a client serving fixed answers per method, ABI-encoded and decoded as the real client would
+func newABIMock(addr common.Address, contractABI *abi.ABI, answers map[string][]interface{}) *mockContractClient {
+	c := newMockContractClient(addr)
+	c.abi = contractABI
+	c.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		answer, ok := answers[method]
+		if !ok {
+			return nil, errors.New("unexpected method " + method)
+		}
+		encoded, err := contractABI.Methods[method].Outputs.Pack(answer...)
+		if err != nil {
+			return nil, err
+		}
+		return contractABI.Unpack(method, encoded)
+	}
+	return c
+}
+
+func TestPoolInfoMetadata(t *testing.T) {
+	poolABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraPoolState.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	erc20ABI, err := util.LoadABI("blackholedex-contracts/abi/ERC20.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	wavaxAddr := common.HexToAddress("0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7")
+	usdcAddr := common.HexToAddress("0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E")
+	poolAddr := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+
+	// Raw price 2^-36 USDC units per wei
+	sqrtPrice := new(big.Int).Rsh(util.Q96, 18)
+	pool := newABIMock(poolAddr, poolABI, map[string][]interface{}{
+		"safelyGetStateOfAMM": {sqrtPrice, big.NewInt(-249_500), uint16(2500), uint8(0), big.NewInt(9_000), big.NewInt(-249_400), big.NewInt(-249_600)},
+		"token0":              {wavaxAddr},
+		"token1":              {usdcAddr},
+		"fee":                 {uint16(2500)},
+		"tickSpacing":         {big.NewInt(200)},
+		"liquidity":           {big.NewInt(9_000)},
+	})
+
+	b := newTestBlackhole(map[string]ContractClient{
+		wavaxUsdcPair: pool,
+		wavax:         newABIMock(wavaxAddr, erc20ABI, map[string][]interface{}{"decimals": {uint8(18)}}),
+		usdc:          newABIMock(usdcAddr, erc20ABI, map[string][]interface{}{"decimals": {uint8(6)}}),
+	}, &mockTxListener{})
+
+	meta, err := b.PoolInfo(poolAddr)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, poolAddr, meta.Address)
+	assert.Equal(t, wavaxAddr, meta.Token0)
+	assert.Equal(t, usdcAddr, meta.Token1)
+	assert.Equal(t, uint8(18), meta.Decimals0)
+	assert.Equal(t, uint8(6), meta.Decimals1)
+	assert.Equal(t, 200, meta.TickSpacing)
+	assert.Equal(t, int32(-249_500), meta.Tick)
+	assert.Equal(t, sqrtPrice, meta.SqrtPrice)
+
+	// 2^-36 * 10^12 USDC per WAVAX
+	price := 1e12 / float64(uint64(1)<<36)
+	assert.InDelta(t, price, meta.Price, 1e-9)
+	assert.InDelta(t, price*0.0001, meta.PriceChangePerTick, 1e-12)
+	// 200 ticks move the price by 1.0001^200 - 1, about 2.02%
+	assert.InDelta(t, price*0.020200, meta.MinPriceStep, price*1e-5)
+	assert.Contains(t, meta.String(), "tick spacing 200")
+}
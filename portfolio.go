@@ -1,7 +1,6 @@
 package blackholedex
 
 import (
-	"context"
 	"fmt"
 	"log"
 	"math/big"
@@ -9,36 +8,68 @@ import (
 
 	"github.com/ChoSanghyuk/blackholedex/pkg/types"
 	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
 )
 
 // RecordCurrentAssetSnapshot records a snapshot of the current asset state
 // Used by RunStrategy1 to track portfolio value over time during strategy execution
-func (b *Blackhole) RecordCurrentAssetSnapshot(state types.StrategyPhase) {
+// The strategy's cumulative gas and rewards are recorded alongside the balances
+// Does nothing when no TransactionRecorder was supplied
+func (b *Blackhole) RecordCurrentAssetSnapshot(state *types.StrategyState) {
 	if b.recorder != nil {
-		snapshot, err := b.GetCurrentAssetSnapshot(state)
+		snapshot, err := b.GetCurrentAssetSnapshot(state.CurrentState)
 		if err != nil {
-			log.Printf("Warning: failed to get initial asset snapshot: %v", err)
+			log.Printf("Warning: failed to get asset snapshot: %v", err)
 		} else {
+			snapshot.CumulativeGas = state.CumulativeGas
+			snapshot.CumulativeRewards = state.CumulativeRewards
+			snapshot.NFTTokenID = state.NFTTokenID
+			snapshot.TickLower = state.TickLower
+			snapshot.TickUpper = state.TickUpper
 			if err := b.recorder.RecordReport(*snapshot); err != nil {
-				log.Printf("Warning: failed to record initial snapshot: %v", err)
+				log.Printf("Warning: failed to record asset snapshot: %v", err)
 			} else {
-				log.Printf("Initial asset snapshot recorded at strategy start")
+				log.Printf("Asset snapshot recorded (phase: %s)", state.CurrentState.String())
 			}
 		}
 	}
 }
 
+// recordSnapshotIfDue records an asset snapshot when interval has elapsed since last
+// Called on every monitoring tick; an interval of 0 records a snapshot each tick
+// Returns the time of the most recent snapshot
+func (b *Blackhole) recordSnapshotIfDue(state *types.StrategyState, interval time.Duration, last, now time.Time) time.Time {
+	if b.recorder == nil || now.Sub(last) < interval {
+		return last
+	}
+	b.RecordCurrentAssetSnapshot(state)
+	return now
+}
+
 // GetCurrentAssetSnapshot fetches a complete snapshot of user's assets
 // including wallet balances (WAVAX, USDC, BLACK, AVAX) and position values
 // state: Current strategy phase (can be 0/Initializing if not in strategy mode)
 // Returns CurrentAssetSnapshot with all balances and estimated total value in USDC
 func (b *Blackhole) GetCurrentAssetSnapshot(state types.StrategyPhase) (*types.CurrentAssetSnapshot, error) {
+	snapshot, err := b.GetPortfolioValue()
+	if err != nil {
+		return nil, err
+	}
+	snapshot.Timestamp = time.Now()
+	snapshot.CurrentState = state
+	return snapshot, nil
+}
+
+// GetPortfolioValue values the wallet's WAVAX, USDC, BLACK and native AVAX plus its WAVAX/USDC positions in USDC
+// WAVAX and AVAX are priced by the WAVAX/USDC pool; BLACK by its deepest USDC or WAVAX pool, found through
+// the Algebra factory (BLACK counts as zero without one). Timestamp and CurrentState are left to the caller
+func (b *Blackhole) GetPortfolioValue() (*types.CurrentAssetSnapshot, error) {
 	// Get WAVAX balance from wallet
 	wavaxClient, err := b.registry.Client(wavax)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get WAVAX client: %w", err)
 	}
-	wavaxBalanceResult, err := wavaxClient.Call(&b.myAddr, "balanceOf", b.myAddr)
+	wavaxBalanceResult, err := wavaxClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get WAVAX balance: %w", err)
 	}
@@ -49,7 +80,7 @@ func (b *Blackhole) GetCurrentAssetSnapshot(state types.StrategyPhase) (*types.C
 	if err != nil {
 		return nil, fmt.Errorf("failed to get USDC client: %w", err)
 	}
-	usdcBalanceResult, err := usdcClient.Call(&b.myAddr, "balanceOf", b.myAddr)
+	usdcBalanceResult, err := usdcClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get USDC balance: %w", err)
 	}
@@ -60,14 +91,14 @@ func (b *Blackhole) GetCurrentAssetSnapshot(state types.StrategyPhase) (*types.C
 	if err != nil {
 		return nil, fmt.Errorf("failed to get BLACK client: %w", err)
 	}
-	blackBalanceResult, err := blackClient.Call(&b.myAddr, "balanceOf", b.myAddr)
+	blackBalanceResult, err := blackClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get BLACK balance: %w", err)
 	}
 	blackBalance := blackBalanceResult[0].(*big.Int)
 
 	// Get native AVAX balance from wallet
-	avaxBalance, err := b.client.BalanceAt(context.Background(), b.myAddr, nil)
+	avaxBalance, err := b.balances.BalanceAt(b.rpcContext(), b.myAddr, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get native AVAX balance: %w", err)
 	}
@@ -137,10 +168,11 @@ func (b *Blackhole) GetCurrentAssetSnapshot(state types.StrategyPhase) (*types.C
 	avaxValueFloat := new(big.Float).Mul(new(big.Float).SetInt(avaxBalance), price)
 	avaxValueInUSDC, _ := avaxValueFloat.Int(nil)
 
-	// For BLACK token, we would need BLACK/USDC or BLACK/WAVAX price
-	// For now, we'll skip BLACK in total value calculation or estimate it
-	// TODO: Add BLACK price conversion when BLACK pool data is available
-	blackValueInUSDC := big.NewInt(0)
+	blackValueInUSDC, err := b.blackValueInUSDC(blackBalance, price)
+	if err != nil {
+		log.Printf("Warning: BLACK left out of total value: %v", err)
+		blackValueInUSDC = big.NewInt(0)
+	}
 
 	// Sum total value in USDC
 	totalValue := new(big.Int).Add(usdcBalance, wavaxValueInUSDC)
@@ -154,8 +186,6 @@ func (b *Blackhole) GetCurrentAssetSnapshot(state types.StrategyPhase) (*types.C
 	estimatedAvax, _ := estimatedAvaxFloat.Int(nil)
 
 	snapshot := &types.CurrentAssetSnapshot{
-		Timestamp:     time.Now(),
-		CurrentState:  state,
 		TotalValue:    totalValue,
 		EstimatedAvax: estimatedAvax,
 		AmountWavax:   wavaxBalance,
@@ -167,14 +197,72 @@ func (b *Blackhole) GetCurrentAssetSnapshot(state types.StrategyPhase) (*types.C
 	return snapshot, nil
 }
 
-// sendReport records all StrategyReports and conditionally sends to the reporting channel
-// Always records the report via TransactionRecorder
-// Only sends to reportChan when stateChanged is true (state transition occurred)
-// If the channel is full, the message is dropped to prevent strategy deadlock
-// Implements non-blocking send pattern from research.md R5
-func sendReport(reportChan chan<- string, report types.StrategyReport) {
+// blackValueInUSDC values amount of BLACK in USDC through a BLACK/USDC pool,
+// or through a BLACK/WAVAX pool and wavaxPrice (USDC per WAVAX, smallest units)
+func (b *Blackhole) blackValueInUSDC(amount *big.Int, wavaxPrice *big.Float) (*big.Int, error) {
+	if amount.Sign() == 0 {
+		return big.NewInt(0), nil
+	}
+	blackAddr, err := b.registry.GetAddress(black)
+	if err != nil {
+		return nil, err
+	}
+	usdcAddr, _ := b.registry.GetAddress(usdc)
+	wavaxAddr, _ := b.registry.GetAddress(wavax)
+
+	if value, err := b.valueInToken(amount, blackAddr, usdcAddr); err == nil {
+		return value, nil
+	}
+	inWAVAX, err := b.valueInToken(amount, blackAddr, wavaxAddr)
+	if err != nil {
+		return nil, fmt.Errorf("no BLACK price: %w", err)
+	}
+	value, _ := new(big.Float).Mul(new(big.Float).SetInt(inWAVAX), wavaxPrice).Int(nil)
+	return value, nil
+}
+
+// valueInToken converts amount of token into quote at the price of their deepest pool
+func (b *Blackhole) valueInToken(amount *big.Int, token, quote common.Address) (*big.Int, error) {
+	pools, err := b.ListPoolsForPair(token, quote)
+	if err != nil {
+		return nil, err
+	}
+	if len(pools) == 0 || pools[0].Liquidity.Sign() == 0 {
+		return nil, &ErrNoRoute{From: token, To: quote}
+	}
+	info, err := b.GetPoolInfo(pools[0].Address)
+	if err != nil {
+		return nil, err
+	}
+
+	// The pool price is token1 per token0
+	price := util.SqrtPriceToPrice(info.SqrtPrice)
+	if price.Sign() == 0 {
+		return nil, fmt.Errorf("pool %s has no price", info.Address.Hex())
+	}
+	value := new(big.Float).SetInt(amount)
+	if info.Token0 == token {
+		value.Mul(value, price)
+	} else {
+		value.Quo(value, price)
+	}
+	result, _ := value.Int(nil)
+	return result, nil
+}
+
+// criticalReports are the event types sendReport waits to deliver even when the channel is full
+var criticalReports = map[string]bool{
+	"error":    true,
+	"shutdown": true, // Also sent when the strategy halts
+}
+
+// sendReport keeps the report for ExportState and sends it to the reporting channel
+// Does nothing else when reportChan is nil
+// Critical reports block until received; others are dropped and counted in DroppedReports when
+// the channel is full, so a slow consumer cannot stall the trading loop
+func (b *Blackhole) sendReport(reportChan chan<- string, report types.StrategyReport) {
+	b.status.recordReport(report)
 
-	// Only send to channel if state changed
 	if reportChan == nil {
 		return
 	}
@@ -185,5 +273,14 @@ func sendReport(reportChan chan<- string, report types.StrategyReport) {
 		return
 	}
 
-	reportChan <- jsonStr
+	if criticalReports[report.EventType] {
+		reportChan <- jsonStr
+		return
+	}
+	select {
+	case reportChan <- jsonStr:
+	default:
+		dropped := b.status.droppedReports.Add(1)
+		log.Printf("Report channel full, dropped %s report (%d dropped so far)", report.EventType, dropped)
+	}
 }
@@ -0,0 +1,144 @@
+package blackholedex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockRecorder keeps every recorded snapshot in memory
+type mockRecorder struct {
+	snapshots []types.CurrentAssetSnapshot
+}
+
+func (m *mockRecorder) RecordReport(snapshot types.CurrentAssetSnapshot) error {
+	m.snapshots = append(m.snapshots, snapshot)
+	return nil
+}
+
+// newSnapshotBlackhole builds a Blackhole whose wallet holds 2 WAVAX, 50 USDC, 1 AVAX and no positions
+func newSnapshotBlackhole() *Blackhole {
+	ether := big.NewInt(1_000_000_000_000_000_000)
+	token := func(addr string, balance *big.Int) *mockContractClient {
+		c := newMockContractClient(common.HexToAddress(addr))
+		c.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			if method != "balanceOf" {
+				return nil, errors.New("unexpected method " + method)
+			}
+			return []interface{}{balance}, nil
+		}
+		return c
+	}
+
+	b := newTestBlackhole(map[string]ContractClient{
+		wavax:                      token("0x00000000000000000000000000000000000000a1", new(big.Int).Mul(ether, big.NewInt(2))),
+		usdc:                       token("0x00000000000000000000000000000000000000a2", big.NewInt(50_000_000)),
+		black:                      token("0x00000000000000000000000000000000000000a3", big.NewInt(0)),
+		nonfungiblePositionManager: token("0x00000000000000000000000000000000000000a4", big.NewInt(0)),
+		// Price of 1 USDC unit per wei: sqrtPrice = 2^96
+		wavaxUsdcPair: newMockPool(util.Q96, 0),
+	}, &mockTxListener{})
+	b.balances = &mockBalanceReader{balance: ether}
+	return b
+}
+
+func TestRecordSnapshotIfDue(t *testing.T) {
+	b := newSnapshotBlackhole()
+	recorder := &mockRecorder{}
+	b.recorder = recorder
+
+	state := &types.StrategyState{
+		CurrentState:      types.ActiveMonitoring,
+		CumulativeGas:     big.NewInt(21_000),
+		CumulativeRewards: big.NewInt(0),
+	}
+	interval := time.Minute
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := start
+
+	// Simulate five monitoring ticks with the snapshot interval equal to the monitoring interval
+	for i := 1; i <= 5; i++ {
+		last = b.recordSnapshotIfDue(state, interval, last, start.Add(time.Duration(i)*interval))
+	}
+
+	if assert.Len(t, recorder.snapshots, 5) {
+		snapshot := recorder.snapshots[0]
+		assert.Equal(t, types.ActiveMonitoring, snapshot.CurrentState)
+		assert.Equal(t, "50000000", snapshot.AmountUsdc.String())
+		assert.Equal(t, "1000000000000000000", snapshot.AmountAvax.String())
+		// 50 USDC + (2 WAVAX + 1 AVAX) at 1 USDC unit per wei
+		assert.Equal(t, "3000000000050000000", snapshot.TotalValue.String())
+		assert.Equal(t, "21000", snapshot.CumulativeGas.String())
+	}
+	assert.Equal(t, start.Add(5*interval), last)
+
+	// A tick before the interval elapses records nothing
+	last = b.recordSnapshotIfDue(state, time.Hour, last, last.Add(interval))
+	assert.Len(t, recorder.snapshots, 5)
+	assert.Equal(t, start.Add(5*interval), last)
+}
+
+func TestRecordSnapshotIfDueWithoutRecorder(t *testing.T) {
+	b := newSnapshotBlackhole()
+	start := time.Now()
+
+	last := b.recordSnapshotIfDue(&types.StrategyState{CurrentState: types.ActiveMonitoring}, 0, start, start.Add(time.Minute))
+	assert.Equal(t, start, last)
+}
+
+func TestGetPortfolioValue(t *testing.T) {
+	factoryABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraCLFactory.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	blackAddr := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	blackPoolAddr := common.HexToAddress("0x00000000000000000000000000000000000000d3")
+
+	b := newSnapshotBlackhole()
+	blackClient := newMockContractClient(blackAddr)
+	blackClient.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		return []interface{}{big.NewInt(1_000)}, nil
+	}
+	b.registry.Register(black, blackClient)
+
+	// Only a BLACK/USDC pool exists; USDC sorts first, and 4 BLACK units trade for 1 USDC unit
+	factory := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000f2"))
+	factory.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		var pool common.Address
+		if method == "poolByPair" && args[0] == usdcAddr && args[1] == blackAddr {
+			pool = blackPoolAddr
+		}
+		encoded, err := factoryABI.Methods[method].Outputs.Pack(pool)
+		if err != nil {
+			return nil, err
+		}
+		return factoryABI.Unpack(method, encoded)
+	}
+	b.registry.Register(algebraFactory, factory)
+	blackPool := newMockPairPool(new(big.Int).Mul(util.Q96, big.NewInt(2)), 13863, usdcAddr, blackAddr)
+	blackPool.address = blackPoolAddr
+	pairState := blackPool.callFn
+	blackPool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "liquidity" {
+			return []interface{}{big.NewInt(1_000_000)}, nil
+		}
+		return pairState(method, args...)
+	}
+	b.registry.Register("blackUsdcPool", blackPool)
+
+	snapshot, err := b.GetPortfolioValue()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "1000", snapshot.AmountBlack.String())
+	// 50 USDC + (2 WAVAX + 1 AVAX) at 1 USDC unit per wei + 1000 BLACK at 4 per USDC unit
+	assert.Equal(t, "3000000000050000250", snapshot.TotalValue.String())
+	assert.True(t, snapshot.Timestamp.IsZero())
+}
@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"time"
 
+	"github.com/ChoSanghyuk/blackholedex/pkg/metrics"
 	"github.com/ChoSanghyuk/blackholedex/pkg/types"
 	"github.com/ChoSanghyuk/blackholedex/pkg/util"
 	"github.com/ethereum/go-ethereum/common"
@@ -16,35 +17,71 @@ import (
 // maxUSDC: Maximum USDC amount to stake (smallest unit)
 // rangeWidth: Position range width (e.g., 6 = ±3 tick ranges)
 // slippagePct: Slippage tolerance percentage (e.g., 5 = 5%)
+// resume: optional state of an earlier attempt; approvals it recorded are awaited instead of sent again
 // Returns StakingResult with all transaction details and position info
 func (b *Blackhole) Mint(
 	maxWAVAX *big.Int,
 	maxUSDC *big.Int,
 	rangeWidth int,
 	slippagePct int,
+	resume ...*types.OperationState,
 ) (*types.StakingResult, error) {
-	tickSpacing := b.poolType.TickSpacing()
-
-	// T012: Input validation
-	if err := util.ValidateStakingRequest(maxWAVAX, maxUSDC, rangeWidth, slippagePct); err != nil {
+	if err := b.checkNativeReserve(mintGasEstimate); err != nil {
 		return &types.StakingResult{
 			Success:      false,
-			ErrorMessage: fmt.Sprintf("validation failed: %v", err),
+			ErrorMessage: err.Error(),
 		}, err
 	}
 
+	// Held until the mint is confirmed so concurrent mints are checked against each other
+	defer b.lockCapital()()
+
 	// Initialize transaction tracking
 	var transactions []types.TransactionRecord
 
-	// T013: Query pool state
-	// wavaxUsdcPairAddr, _ := b.GetAddress(wavaxUsdcPair)
-	state, err := b.GetAMMState()
+	// T013: Query pool state and tick spacing
+	wavaxUsdcPairAddr, _ := b.registry.GetAddress(wavaxUsdcPair)
+	poolInfo, err := b.GetPoolInfo(wavaxUsdcPairAddr)
 	if err != nil {
 		return &types.StakingResult{
 			Success:      false,
 			ErrorMessage: fmt.Sprintf("failed to query pool state: %v", err),
 		}, fmt.Errorf("failed to query pool state: %w", err)
 	}
+	state := &poolInfo.AMMState
+
+	// Ticks must align to the pool's spacing; the pool type may ask for a coarser
+	// multiple of it (CL1 positions are placed on 200-tick boundaries)
+	tickSpacing := poolInfo.TickSpacing
+	if minSpacing := b.poolType.TickSpacing(); tickSpacing > 0 && minSpacing > tickSpacing && minSpacing%tickSpacing == 0 {
+		tickSpacing = minSpacing
+	}
+
+	// T012: Input validation, once the pool's tick spacing is known
+	if err := util.ValidateStakingRequest(maxWAVAX, maxUSDC, rangeWidth, tickSpacing, slippagePct); err != nil {
+		return &types.StakingResult{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("validation failed: %v", err),
+		}, err
+	}
+
+	// Algebra orders pool tokens by address, so USDC may be token0
+	// Budgets are mapped onto the pool's token0/token1 and results mapped back to WAVAX/USDC
+	usdcIsToken0, err := b.usdcIsToken0(poolInfo)
+	if err != nil {
+		return &types.StakingResult{
+			Success:      false,
+			ErrorMessage: fmt.Sprintf("unexpected pool tokens: %v", err),
+		}, fmt.Errorf("unexpected pool tokens: %w", err)
+	}
+	// inPoolOrder converts between (WAVAX, USDC) and (token0, token1) order, in either direction
+	inPoolOrder := func(x, y *big.Int) (*big.Int, *big.Int) {
+		if usdcIsToken0 {
+			return y, x
+		}
+		return x, y
+	}
+	max0, max1 := inPoolOrder(maxWAVAX, maxUSDC)
 
 	// T014: Calculate tick bounds
 	log.Printf("CalculateTickBounds: %d,rangeWidth: %d, tickSpacing: %d", state.Tick, rangeWidth, tickSpacing)
@@ -62,18 +99,18 @@ func (b *Blackhole) Mint(
 		int(state.Tick),
 		int(tickLower),
 		int(tickUpper),
-		maxWAVAX,
-		maxUSDC,
+		max0,
+		max1,
 	)
 
 	// T033: Compare actual vs desired amounts for capital efficiency
 	// T034: Calculate and log capital utilization percentages
 	utilization0 := new(big.Int).Mul(amount0Desired, big.NewInt(100)) // (amount0Desired / maxWAVAX) * 100. 최대 가능 금액 대비 staking되는 금액의 비율
-	utilization0.Div(utilization0, maxWAVAX)
+	utilization0.Div(utilization0, max0)
 	utilization1 := new(big.Int).Mul(amount1Desired, big.NewInt(100))
-	utilization1.Div(utilization1, maxUSDC)
+	utilization1.Div(utilization1, max1)
 
-	log.Printf("Capital Utilization: WAVAX %d%%, USDC %d%%",
+	log.Printf("Capital Utilization: token0 %d%%, token1 %d%%",
 		utilization0.Int64(), utilization1.Int64())
 
 	// T032: For CL1 pools, automatically adjust range if utilization is low
@@ -82,7 +119,7 @@ func (b *Blackhole) Mint(
 		originalTickLower := tickLower
 		originalTickUpper := tickUpper
 
-		log.Printf("🔄 CL1 Pool: Low capital utilization detected (WAVAX: %d%%, USDC: %d%%). Attempting to optimize range...",
+		log.Printf("🔄 CL1 Pool: Low capital utilization detected (token0: %d%%, token1: %d%%). Attempting to optimize range...",
 			utilization0.Int64(), utilization1.Int64())
 
 		optTickLower, optTickUpper, optAmount0, optAmount1, optErr := util.CalculateOptimalRangeWidthForCL1(
@@ -90,8 +127,8 @@ func (b *Blackhole) Mint(
 			rangeWidth,
 			tickSpacing,
 			state.SqrtPrice,
-			maxWAVAX,
-			maxUSDC,
+			max0,
+			max1,
 			90, // 90% utilization threshold
 			20, // Try up to 20 iterations
 		)
@@ -105,38 +142,59 @@ func (b *Blackhole) Mint(
 
 			// Recalculate utilization
 			utilization0 = new(big.Int).Mul(amount0Desired, big.NewInt(100))
-			utilization0.Div(utilization0, maxWAVAX)
+			utilization0.Div(utilization0, max0)
 			utilization1 = new(big.Int).Mul(amount1Desired, big.NewInt(100))
-			utilization1.Div(utilization1, maxUSDC)
+			utilization1.Div(utilization1, max1)
 
 			log.Printf("✅ Optimized tick range: TickLower: %d → %d, TickUpper: %d → %d",
 				originalTickLower, tickLower, originalTickUpper, tickUpper)
-			log.Printf("✅ Improved Capital Utilization: WAVAX %d%%, USDC %d%%",
+			log.Printf("✅ Improved Capital Utilization: token0 %d%%, token1 %d%%",
 				utilization0.Int64(), utilization1.Int64())
 		} else {
 			log.Printf("⚠️  Failed to optimize range: %v", optErr)
 		}
 	}
 
+	wavaxDesired, usdcDesired := inPoolOrder(amount0Desired, amount1Desired)
+	wavaxUtilization, usdcUtilization := inPoolOrder(utilization0, utilization1)
+
 	// T032: Warn if >10% of either token will be unused (capital efficiency warning)
-	wastedWAVAX := new(big.Int).Sub(maxWAVAX, amount0Desired)
-	wastedUSDC := new(big.Int).Sub(maxUSDC, amount1Desired)
+	wastedWAVAX := new(big.Int).Sub(maxWAVAX, wavaxDesired)
+	wastedUSDC := new(big.Int).Sub(maxUSDC, usdcDesired)
 
-	if utilization0.Cmp(big.NewInt(90)) < 0 { // Less than 90% utilized = >10% wasted
+	if wavaxUtilization.Cmp(big.NewInt(90)) < 0 { // Less than 90% utilized = >10% wasted
 		wastePercent := new(big.Int).Mul(wastedWAVAX, big.NewInt(100))
 		wastePercent.Div(wastePercent, maxWAVAX)
 		log.Printf("⚠️  Capital Efficiency Warning: %d%% of WAVAX (%s wei) will not be staked. Consider adjusting amounts or range width.",
 			wastePercent.Int64(), wastedWAVAX.String())
 	}
-	if utilization1.Cmp(big.NewInt(90)) < 0 { // Less than 90% utilized = >10% wasted
+	if usdcUtilization.Cmp(big.NewInt(90)) < 0 { // Less than 90% utilized = >10% wasted
 		wastePercent := new(big.Int).Mul(wastedUSDC, big.NewInt(100))
 		wastePercent.Div(wastePercent, maxUSDC)
 		log.Printf("⚠️  Capital Efficiency Warning: %d%% of USDC (%s smallest unit) will not be staked. Consider adjusting amounts or range width.",
 			wastePercent.Int64(), wastedUSDC.String())
 	}
 
+	// Reject misaligned bounds before they reach the contract and revert
+	for _, tick := range []int32{tickLower, tickUpper} {
+		if err := util.AssertTickAligned(tick, tickSpacing); err != nil {
+			return &types.StakingResult{
+				Success:      false,
+				ErrorMessage: fmt.Sprintf("invalid tick bounds: %v", err),
+			}, fmt.Errorf("invalid tick bounds: %w", err)
+		}
+	}
+
+	// Refuse to deploy more than the capital cap across all positions
+	if err := b.checkCapitalCap(state.SqrtPrice, usdcIsToken0, wavaxDesired, usdcDesired); err != nil {
+		return &types.StakingResult{
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}, err
+	}
+
 	// T016: Validate balances
-	if err := b.validateBalances(amount0Desired, amount1Desired); err != nil {
+	if err := b.validateBalances(wavaxDesired, usdcDesired); err != nil {
 		return &types.StakingResult{
 			Success:      false,
 			ErrorMessage: fmt.Sprintf("balance validation failed: %v", err),
@@ -167,7 +225,8 @@ func (b *Blackhole) Mint(
 	nftManagerAddr, _ := b.registry.GetAddress(nonfungiblePositionManager)
 
 	// T018: WAVAX approval
-	wavaxApproveTxHash, err := b.ensureApproval(wavaxClient, nftManagerAddr, amount0Desired)
+	op := resumeState(resume)
+	wavaxApproveTxHash, err := b.resumableApproval(op, "ApproveWAVAX", wavaxClient, nftManagerAddr, wavaxDesired)
 	if err != nil {
 		return &types.StakingResult{
 			Success:      false,
@@ -195,12 +254,10 @@ func (b *Blackhole) Mint(
 		}
 
 		// Parse gas price for record
-		gasPrice := new(big.Int)
-		gasPrice.SetString(receipt.EffectiveGasPrice, 0)
+		gasPrice, _ := util.ParseReceiptUint(receipt.EffectiveGasPrice)
 
 		// Parse gas used
-		gasUsed := new(big.Int)
-		gasUsed.SetString(receipt.GasUsed, 0)
+		gasUsed, _ := util.ParseReceiptUint(receipt.GasUsed)
 
 		transactions = append(transactions, types.TransactionRecord{
 			TxHash:    wavaxApproveTxHash,
@@ -213,7 +270,7 @@ func (b *Blackhole) Mint(
 	}
 
 	// T019: USDC approval
-	usdcApproveTxHash, err := b.ensureApproval(usdcClient, nftManagerAddr, amount1Desired)
+	usdcApproveTxHash, err := b.resumableApproval(op, "ApproveUSDC", usdcClient, nftManagerAddr, usdcDesired)
 	if err != nil {
 		return &types.StakingResult{
 			Success:      false,
@@ -241,12 +298,10 @@ func (b *Blackhole) Mint(
 		}
 
 		// Parse gas price for record
-		gasPrice := new(big.Int)
-		gasPrice.SetString(receipt.EffectiveGasPrice, 0)
+		gasPrice, _ := util.ParseReceiptUint(receipt.EffectiveGasPrice)
 
 		// Parse gas used
-		gasUsed := new(big.Int)
-		gasUsed.SetString(receipt.GasUsed, 0)
+		gasUsed, _ := util.ParseReceiptUint(receipt.GasUsed)
 
 		transactions = append(transactions, types.TransactionRecord{
 			TxHash:    usdcApproveTxHash,
@@ -259,13 +314,11 @@ func (b *Blackhole) Mint(
 	}
 
 	// T020: Construct MintParams
-	deadline := big.NewInt(time.Now().Add(20 * time.Minute).Unix())
-	wavaxAddr, _ := b.registry.GetAddress(wavax)
-	usdcAddr, _ := b.registry.GetAddress(usdc)
+	deadline := b.txDeadline(txDeadlineOffset)
 	deployerAddr, _ := b.registry.GetAddress(deployer)
 	mintParams := &types.MintParams{
-		Token0:         wavaxAddr,
-		Token1:         usdcAddr,
+		Token0:         poolInfo.Token0,
+		Token1:         poolInfo.Token1,
 		Deployer:       deployerAddr,
 		TickLower:      big.NewInt(int64(tickLower)),
 		TickUpper:      big.NewInt(int64(tickUpper)),
@@ -287,7 +340,31 @@ func (b *Blackhole) Mint(
 	}
 
 	// T022: Submit mint transaction
-	mintTxHash, err := nftManagerClient.Send(
+	if b.dryRun {
+		// mint returns (tokenId, liquidity, amount0, amount1)
+		outputs, err := b.simulate(nftManagerClient, "mint", mintParams)
+		if err != nil {
+			return &types.StakingResult{
+				Success:      false,
+				ErrorMessage: err.Error(),
+				DryRun:       true,
+			}, err
+		}
+		simulatedWAVAX, simulatedUSDC := inPoolOrder(bigOutput(outputs, 2), bigOutput(outputs, 3))
+		return &types.StakingResult{
+			NFTTokenID:     bigOutput(outputs, 0),
+			ActualAmount0:  simulatedWAVAX,
+			ActualAmount1:  simulatedUSDC,
+			FinalTickLower: tickLower,
+			FinalTickUpper: tickUpper,
+			TotalGasCost:   big.NewInt(0),
+			Success:        true,
+			DryRun:         true,
+		}, nil
+	}
+
+	mintTxHash, err := nftManagerClient.SendCtx(
+		b.rpcContext(),
 		types.Standard,
 		&b.myAddr,
 		b.privateKey,
@@ -320,12 +397,10 @@ func (b *Blackhole) Mint(
 	}
 
 	// Parse gas price for record
-	mintGasPrice := new(big.Int)
-	mintGasPrice.SetString(mintReceipt.EffectiveGasPrice, 0)
+	mintGasPrice, _ := util.ParseReceiptUint(mintReceipt.EffectiveGasPrice)
 
 	// Parse gas used
-	mintGasUsed := new(big.Int)
-	mintGasUsed.SetString(mintReceipt.GasUsed, 0)
+	mintGasUsed, _ := util.ParseReceiptUint(mintReceipt.GasUsed)
 
 	transactions = append(transactions, types.TransactionRecord{
 		TxHash:    mintTxHash,
@@ -341,16 +416,23 @@ func (b *Blackhole) Mint(
 	// Event signature: Transfer(address indexed from, address indexed to, uint256 indexed tokenId)
 	nftTokenID := MintNftTokenId(nftManagerClient, mintReceipt)
 
-	// T026: Construct StakingResult
-	totalGasCost := big.NewInt(0)
-	for _, tx := range transactions {
-		totalGasCost.Add(totalGasCost, tx.GasCost)
+	// Report the amounts actually deposited; fall back to desired amounts if the event is missing
+	actualAmount0, actualAmount1, err := MintDepositedAmounts(nftManagerClient, mintReceipt)
+	if err != nil {
+		log.Printf("Warning: Failed to read deposited amounts from mint receipt, reporting desired amounts: %v", err)
+		actualAmount0, actualAmount1 = amount0Desired, amount1Desired
 	}
+	actualWAVAX, actualUSDC := inPoolOrder(actualAmount0, actualAmount1)
+
+	// T026: Construct StakingResult
+	totalGasCost := types.NewGasTracker(transactions...).Total()
+
+	metrics.RecordTransactions(transactions)
 
 	result := &types.StakingResult{
 		NFTTokenID:     nftTokenID,
-		ActualAmount0:  amount0Desired, // Actual amounts would be in mint receipt
-		ActualAmount1:  amount1Desired,
+		ActualAmount0:  actualWAVAX,
+		ActualAmount1:  actualUSDC,
 		FinalTickLower: tickLower,
 		FinalTickUpper: tickUpper,
 		Transactions:   transactions,
@@ -362,12 +444,12 @@ func (b *Blackhole) Mint(
 	// T028: Transaction logging
 	fmt.Printf("✓ Liquidity staked successfully\n")
 	fmt.Printf("  Position: Tick %d to %d\n", tickLower, tickUpper)
-	fmt.Printf("  WAVAX: %s wei\n", amount0Desired.String())
-	fmt.Printf("  USDC: %s\n", amount1Desired.String())
-	fmt.Printf("  Total Gas Cost: %s wei\n", totalGasCost.String())
+	fmt.Printf("  WAVAX: %s\n", b.formatAmount(wavax, wavaxDesired))
+	fmt.Printf("  USDC: %s\n", b.formatAmount(usdc, usdcDesired))
+	fmt.Printf("  Total Gas Cost: %s\n", formatAVAX(totalGasCost))
 	fmt.Printf("  NFT ID: %s", result.NFTTokenID.String())
 	for _, tx := range transactions {
-		fmt.Printf("  - %s: %s (gas: %s wei)\n", tx.Operation, tx.TxHash.Hex(), tx.GasCost.String())
+		fmt.Printf("  - %s: %s (gas: %s)\n", tx.Operation, tx.TxHash.Hex(), formatAVAX(tx.GasCost))
 	}
 
 	return result, nil
@@ -376,9 +458,11 @@ func (b *Blackhole) Mint(
 // Stake stakes a liquidity position NFT in a GaugeV2 contract to earn additional rewards
 // nftTokenID: ERC721 token ID from previous Mint operation
 // gaugeAddress: GaugeV2 contract address (must match pool)
+// resume: optional state of an earlier attempt; an NFT approval it recorded is awaited instead of sent again
 // Returns StakingResult with transaction tracking and gas costs
 func (b *Blackhole) Stake(
 	nftTokenID *big.Int,
+	resume ...*types.OperationState,
 ) (*types.StakingResult, error) {
 	// T007-T008: Input validation
 	if nftTokenID == nil || nftTokenID.Sign() <= 0 {
@@ -387,6 +471,13 @@ func (b *Blackhole) Stake(
 			ErrorMessage: "validation failed: invalid token ID",
 		}, fmt.Errorf("validation failed: invalid token ID")
 	}
+	if err := b.checkNativeReserve(stakeGasEstimate); err != nil {
+		return &types.StakingResult{
+			NFTTokenID:   nftTokenID,
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}, err
+	}
 
 	// T009: Initialize transaction tracking
 	var transactions []types.TransactionRecord
@@ -402,7 +493,7 @@ func (b *Blackhole) Stake(
 	}
 
 	// Query NFT ownership
-	ownerResult, err := nftManagerClient.Call(&b.myAddr, "ownerOf", nftTokenID)
+	ownerResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "ownerOf", nftTokenID)
 	if err != nil {
 		return &types.StakingResult{
 			NFTTokenID:   nftTokenID,
@@ -417,11 +508,14 @@ func (b *Blackhole) Stake(
 			NFTTokenID:   nftTokenID,
 			Success:      false,
 			ErrorMessage: fmt.Sprintf("NFT not owned by wallet: owned by %s", owner.Hex()),
-		}, fmt.Errorf("NFT not owned by wallet")
+		}, fmt.Errorf("%w: owned by %s", ErrNFTNotOwned, owner.Hex())
 	}
 
 	// T015-T023: NFT Approval Check and Execution
-	approvalResult, err := nftManagerClient.Call(&b.myAddr, "getApproved", nftTokenID)
+	// An approval sent by an earlier attempt is awaited first, so the check below sees it
+	op := resumeState(resume)
+	b.awaitRecorded(op, "ApproveNFT")
+	approvalResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "getApproved", nftTokenID)
 	if err != nil {
 		return &types.StakingResult{
 			NFTTokenID:   nftTokenID,
@@ -434,10 +528,13 @@ func (b *Blackhole) Stake(
 
 	// Only approve if not already approved for this gauge
 	gaugeAddr, _ := b.registry.GetAddress(gauge)
-	if currentApproval != gaugeAddr {
+	if currentApproval != gaugeAddr && b.dryRun {
+		log.Printf("[dry-run] skipping approval of NFT %s for gauge %s", nftTokenID.String(), gaugeAddr.Hex())
+	} else if currentApproval != gaugeAddr {
 		log.Printf("Approving NFT %s for gauge %s", nftTokenID.String(), gaugeAddr.Hex())
 
-		approveTxHash, err := nftManagerClient.Send(
+		approveTxHash, err := nftManagerClient.SendCtx(
+			b.rpcContext(),
 			types.Standard,
 			&b.myAddr,
 			b.privateKey,
@@ -452,6 +549,7 @@ func (b *Blackhole) Stake(
 				ErrorMessage: fmt.Sprintf("failed to approve NFT: %v", err),
 			}, fmt.Errorf("failed to approve NFT: %w", err)
 		}
+		op.Record("ApproveNFT", approveTxHash)
 
 		// Wait for approval confirmation
 		approvalReceipt, err := b.tl.WaitForTransaction(approveTxHash)
@@ -473,10 +571,8 @@ func (b *Blackhole) Stake(
 			}, fmt.Errorf("failed to extract approval gas cost: %w", err)
 		}
 
-		gasPrice := new(big.Int)
-		gasPrice.SetString(approvalReceipt.EffectiveGasPrice, 0)
-		gasUsed := new(big.Int)
-		gasUsed.SetString(approvalReceipt.GasUsed, 0)
+		gasPrice, _ := util.ParseReceiptUint(approvalReceipt.EffectiveGasPrice)
+		gasUsed, _ := util.ParseReceiptUint(approvalReceipt.GasUsed)
 
 		transactions = append(transactions, types.TransactionRecord{
 			TxHash:    approveTxHash,
@@ -494,10 +590,7 @@ func (b *Blackhole) Stake(
 	gaugeClient, err := b.registry.Client(gauge)
 	if err != nil {
 		// Return with partial transaction records if approval was sent
-		totalGasCost := big.NewInt(0)
-		for _, tx := range transactions {
-			totalGasCost.Add(totalGasCost, tx.GasCost)
-		}
+		totalGasCost := types.NewGasTracker(transactions...).Total()
 		return &types.StakingResult{
 			NFTTokenID:   nftTokenID,
 			Transactions: transactions,
@@ -507,10 +600,27 @@ func (b *Blackhole) Stake(
 		}, fmt.Errorf("failed to get gauge client: %w", err)
 	}
 
+	if b.dryRun {
+		_, err := b.simulate(gaugeClient, "deposit", nftTokenID)
+		result := &types.StakingResult{
+			NFTTokenID:    nftTokenID,
+			ActualAmount0: big.NewInt(0),
+			ActualAmount1: big.NewInt(0),
+			TotalGasCost:  big.NewInt(0),
+			Success:       err == nil,
+			DryRun:        true,
+		}
+		if err != nil {
+			result.ErrorMessage = err.Error()
+		}
+		return result, err
+	}
+
 	// Submit deposit transaction
 	log.Printf("Depositing NFT %s into gauge %s", nftTokenID.String(), gaugeAddr.Hex())
 
-	depositTxHash, err := gaugeClient.Send(
+	depositTxHash, err := gaugeClient.SendCtx(
+		b.rpcContext(),
 		types.Standard,
 		&b.myAddr,
 		b.privateKey,
@@ -518,10 +628,7 @@ func (b *Blackhole) Stake(
 		nftTokenID, // Token ID is the "amount" parameter
 	)
 	if err != nil {
-		totalGasCost := big.NewInt(0)
-		for _, tx := range transactions {
-			totalGasCost.Add(totalGasCost, tx.GasCost)
-		}
+		totalGasCost := types.NewGasTracker(transactions...).Total()
 		return &types.StakingResult{
 			NFTTokenID:   nftTokenID,
 			Transactions: transactions,
@@ -534,10 +641,7 @@ func (b *Blackhole) Stake(
 	// Wait for deposit confirmation
 	depositReceipt, err := b.tl.WaitForTransaction(depositTxHash)
 	if err != nil {
-		totalGasCost := big.NewInt(0)
-		for _, tx := range transactions {
-			totalGasCost.Add(totalGasCost, tx.GasCost)
-		}
+		totalGasCost := types.NewGasTracker(transactions...).Total()
 		return &types.StakingResult{
 			NFTTokenID:   nftTokenID,
 			Transactions: transactions,
@@ -550,10 +654,7 @@ func (b *Blackhole) Stake(
 	// Track deposit transaction
 	gasCost, err := util.ExtractGasCost(depositReceipt)
 	if err != nil {
-		totalGasCost := big.NewInt(0)
-		for _, tx := range transactions {
-			totalGasCost.Add(totalGasCost, tx.GasCost)
-		}
+		totalGasCost := types.NewGasTracker(transactions...).Total()
 		return &types.StakingResult{
 			NFTTokenID:   nftTokenID,
 			Transactions: transactions,
@@ -563,10 +664,8 @@ func (b *Blackhole) Stake(
 		}, fmt.Errorf("failed to extract deposit gas cost: %w", err)
 	}
 
-	gasPrice := new(big.Int)
-	gasPrice.SetString(depositReceipt.EffectiveGasPrice, 0)
-	gasUsed := new(big.Int)
-	gasUsed.SetString(depositReceipt.GasUsed, 0)
+	gasPrice, _ := util.ParseReceiptUint(depositReceipt.EffectiveGasPrice)
+	gasUsed, _ := util.ParseReceiptUint(depositReceipt.GasUsed)
 
 	transactions = append(transactions, types.TransactionRecord{
 		TxHash:    depositTxHash,
@@ -578,10 +677,9 @@ func (b *Blackhole) Stake(
 	})
 
 	// T031-T037: Result Construction and Gas Tracking
-	totalGasCost := big.NewInt(0)
-	for _, tx := range transactions {
-		totalGasCost.Add(totalGasCost, tx.GasCost)
-	}
+	totalGasCost := types.NewGasTracker(transactions...).Total()
+
+	metrics.RecordTransactions(transactions)
 
 	result := &types.StakingResult{
 		NFTTokenID:     nftTokenID,
@@ -599,9 +697,9 @@ func (b *Blackhole) Stake(
 	fmt.Printf("✓ NFT staked successfully\n")
 	fmt.Printf("  Token ID: %s\n", nftTokenID.String())
 	fmt.Printf("  Gauge: %s\n", gaugeAddr.Hex())
-	fmt.Printf("  Total Gas Cost: %s wei\n", totalGasCost.String())
+	fmt.Printf("  Total Gas Cost: %s\n", formatAVAX(totalGasCost))
 	for _, tx := range transactions {
-		fmt.Printf("  - %s: %s (gas: %s wei)\n", tx.Operation, tx.TxHash.Hex(), tx.GasCost.String())
+		fmt.Printf("  - %s: %s (gas: %s)\n", tx.Operation, tx.TxHash.Hex(), formatAVAX(tx.GasCost))
 	}
 
 	return result, nil
@@ -614,7 +712,7 @@ func (b *Blackhole) executeUnstake(
 	state *types.StrategyState,
 	reportChan chan<- string,
 ) (*types.UnstakeResult, error) {
-	sendReport(reportChan, types.StrategyReport{
+	b.sendReport(reportChan, types.StrategyReport{
 		Timestamp:  time.Now(),
 		EventType:  "rebalance_start",
 		Message:    fmt.Sprintf("Unstaking NFT %s", nftTokenID.String()),
@@ -628,8 +726,8 @@ func (b *Blackhole) executeUnstake(
 	}
 
 	// Update cumulative gas
-	state.CumulativeGas = new(big.Int).Add(state.CumulativeGas, result.TotalGasCost)
-	sendReport(reportChan, types.StrategyReport{
+	state.RecordGas(result.Transactions)
+	b.sendReport(reportChan, types.StrategyReport{
 		Timestamp:     time.Now(),
 		EventType:     "gas_cost",
 		Message:       "Unstake transaction completed",
@@ -646,10 +744,12 @@ func (b *Blackhole) executeUnstake(
 memo. nonce = unique identifier for a farming program incentive.
 IncentiveKey에 대응되는 nonce 값을 사용해야만 함. 내 경우에는 3만을 사용.
 "incentiveKeys" 함수를 호출하면 내 incentiveId에 대응되는 nonce를 알 수 있음
+resume: optional state of an earlier attempt; a confirmed multicall it recorded is returned without resending
 */
 func (b *Blackhole) Unstake(
 	nftTokenID *big.Int,
 	nonce *big.Int,
+	resume ...*types.OperationState,
 ) (*types.UnstakeResult, error) {
 	// T006: Input validation - NFT token ID
 	if nftTokenID == nil || nftTokenID.Sign() <= 0 {
@@ -659,6 +759,27 @@ func (b *Blackhole) Unstake(
 		}, fmt.Errorf("validation failed: invalid token ID")
 	}
 
+	// A multicall sent by an earlier attempt is awaited instead of sent again
+	op := resumeState(resume)
+	if receipt, ok := b.awaitRecorded(op, "Unstake"); ok {
+		txHash, _ := op.Sent("Unstake")
+		record := receiptRecord(txHash, receipt, "Unstake")
+		return &types.UnstakeResult{
+			NFTTokenID:   nftTokenID,
+			Transactions: []types.TransactionRecord{record},
+			TotalGasCost: types.NewGasTracker(record).Total(),
+			Success:      true,
+		}, nil
+	}
+
+	if err := b.checkNativeReserve(unstakeGasEstimate); err != nil {
+		return &types.UnstakeResult{
+			NFTTokenID:   nftTokenID,
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}, err
+	}
+
 	// Initialize transaction tracking
 	var transactions []types.TransactionRecord
 
@@ -672,7 +793,7 @@ func (b *Blackhole) Unstake(
 		}, fmt.Errorf("failed to get NFT manager client: %w", err)
 	}
 
-	ownerResult, err := nftManagerClient.Call(&b.myAddr, "ownerOf", nftTokenID)
+	ownerResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "ownerOf", nftTokenID)
 	if err != nil {
 		return &types.UnstakeResult{
 			NFTTokenID:   nftTokenID,
@@ -687,7 +808,7 @@ func (b *Blackhole) Unstake(
 			NFTTokenID:   nftTokenID,
 			Success:      false,
 			ErrorMessage: fmt.Sprintf("NFT not owned by wallet: owned by %s", owner.Hex()),
-		}, fmt.Errorf("NFT not owned by wallet")
+		}, fmt.Errorf("%w: owned by %s", ErrNFTNotOwned, owner.Hex())
 	}
 
 	// T009: Verify NFT is currently farmed
@@ -700,7 +821,7 @@ func (b *Blackhole) Unstake(
 		}, fmt.Errorf("failed to get FarmingCenter client: %w", err)
 	}
 
-	depositsResult, err := farmingCenterClient.Call(&b.myAddr, "deposits", nftTokenID)
+	depositsResult, err := farmingCenterClient.CallCtx(b.rpcContext(), &b.myAddr, "deposits", nftTokenID)
 	if err != nil {
 		return &types.UnstakeResult{
 			NFTTokenID:   nftTokenID,
@@ -715,7 +836,7 @@ func (b *Blackhole) Unstake(
 			NFTTokenID:   nftTokenID,
 			Success:      false,
 			ErrorMessage: "NFT is not currently staked in farming",
-		}, fmt.Errorf("NFT is not currently staked")
+		}, fmt.Errorf("NFT %s: %w", nftTokenID, ErrNotStaked)
 	}
 
 	// T010: Build multicall data - encode exitFarming call
@@ -756,7 +877,22 @@ func (b *Blackhole) Unstake(
 	farmingCenterAddr, _ := b.registry.GetAddress(farmingCenter)
 	log.Printf("Unstaking NFT %s from FarmingCenter %s", nftTokenID.String(), farmingCenterAddr.Hex())
 
-	multicallTxHash, err := farmingCenterClient.Send(
+	if b.dryRun {
+		_, err := b.simulate(farmingCenterClient, "multicall", multicallData)
+		result := &types.UnstakeResult{
+			NFTTokenID:   nftTokenID,
+			TotalGasCost: big.NewInt(0),
+			Success:      err == nil,
+			DryRun:       true,
+		}
+		if err != nil {
+			result.ErrorMessage = err.Error()
+		}
+		return result, err
+	}
+
+	multicallTxHash, err := farmingCenterClient.SendCtx(
+		b.rpcContext(),
 		types.Standard,
 		&b.myAddr,
 		b.privateKey,
@@ -770,6 +906,7 @@ func (b *Blackhole) Unstake(
 			ErrorMessage: fmt.Sprintf("failed to submit multicall transaction: %v", err),
 		}, fmt.Errorf("failed to submit multicall transaction: %w", err)
 	}
+	op.Record("Unstake", multicallTxHash)
 
 	// T013: Wait for transaction confirmation and extract gas cost
 	multicallReceipt, err := b.tl.WaitForTransaction(multicallTxHash)
@@ -790,10 +927,8 @@ func (b *Blackhole) Unstake(
 		}, fmt.Errorf("failed to extract gas cost: %w", err)
 	}
 
-	gasPrice := new(big.Int)
-	gasPrice.SetString(multicallReceipt.EffectiveGasPrice, 0)
-	gasUsed := new(big.Int)
-	gasUsed.SetString(multicallReceipt.GasUsed, 0)
+	gasPrice, _ := util.ParseReceiptUint(multicallReceipt.EffectiveGasPrice)
+	gasUsed, _ := util.ParseReceiptUint(multicallReceipt.GasUsed)
 
 	transactions = append(transactions, types.TransactionRecord{
 		TxHash:    multicallTxHash,
@@ -817,10 +952,9 @@ func (b *Blackhole) Unstake(
 	log.Printf("Rewards collected (parsing from receipt not yet implemented)")
 
 	// T015: Construct and return UnstakeResult
-	totalGasCost := big.NewInt(0)
-	for _, tx := range transactions {
-		totalGasCost.Add(totalGasCost, tx.GasCost)
-	}
+	totalGasCost := types.NewGasTracker(transactions...).Total()
+
+	metrics.RecordTransactions(transactions)
 
 	result := &types.UnstakeResult{
 		NFTTokenID:   nftTokenID,
@@ -836,11 +970,11 @@ func (b *Blackhole) Unstake(
 	fmt.Printf("  Token ID: %s\n", nftTokenID.String())
 	fmt.Printf("  FarmingCenter: %s\n", farmingCenterAddr.Hex())
 	if rewards != nil {
-		fmt.Printf("  Rewards: %s / %s\n", rewards.Reward.String(), rewards.BonusReward.String())
+		fmt.Printf("  Rewards: %s / %s\n", b.formatAmount(black, rewards.Reward), b.formatAmount(black, rewards.BonusReward))
 	}
-	fmt.Printf("  Total Gas Cost: %s wei\n", totalGasCost.String())
+	fmt.Printf("  Total Gas Cost: %s\n", formatAVAX(totalGasCost))
 	for _, tx := range transactions {
-		fmt.Printf("  - %s: %s (gas: %s wei)\n", tx.Operation, tx.TxHash.Hex(), tx.GasCost.String())
+		fmt.Printf("  - %s: %s (gas: %s)\n", tx.Operation, tx.TxHash.Hex(), formatAVAX(tx.GasCost))
 	}
 
 	return result, nil
@@ -852,7 +986,7 @@ func (b *Blackhole) executeWithdraw(
 	state *types.StrategyState,
 	reportChan chan<- string,
 ) (*types.WithdrawResult, error) {
-	sendReport(reportChan, types.StrategyReport{
+	b.sendReport(reportChan, types.StrategyReport{
 		Timestamp:  time.Now(),
 		EventType:  "rebalance_start",
 		Message:    fmt.Sprintf("Withdrawing liquidity from NFT %s", nftTokenID.String()),
@@ -860,14 +994,15 @@ func (b *Blackhole) executeWithdraw(
 		NFTTokenID: nftTokenID,
 	})
 
-	result, err := b.Withdraw(nftTokenID)
+	// Rebalancing always mints a fresh position, so the emptied NFT is burned
+	result, err := b.Withdraw(nftTokenID, true)
 	if err != nil {
 		return nil, fmt.Errorf("withdraw failed: %w", err)
 	}
 
 	// Update cumulative gas
-	state.CumulativeGas = new(big.Int).Add(state.CumulativeGas, result.TotalGasCost)
-	sendReport(reportChan, types.StrategyReport{
+	state.RecordGas(result.Transactions)
+	b.sendReport(reportChan, types.StrategyReport{
 		Timestamp:     time.Now(),
 		EventType:     "gas_cost",
 		Message:       "Withdraw transaction completed",
@@ -879,10 +1014,12 @@ func (b *Blackhole) executeWithdraw(
 	return result, nil
 }
 
-// Withdraw removes all liquidity from an NFT position and burns the NFT
+// Withdraw removes all liquidity from an NFT position and optionally burns the NFT
 // nftTokenID: ERC721 token ID from previous Mint operation
+// burnOnFullWithdraw: burn the emptied NFT; keep it to re-enter later via increaseLiquidity
+// opts: ConvertTo swaps the withdrawn tokens into a single token after collecting
 // Returns WithdrawResult with transaction tracking and gas costs
-func (b *Blackhole) Withdraw(nftTokenID *big.Int) (*types.WithdrawResult, error) {
+func (b *Blackhole) Withdraw(nftTokenID *big.Int, burnOnFullWithdraw bool, opts ...CollectOption) (*types.WithdrawResult, error) {
 	// T008: Input validation
 	if nftTokenID == nil || nftTokenID.Sign() <= 0 {
 		return &types.WithdrawResult{
@@ -890,6 +1027,13 @@ func (b *Blackhole) Withdraw(nftTokenID *big.Int) (*types.WithdrawResult, error)
 			ErrorMessage: "validation failed: NFT token ID must be positive",
 		}, fmt.Errorf("validation failed: NFT token ID must be positive")
 	}
+	if err := b.rejectDryRun("Withdraw"); err != nil {
+		return &types.WithdrawResult{
+			NFTTokenID:   nftTokenID,
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}, err
+	}
 
 	// T009: Get nonfungiblePositionManager ContractClient
 	nftManagerClient, err := b.registry.Client(nonfungiblePositionManager)
@@ -902,7 +1046,7 @@ func (b *Blackhole) Withdraw(nftTokenID *big.Int) (*types.WithdrawResult, error)
 	}
 
 	// T010: Verify NFT ownership
-	ownerResult, err := nftManagerClient.Call(&b.myAddr, "ownerOf", nftTokenID)
+	ownerResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "ownerOf", nftTokenID)
 	if err != nil {
 		return &types.WithdrawResult{
 			NFTTokenID:   nftTokenID,
@@ -917,11 +1061,11 @@ func (b *Blackhole) Withdraw(nftTokenID *big.Int) (*types.WithdrawResult, error)
 			NFTTokenID:   nftTokenID,
 			Success:      false,
 			ErrorMessage: fmt.Sprintf("NFT not owned by wallet: owned by %s", owner.Hex()),
-		}, fmt.Errorf("NFT not owned by wallet: owned by %s", owner.Hex())
+		}, fmt.Errorf("%w: owned by %s", ErrNFTNotOwned, owner.Hex())
 	}
 
 	// T011: Query position details to get liquidity amount
-	positionsResult, err := nftManagerClient.Call(&b.myAddr, "positions", nftTokenID)
+	positionsResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "positions", nftTokenID)
 	if err != nil {
 		return &types.WithdrawResult{
 			NFTTokenID:   nftTokenID,
@@ -932,14 +1076,25 @@ func (b *Blackhole) Withdraw(nftTokenID *big.Int) (*types.WithdrawResult, error)
 
 	liquidity := positionsResult[7].(*big.Int) // uint128 liquidity at index 7
 
+	// The result reports WAVAX/USDC, so reject other positions before sending
+	token0 := positionsResult[2].(common.Address)
+	token1 := positionsResult[3].(common.Address)
+	if _, _, err := b.wavaxUSDCAmounts(token0, token1, nil, nil); err != nil {
+		return &types.WithdrawResult{
+			NFTTokenID:   nftTokenID,
+			Success:      false,
+			ErrorMessage: err.Error(),
+		}, err
+	}
+
 	// T012-T016: Build multicall data
-	// The multicall will execute three operations atomically in this order:
+	// The multicall will execute these operations atomically in this order:
 	// 1. decreaseLiquidity: Removes liquidity from the position (tokens become withdrawable)
 	// 2. collect: Actually transfers the tokens and fees to the recipient
-	// 3. burn: Destroys the NFT after all tokens are collected
+	// 3. burn: Destroys the NFT after all tokens are collected (only if burnOnFullWithdraw)
 	// If any operation fails, the entire transaction reverts (atomicity guarantee)
 	var multicallData [][]byte
-	deadline := big.NewInt(time.Now().Add(20 * time.Minute).Unix())
+	deadline := b.txDeadline(txDeadlineOffset)
 
 	// Slippage protection via amount0Min/amount1Min
 	// These minimums protect against price manipulation and sandwich attacks
@@ -988,18 +1143,21 @@ func (b *Blackhole) Withdraw(nftTokenID *big.Int) (*types.WithdrawResult, error)
 	multicallData = append(multicallData, collectData)
 
 	// T016: Encode burn
-	burnData, err := nftManagerABI.Pack("burn", nftTokenID)
-	if err != nil {
-		return &types.WithdrawResult{
-			NFTTokenID:   nftTokenID,
-			Success:      false,
-			ErrorMessage: fmt.Sprintf("failed to encode burn: %v", err),
-		}, fmt.Errorf("failed to encode burn: %w", err)
+	if burnOnFullWithdraw {
+		burnData, err := nftManagerABI.Pack("burn", nftTokenID)
+		if err != nil {
+			return &types.WithdrawResult{
+				NFTTokenID:   nftTokenID,
+				Success:      false,
+				ErrorMessage: fmt.Sprintf("failed to encode burn: %v", err),
+			}, fmt.Errorf("failed to encode burn: %w", err)
+		}
+		multicallData = append(multicallData, burnData)
 	}
-	multicallData = append(multicallData, burnData)
 
 	// T017: Execute multicall transaction
-	txHash, err := nftManagerClient.Send(
+	txHash, err := nftManagerClient.SendCtx(
+		b.rpcContext(),
 		types.Standard,
 		&b.myAddr,
 		b.privateKey,
@@ -1034,10 +1192,8 @@ func (b *Blackhole) Withdraw(nftTokenID *big.Int) (*types.WithdrawResult, error)
 		}, fmt.Errorf("failed to extract gas cost: %w", err)
 	}
 
-	gasPrice := new(big.Int)
-	gasPrice.SetString(receipt.EffectiveGasPrice, 0)
-	gasUsed := new(big.Int)
-	gasUsed.SetString(receipt.GasUsed, 0)
+	gasPrice, _ := util.ParseReceiptUint(receipt.EffectiveGasPrice)
+	gasUsed, _ := util.ParseReceiptUint(receipt.GasUsed)
 
 	// T020: Create TransactionRecord
 	var transactions []types.TransactionRecord
@@ -1050,13 +1206,47 @@ func (b *Blackhole) Withdraw(nftTokenID *big.Int) (*types.WithdrawResult, error)
 		Operation: "Withdraw",
 	})
 
+	metrics.RecordTransactions(transactions)
+
+	// Withdrawn amounts are reported by the Collect event of the multicall
+	amount0, amount1, err := eventAmounts(nftManagerClient, receipt, "Collect")
+	if err != nil {
+		log.Printf("Warning: failed to read withdrawn amounts: %v", err)
+		amount0, amount1 = big.NewInt(0), big.NewInt(0)
+	}
+	// Collect reports the pool's token order
+	amount0, amount1, _ = b.wavaxUSDCAmounts(token0, token1, amount0, amount1)
+
+	totalGasCost := new(big.Int).Set(gasCost)
+	if len(opts) > 0 {
+		converted0, converted1, swapRecords, err := b.convertCollected(amount0, amount1, opts)
+		transactions = append(transactions, swapRecords...)
+		for _, record := range swapRecords {
+			totalGasCost.Add(totalGasCost, record.GasCost)
+		}
+		if err != nil {
+			// The liquidity is already withdrawn; only the conversion failed
+			log.Printf("Withdrew NFT %s but failed to convert the withdrawn tokens: %v", nftTokenID.String(), err)
+			return &types.WithdrawResult{
+				NFTTokenID:   nftTokenID,
+				Amount0:      amount0,
+				Amount1:      amount1,
+				Transactions: transactions,
+				TotalGasCost: totalGasCost,
+				Success:      true,
+				ErrorMessage: fmt.Sprintf("liquidity withdrawn, but failed to convert withdrawn tokens: %v", err),
+			}, fmt.Errorf("%w: %v", ErrConversionFailed, err)
+		}
+		amount0, amount1 = converted0, converted1
+	}
+
 	// T021: Build and return WithdrawResult
 	result := &types.WithdrawResult{
 		NFTTokenID:   nftTokenID,
-		Amount0:      big.NewInt(0), // Will be enhanced in Polish phase to parse from multicall results
-		Amount1:      big.NewInt(0), // Will be enhanced in Polish phase to parse from multicall results
+		Amount0:      amount0,
+		Amount1:      amount1,
 		Transactions: transactions,
-		TotalGasCost: gasCost,
+		TotalGasCost: totalGasCost,
 		Success:      true,
 		ErrorMessage: "",
 	}
@@ -1064,7 +1254,7 @@ func (b *Blackhole) Withdraw(nftTokenID *big.Int) (*types.WithdrawResult, error)
 	// T022: Add success logging
 	fmt.Printf("✓ Liquidity withdrawn successfully\n")
 	fmt.Printf("  NFT ID: %s\n", nftTokenID.String())
-	fmt.Printf("  Gas cost: %s wei\n", gasCost.String())
+	fmt.Printf("  Gas cost: %s wei\n", totalGasCost.String())
 
 	return result, nil
 }
@@ -1090,7 +1280,7 @@ func (b *Blackhole) executeRebalancing(
 		ErrorMessage: "",
 	}
 
-	sendReport(reportChan, types.StrategyReport{
+	b.sendReport(reportChan, types.StrategyReport{
 		Timestamp: time.Now(),
 		EventType: "rebalance_start",
 		Message:   fmt.Sprintf("Starting rebalancing workflow from step: %s", state.CurrentStep.String()),
@@ -1156,14 +1346,15 @@ func (b *Blackhole) executeRebalancing(
 	netPnL := new(big.Int).Sub(state.CumulativeRewards, state.CumulativeGas)
 	netPnL = new(big.Int).Sub(netPnL, state.TotalSwapFees)
 
-	sendReport(reportChan, types.StrategyReport{
-		Timestamp:     time.Now(),
-		EventType:     "profit",
-		Message:       "Rebalancing workflow completed (unstake + withdrawal)",
-		CumulativeGas: state.CumulativeGas,
-		Profit:        state.CumulativeRewards,
-		NetPnL:        netPnL,
-		Phase:         &state.CurrentState,
+	b.sendReport(reportChan, types.StrategyReport{
+		Timestamp:      time.Now(),
+		EventType:      "profit",
+		Message:        "Rebalancing workflow completed (unstake + withdrawal)",
+		CumulativeGas:  state.CumulativeGas,
+		Profit:         state.CumulativeRewards,
+		NetPnL:         netPnL,
+		Phase:          &state.CurrentState,
+		GasByOperation: state.GasByOperation,
 	})
 
 	workflow.Duration = time.Since(workflow.StartTime)
@@ -1175,3 +1366,114 @@ func (b *Blackhole) executeRebalancing(
 
 	return workflow, nil
 }
+
+// CollectFees collects the swap fees accrued by a position without removing liquidity
+// nftTokenID: ERC721 token ID of a position owned by the wallet
+// opts: ConvertTo swaps the collected fees into a single token after collecting
+// Returns the collected WAVAX and USDC amounts (parsed from the Collect event, after any conversion)
+// and the collect transaction hash; use CollectFeesDetailed for the conversion swap records
+func (b *Blackhole) CollectFees(nftTokenID *big.Int, opts ...CollectOption) (*big.Int, *big.Int, common.Hash, error) {
+	result, err := b.CollectFeesDetailed(nftTokenID, opts...)
+	if result == nil {
+		return nil, nil, common.Hash{}, err
+	}
+	return result.Amount0, result.Amount1, result.Transactions[0].TxHash, err
+}
+
+// CollectFeesDetailed is CollectFees returning every transaction it sent
+// The result is nil if the collect transaction was not sent, and otherwise returned also on error
+func (b *Blackhole) CollectFeesDetailed(nftTokenID *big.Int, opts ...CollectOption) (*types.CollectFeesResult, error) {
+	if nftTokenID == nil || nftTokenID.Sign() <= 0 {
+		return nil, fmt.Errorf("validation failed: NFT token ID must be positive")
+	}
+	if err := b.rejectDryRun("CollectFees"); err != nil {
+		return nil, err
+	}
+
+	nftManagerClient, err := b.registry.Client(nonfungiblePositionManager)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get NFT manager client: %w", err)
+	}
+
+	// Verify NFT ownership
+	ownerResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "ownerOf", nftTokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify NFT ownership: %w", err)
+	}
+	owner := ownerResult[0].(common.Address)
+	if owner != b.myAddr {
+		return nil, fmt.Errorf("%w: owned by %s", ErrNFTNotOwned, owner.Hex())
+	}
+
+	// The pool may order USDC before WAVAX
+	positionsResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "positions", nftTokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query position: %w", err)
+	}
+	token0 := positionsResult[2].(common.Address)
+	token1 := positionsResult[3].(common.Address)
+	if _, _, err := b.wavaxUSDCAmounts(token0, token1, nil, nil); err != nil {
+		return nil, err
+	}
+
+	// Collect everything owed to the position
+	maxUint128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+	collectParams := &types.CollectParams{
+		TokenId:    nftTokenID,
+		Recipient:  b.myAddr,
+		Amount0Max: maxUint128,
+		Amount1Max: maxUint128,
+	}
+
+	txHash, err := nftManagerClient.SendCtx(
+		b.rpcContext(),
+		types.Standard,
+		&b.myAddr,
+		b.privateKey,
+		"collect",
+		collectParams,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to submit collect transaction: %w", err)
+	}
+
+	result := &types.CollectFeesResult{
+		NFTTokenID:   nftTokenID,
+		Transactions: []types.TransactionRecord{{TxHash: txHash, Timestamp: time.Now(), Operation: "CollectFees"}},
+	}
+	receipt, err := b.tl.WaitForTransaction(txHash)
+	if err != nil {
+		return result, fmt.Errorf("collect transaction failed: %w", err)
+	}
+
+	amount0, amount1, err := eventAmounts(nftManagerClient, receipt, "Collect")
+	if err != nil {
+		return result, fmt.Errorf("failed to read collected amounts: %w", err)
+	}
+	amount0, amount1, _ = b.wavaxUSDCAmounts(token0, token1, amount0, amount1)
+	result.Amount0, result.Amount1 = amount0, amount1
+
+	gasCost, err := util.ExtractGasCost(receipt)
+	if err != nil {
+		return result, fmt.Errorf("failed to extract gas cost: %w", err)
+	}
+	gasPrice, _ := util.ParseReceiptUint(receipt.EffectiveGasPrice)
+	gasUsed, _ := util.ParseReceiptUint(receipt.GasUsed)
+	record := &result.Transactions[0]
+	record.GasUsed = gasUsed.Uint64()
+	record.GasPrice = gasPrice
+	record.GasCost = gasCost
+	metrics.RecordTransactions(result.Transactions)
+	log.Printf("Collected fees for NFT %s: %s WAVAX wei, %s USDC (tx: %s, gas cost: %s wei)",
+		nftTokenID.String(), amount0.String(), amount1.String(), txHash.Hex(), gasCost.String())
+
+	// convertCollected records its swap in metrics itself
+	converted0, converted1, swapRecords, err := b.convertCollected(amount0, amount1, opts)
+	result.Transactions = append(result.Transactions, swapRecords...)
+	if err != nil {
+		return result, fmt.Errorf("%w: %v", ErrConversionFailed, err)
+	}
+
+	result.Amount0, result.Amount1 = converted0, converted1
+	return result, nil
+}
@@ -0,0 +1,264 @@
+package blackholedex
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithdrawBurnOnFullWithdraw(t *testing.T) {
+	nftManagerABI, err := util.LoadABI("blackholedex-contracts/abi/MultiCallNonfungiblePositionManager.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	burnSelector := nftManagerABI.Methods["burn"].ID
+	self := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	newNFTManager := func() *mockContractClient {
+		nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+		nftManager.abi = nftManagerABI
+		nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			switch method {
+			case "ownerOf":
+				return []interface{}{self}, nil
+			case "positions":
+				return []interface{}{
+					big.NewInt(0), common.Address{}, common.Address{}, common.Address{}, common.Address{},
+					big.NewInt(-400), big.NewInt(400), big.NewInt(1000),
+					big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+				}, nil
+			}
+			return nil, errors.New("unexpected method " + method)
+		}
+		return nftManager
+	}
+
+	// multicallHasBurn reports whether the single multicall sent includes a burn call
+	multicallHasBurn := func(t *testing.T, nftManager *mockContractClient) bool {
+		if !assert.Equal(t, []string{"multicall"}, nftManager.sentMethods()) {
+			return false
+		}
+		for _, data := range nftManager.sent[0].Args[0].([][]byte) {
+			if bytes.HasPrefix(data, burnSelector) {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, burn := range []bool{true, false} {
+		nftManager := newNFTManager()
+		b := newTestBlackhole(map[string]ContractClient{nonfungiblePositionManager: nftManager}, &mockTxListener{})
+
+		result, err := b.Withdraw(big.NewInt(42), burn)
+		assert.NoError(t, err)
+		assert.True(t, result.Success)
+		assert.Equal(t, burn, multicallHasBurn(t, nftManager), "burn=%v", burn)
+		assert.Len(t, nftManager.sent[0].Args[0].([][]byte), map[bool]int{true: 3, false: 2}[burn])
+	}
+}
+
+func TestCollectFees(t *testing.T) {
+	self := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	owners := map[int64]common.Address{42: self, 43: common.HexToAddress("0xbb"), 44: self}
+	// NFT 44 is a position in a pool ordering USDC first
+	positions := map[int64][]interface{}{42: mockPosition(wavaxAddr, usdcAddr), 44: mockPosition(usdcAddr, wavaxAddr)}
+
+	nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+	nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "ownerOf":
+			return []interface{}{owners[args[0].(*big.Int).Int64()]}, nil
+		case "positions":
+			return positions[args[0].(*big.Int).Int64()], nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	nftManager.events = `[{"address":"0x00000000000000000000000000000000000000b1","event":"Collect","index":3,` +
+		`"parameter":{"tokenId":42,"recipient":"0x00000000000000000000000000000000000000aa",` +
+		`"amount0":1234567890123456789,"amount1":4321000}}]`
+
+	b := newTestBlackhole(map[string]ContractClient{
+		nonfungiblePositionManager: nftManager,
+		wavax:                      newMockContractClient(wavaxAddr),
+		usdc:                       newMockContractClient(usdcAddr),
+	}, &mockTxListener{})
+	expectedWAVAX, _ := new(big.Int).SetString("1234567890123456789", 10)
+
+	t.Run("CollectsOwedFees", func(t *testing.T) {
+		amount0, amount1, txHash, err := b.CollectFees(big.NewInt(42))
+		assert.NoError(t, err)
+		assert.NotEqual(t, common.Hash{}, txHash)
+		assert.Equal(t, expectedWAVAX, amount0)
+		assert.Equal(t, big.NewInt(4321000), amount1)
+
+		if assert.Equal(t, []string{"collect"}, nftManager.sentMethods()) {
+			params := nftManager.sent[0].Args[0].(*types.CollectParams)
+			maxUint128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+			assert.Equal(t, maxUint128, params.Amount0Max)
+			assert.Equal(t, maxUint128, params.Amount1Max)
+			assert.Equal(t, self, params.Recipient)
+		}
+	})
+
+	t.Run("RejectsNonOwned", func(t *testing.T) {
+		_, _, _, err := b.CollectFees(big.NewInt(43))
+		assert.ErrorIs(t, err, ErrNFTNotOwned)
+		assert.Len(t, nftManager.sentMethods(), 1)
+	})
+
+	t.Run("USDCIsToken0", func(t *testing.T) {
+		nftManager.events = `[{"event":"Collect","parameter":{"amount0":4321000,"amount1":1234567890123456789}}]`
+		wavaxAmount, usdcAmount, _, err := b.CollectFees(big.NewInt(44))
+		assert.NoError(t, err)
+		assert.Equal(t, expectedWAVAX, wavaxAmount)
+		assert.Equal(t, big.NewInt(4321000), usdcAmount)
+	})
+
+	t.Run("Detailed", func(t *testing.T) {
+		result, err := b.CollectFeesDetailed(big.NewInt(44))
+		assert.NoError(t, err)
+		if assert.NotNil(t, result) && assert.Len(t, result.Transactions, 1) {
+			assert.Equal(t, big.NewInt(44), result.NFTTokenID)
+			assert.Equal(t, "CollectFees", result.Transactions[0].Operation)
+			assert.NotNil(t, result.Transactions[0].GasCost)
+			assert.Equal(t, expectedWAVAX, result.Amount0)
+			assert.Equal(t, big.NewInt(4321000), result.Amount1)
+		}
+	})
+}
+
+func TestMintTokenOrdering(t *testing.T) {
+	poolABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraPoolState.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	wavaxAddr := common.HexToAddress("0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7")
+	usdcAddr := common.HexToAddress("0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E")
+	maxWAVAX := big.NewInt(3_000_000)
+	maxUSDC := big.NewInt(1_000_000)
+
+	// Tick 100 sits off-center in the [-400, 800] range, so the two desired amounts differ
+	sqrtPriceFloat := new(big.Float).Mul(new(big.Float).SetInt(util.Q96), big.NewFloat(math.Pow(1.0001, 50)))
+	sqrtPrice, _ := sqrtPriceFloat.Int(nil)
+
+	for _, usdcFirst := range []bool{false, true} {
+		token0, token1 := wavaxAddr, usdcAddr
+		max0, max1 := maxWAVAX, maxUSDC
+		if usdcFirst {
+			token0, token1 = usdcAddr, wavaxAddr
+			max0, max1 = maxUSDC, maxWAVAX
+		}
+
+		pool := newABIMock(common.HexToAddress("0x00000000000000000000000000000000000000d1"), poolABI, map[string][]interface{}{
+			"safelyGetStateOfAMM": {sqrtPrice, big.NewInt(100), uint16(0), uint8(0), big.NewInt(0), big.NewInt(200), big.NewInt(0)},
+			"token0":              {token0},
+			"token1":              {token1},
+			"fee":                 {uint16(0)},
+			"tickSpacing":         {big.NewInt(200)},
+			"liquidity":           {big.NewInt(0)},
+		})
+		wavaxClient, usdcClient := newMockToken(wavaxAddr, big.NewInt(1_000_000_000)), newMockToken(usdcAddr, big.NewInt(1_000_000_000))
+		nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+		b := newTestBlackhole(map[string]ContractClient{
+			wavaxUsdcPair:              pool,
+			wavax:                      wavaxClient,
+			usdc:                       usdcClient,
+			nonfungiblePositionManager: nftManager,
+		}, &mockTxListener{})
+
+		result, err := b.Mint(maxWAVAX, maxUSDC, 6, 5)
+		if !assert.NoError(t, err, "usdcFirst=%v", usdcFirst) {
+			continue
+		}
+		if !assert.Equal(t, []string{"mint"}, nftManager.sentMethods(), "usdcFirst=%v", usdcFirst) {
+			continue
+		}
+		params := nftManager.sent[0].Args[0].(*types.MintParams)
+
+		expected0, expected1, _ := util.ComputeAmounts(sqrtPrice, 100, -400, 800, max0, max1)
+		assert.NotEqual(t, expected0, expected1)
+		assert.Equal(t, token0, params.Token0, "usdcFirst=%v", usdcFirst)
+		assert.Equal(t, token1, params.Token1, "usdcFirst=%v", usdcFirst)
+		assert.Equal(t, expected0, params.Amount0Desired, "usdcFirst=%v", usdcFirst)
+		assert.Equal(t, expected1, params.Amount1Desired, "usdcFirst=%v", usdcFirst)
+
+		// Approvals and the reported amounts are per token, whatever the pool order
+		expectedWAVAX, expectedUSDC := expected0, expected1
+		if usdcFirst {
+			expectedWAVAX, expectedUSDC = expected1, expected0
+		}
+		assert.Equal(t, expectedWAVAX, wavaxClient.sent[0].Args[1], "usdcFirst=%v", usdcFirst)
+		assert.Equal(t, expectedUSDC, usdcClient.sent[0].Args[1], "usdcFirst=%v", usdcFirst)
+		assert.Equal(t, expectedWAVAX, result.ActualAmount0, "usdcFirst=%v", usdcFirst)
+		assert.Equal(t, expectedUSDC, result.ActualAmount1, "usdcFirst=%v", usdcFirst)
+	}
+}
+
+func TestMintInfiniteApproval(t *testing.T) {
+	poolABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraPoolState.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	sqrtPriceFloat := new(big.Float).Mul(new(big.Float).SetInt(util.Q96), big.NewFloat(math.Pow(1.0001, 50)))
+	sqrtPrice, _ := sqrtPriceFloat.Int(nil)
+
+	// The allowance is whatever was last approved, as on-chain
+	newToken := func(addr common.Address) *mockContractClient {
+		token := newMockToken(addr, big.NewInt(1_000_000_000))
+		erc20 := token.callFn
+		token.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			if method == "allowance" {
+				token.mu.Lock()
+				defer token.mu.Unlock()
+				if n := len(token.sent); n > 0 {
+					return []interface{}{token.sent[n-1].Args[1]}, nil
+				}
+			}
+			return erc20(method, args...)
+		}
+		return token
+	}
+
+	pool := newABIMock(common.HexToAddress("0x00000000000000000000000000000000000000d1"), poolABI, map[string][]interface{}{
+		"safelyGetStateOfAMM": {sqrtPrice, big.NewInt(100), uint16(0), uint8(0), big.NewInt(0), big.NewInt(200), big.NewInt(0)},
+		"token0":              {wavaxAddr},
+		"token1":              {usdcAddr},
+		"fee":                 {uint16(0)},
+		"tickSpacing":         {big.NewInt(200)},
+		"liquidity":           {big.NewInt(0)},
+	})
+	wavaxClient, usdcClient := newToken(wavaxAddr), newToken(usdcAddr)
+	nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+	b := newTestBlackhole(map[string]ContractClient{
+		wavaxUsdcPair:              pool,
+		wavax:                      wavaxClient,
+		usdc:                       usdcClient,
+		nonfungiblePositionManager: nftManager,
+	}, &mockTxListener{})
+	WithInfiniteApproval()(b)
+
+	for i := 0; i < 2; i++ {
+		_, err := b.Mint(big.NewInt(3_000_000), big.NewInt(1_000_000), 6, 5)
+		assert.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{"mint", "mint"}, nftManager.sentMethods())
+	for _, token := range []*mockContractClient{wavaxClient, usdcClient} {
+		if assert.Equal(t, []string{"approve"}, token.sentMethods()) {
+			assert.Equal(t, abi.MaxUint256, token.sent[0].Args[1])
+		}
+	}
+}
@@ -2,13 +2,13 @@ package blackholedex
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"math/big"
 	"time"
 
 	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
 
 	"github.com/ethereum/go-ethereum/common"
 )
@@ -20,9 +20,13 @@ func (b *Blackhole) GetAMMState() (*types.AMMState, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get pool client for %s: %w", wavaxUsdcPair, err)
 	}
+	return readAMMState(b.rpcContext(), poolClient)
+}
 
+// readAMMState reads the AMM state of the pool behind poolClient
+func readAMMState(ctx context.Context, poolClient ContractClient) (*types.AMMState, error) {
 	// Call safelyGetStateOfAMM - this is a read-only operation
-	result, err := poolClient.Call(nil, "safelyGetStateOfAMM")
+	result, err := poolClient.CallCtx(ctx, nil, "safelyGetStateOfAMM")
 	if err != nil {
 		return nil, fmt.Errorf("failed to call safelyGetStateOfAMM: %w", err)
 	}
@@ -47,6 +51,135 @@ func (b *Blackhole) GetAMMState() (*types.AMMState, error) {
 	return state, nil
 }
 
+// AmountsForUSDPosition computes the WAVAX and USDC amounts needed to open a position
+// worth targetUSD within range r at the current pool price
+// r is in the pool's tick space; USDC (6 decimals) may be either token0 or token1
+// Returns wavaxAmount (wei), usdcAmount (smallest unit), or error
+func (b *Blackhole) AmountsForUSDPosition(targetUSD *big.Float, r types.PositionRange) (wavaxAmount, usdcAmount *big.Int, err error) {
+	if targetUSD == nil || targetUSD.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("target USD value must be positive")
+	}
+	if r.TickLower >= r.TickUpper {
+		return nil, nil, fmt.Errorf("tickLower (%d) must be < tickUpper (%d)", r.TickLower, r.TickUpper)
+	}
+
+	poolInfo, usdcFirst, err := b.pairInfo()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get pool state: %w", err)
+	}
+	poolState := &poolInfo.AMMState
+
+	// Token amounts scale linearly with liquidity, so value a reference liquidity
+	// and scale it to the target value
+	refLiquidity := new(big.Int).Exp(big.NewInt(10), big.NewInt(18), nil)
+	ref0, ref1, err := util.CalculateTokenAmountsFromLiquidity(refLiquidity, poolState.SqrtPrice, r.TickLower, r.TickUpper)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to calculate reference amounts: %w", err)
+	}
+
+	// Value in USDC smallest units; price is token1 per token0
+	price := util.SqrtPriceToPrice(poolState.SqrtPrice)
+	var refValue *big.Float
+	if usdcFirst {
+		// amount0 + amount1 / price
+		refValue = new(big.Float).Quo(new(big.Float).SetInt(ref1), price)
+		refValue.Add(refValue, new(big.Float).SetInt(ref0))
+	} else {
+		// amount1 + amount0 * price
+		refValue = new(big.Float).Mul(new(big.Float).SetInt(ref0), price)
+		refValue.Add(refValue, new(big.Float).SetInt(ref1))
+	}
+	if refValue.Sign() <= 0 {
+		return nil, nil, fmt.Errorf("position range has zero value at current price")
+	}
+
+	targetUnits := new(big.Float).Mul(targetUSD, big.NewFloat(1_000_000)) // USDC has 6 decimals
+	liquidityFloat := new(big.Float).Mul(new(big.Float).SetInt(refLiquidity), targetUnits)
+	liquidityFloat.Quo(liquidityFloat, refValue)
+	liquidity, _ := liquidityFloat.Int(nil)
+
+	amount0, amount1, err := util.CalculateTokenAmountsFromLiquidity(liquidity, poolState.SqrtPrice, r.TickLower, r.TickUpper)
+	if err != nil {
+		return nil, nil, err
+	}
+	if usdcFirst {
+		return amount1, amount0, nil
+	}
+	return amount0, amount1, nil
+}
+
+// WaitForPriceInRange polls pool until its price is within [low, high] or ctx is cancelled
+// Prices are sqrtPriceX96 values as reported by safelyGetStateOfAMM (see util.TickToSqrtPriceX96)
+// Read failures are logged and retried on the next poll
+// Returns nil once the price is in range, or ctx.Err()
+func (b *Blackhole) WaitForPriceInRange(ctx context.Context, pool common.Address, low, high *big.Int, poll time.Duration) error {
+	if low == nil || high == nil || low.Cmp(high) > 0 {
+		return fmt.Errorf("invalid price range [%v, %v]", low, high)
+	}
+	if poll <= 0 {
+		return fmt.Errorf("poll interval must be positive, got %s", poll)
+	}
+
+	poolClient, err := b.poolClient(pool)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(poll)
+	defer ticker.Stop()
+
+	for {
+		state, err := readAMMState(ctx, poolClient)
+		if err != nil {
+			log.Printf("Warning: failed to read price of pool %s: %v", pool.Hex(), err)
+		} else if state.SqrtPrice.Cmp(low) >= 0 && state.SqrtPrice.Cmp(high) <= 0 {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WaitForStability polls pool every interval and feeds its price into window.CheckStability
+// Unlike the strategy's stability phase it sends no reports, so it can be used before a manual Mint
+// Read failures are logged and retried on the next poll
+// Returns nil once the window reports the price stable, or ctx.Err()
+func (b *Blackhole) WaitForStability(ctx context.Context, pool common.Address, window *types.StabilityWindow, interval time.Duration) error {
+	if window == nil {
+		return fmt.Errorf("stability window is required")
+	}
+	if interval <= 0 {
+		return fmt.Errorf("poll interval must be positive, got %s", interval)
+	}
+
+	poolClient, err := b.poolClient(pool)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		state, err := readAMMState(ctx, poolClient)
+		if err != nil {
+			log.Printf("Warning: failed to read price of pool %s: %v", pool.Hex(), err)
+		} else if window.CheckStability(state.SqrtPrice) {
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 // validateBalances validates wallet has sufficient token balances
 // Returns error if insufficient balance, nil otherwise
 func (b *Blackhole) validateBalances(requiredWAVAX, requiredUSDC *big.Int) error {
@@ -61,14 +194,14 @@ func (b *Blackhole) validateBalances(requiredWAVAX, requiredUSDC *big.Int) error
 	}
 
 	// Query WAVAX balance
-	wavaxResult, err := wavaxClient.Call(&b.myAddr, "balanceOf", b.myAddr)
+	wavaxResult, err := wavaxClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
 	if err != nil {
 		return fmt.Errorf("failed to get WAVAX balance: %w", err)
 	}
 	wavaxBalance := wavaxResult[0].(*big.Int)
 
 	// Query USDC balance
-	usdcResult, err := usdcClient.Call(&b.myAddr, "balanceOf", b.myAddr)
+	usdcResult, err := usdcClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
 	if err != nil {
 		return fmt.Errorf("failed to get USDC balance: %w", err)
 	}
@@ -76,13 +209,13 @@ func (b *Blackhole) validateBalances(requiredWAVAX, requiredUSDC *big.Int) error
 
 	// Validate WAVAX balance
 	if wavaxBalance.Cmp(requiredWAVAX) < 0 {
-		return fmt.Errorf("insufficient WAVAX balance: have %s, need %s",
+		return fmt.Errorf("%w of WAVAX: have %s, need %s", ErrInsufficientBalance,
 			wavaxBalance.String(), requiredWAVAX.String())
 	}
 
 	// Validate USDC balance
 	if usdcBalance.Cmp(requiredUSDC) < 0 {
-		return fmt.Errorf("insufficient USDC balance: have %s, need %s",
+		return fmt.Errorf("%w of USDC: have %s, need %s", ErrInsufficientBalance,
 			usdcBalance.String(), requiredUSDC.String())
 	}
 
@@ -100,7 +233,7 @@ func (b *Blackhole) TokenOfOwnerByIndex(index *big.Int) (*big.Int, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to get NFT manager client: %w", err)
 	}
-	rtnRaw, err := nftManagerClient.Call(nil, "tokenOfOwnerByIndex", b.myAddr, index)
+	rtnRaw, err := nftManagerClient.CallCtx(b.rpcContext(), nil, "tokenOfOwnerByIndex", b.myAddr, index)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call tokenOfOwnerByIndex: %w", err)
 	}
@@ -117,7 +250,7 @@ func (b *Blackhole) GetUserPositions() ([]*big.Int, error) {
 	}
 
 	// Get the balance of NFTs owned by the user
-	balanceResult, err := nftManagerClient.Call(nil, "balanceOf", b.myAddr)
+	balanceResult, err := nftManagerClient.CallCtx(b.rpcContext(), nil, "balanceOf", b.myAddr)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get NFT balance: %w", err)
 	}
@@ -140,11 +273,37 @@ func (b *Blackhole) GetUserPositions() ([]*big.Int, error) {
 	return tokenIDs, nil
 }
 
+// ListPositions returns the token IDs of the wallet's WAVAX/USDC position NFTs, in ownership order
+// Positions in other pools are skipped (GetUserPositions lists them all); staked positions are
+// held by the FarmingCenter, not the wallet, so they are not listed
+func (b *Blackhole) ListPositions() ([]*big.Int, error) {
+	tokenIDs, err := b.GetUserPositions()
+	if err != nil {
+		return nil, err
+	}
+	wavaxAddr, _ := b.registry.GetAddress(wavax)
+	usdcAddr, _ := b.registry.GetAddress(usdc)
+
+	positions := make([]*big.Int, 0, len(tokenIDs))
+	for _, tokenID := range tokenIDs {
+		position, err := b.GetPositionDetails(tokenID)
+		if err != nil {
+			return nil, err
+		}
+		if (position.Token0 == wavaxAddr && position.Token1 == usdcAddr) ||
+			(position.Token0 == usdcAddr && position.Token1 == wavaxAddr) {
+			positions = append(positions, tokenID)
+		}
+	}
+	return positions, nil
+}
+
 // monitoringLoop continuously monitors pool price and detects out-of-range conditions (T035-T041)
 // Returns true if out-of-range detected, false otherwise, or error
 func (b *Blackhole) monitoringLoop(
 	ctx context.Context,
 	state *types.StrategyState,
+	config *types.StrategyConfig,
 	reportChan chan<- string,
 ) (bool, error) {
 	// T034: Check context cancellation
@@ -172,19 +331,29 @@ func (b *Blackhole) monitoringLoop(
 
 	isOutOfRange := positionRange.IsOutOfRange(poolState.Tick)
 
-	// T039: Send monitoring report
-	// sendReport(b, reportChan, StrategyReport{
-	// 	Timestamp: time.Now(),
-	// 	EventType: "monitoring",
-	// 	Message:   fmt.Sprintf("Price check: tick=%d, range=[%d, %d], out_of_range=%v", poolState.Tick, state.TickLower, state.TickUpper, isOutOfRange),
-	// 	Phase:     &state.CurrentState,
-	// }, false)
+	// T039: Send monitoring report; every price check is only reported at Verbose
+	if config.ReportVerbosity == types.Verbose {
+		now := time.Now()
+		b.sendReport(reportChan, types.StrategyReport{
+			Timestamp:  now,
+			EventType:  "monitoring",
+			Message:    fmt.Sprintf("Price check: tick=%d, range=[%d, %d], out_of_range=%v", poolState.Tick, state.TickLower, state.TickUpper, isOutOfRange),
+			Phase:      &state.CurrentState,
+			NFTTokenID: state.NFTTokenID,
+			PositionDetails: &types.PositionSnapshot{
+				NFTTokenID: state.NFTTokenID,
+				TickLower:  state.TickLower,
+				TickUpper:  state.TickUpper,
+				Timestamp:  now,
+			},
+		})
+	}
 	log.Printf("[monitoring] Price check: tick=%d, range=[%d, %d], out_of_range=%v\n", poolState.Tick, state.TickLower, state.TickUpper, isOutOfRange)
 
 	// T038: Transition to RebalancingRequired if out of range
 	if isOutOfRange {
 		state.CurrentState = types.RebalancingRequired
-		sendReport(reportChan, types.StrategyReport{
+		b.sendReport(reportChan, types.StrategyReport{
 			Timestamp:  time.Now(),
 			EventType:  "out_of_range",
 			Message:    fmt.Sprintf("Position out of range detected: current tick %d outside [%d, %d]", poolState.Tick, state.TickLower, state.TickUpper),
@@ -194,6 +363,20 @@ func (b *Blackhole) monitoringLoop(
 		return true, nil
 	}
 
+	// Warn once as the tick approaches a bound, and again only after it has moved away
+	_, _, nearestPct := positionRange.DistanceToEdge(poolState.Tick)
+	nearEdge := nearestPct < config.NearEdgeThreshold
+	if nearEdge && !state.NearEdge && config.ReportVerbosity != types.Quiet {
+		b.sendReport(reportChan, types.StrategyReport{
+			Timestamp:  time.Now(),
+			EventType:  "near_edge",
+			Message:    fmt.Sprintf("Price nearing range edge: tick %d is %.1f%% of the width from a bound of [%d, %d]", poolState.Tick, nearestPct*100, state.TickLower, state.TickUpper),
+			Phase:      &state.CurrentState,
+			NFTTokenID: state.NFTTokenID,
+		})
+	}
+	state.NearEdge = nearEdge
+
 	return false, nil
 }
 
@@ -210,7 +393,7 @@ func (b *Blackhole) GetPositionDetails(tokenID *big.Int) (*types.Position, error
 	}
 
 	// Call positions(tokenId) function
-	positionResult, err := nftManagerClient.Call(nil, "positions", tokenID)
+	positionResult, err := nftManagerClient.CallCtx(b.rpcContext(), nil, "positions", tokenID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get position details for token ID %s: %w", tokenID.String(), err)
 	}
@@ -237,45 +420,48 @@ func (b *Blackhole) GetPositionDetails(tokenID *big.Int) (*types.Position, error
 	return position, nil
 }
 
+// MintNftTokenId returns the token ID of the NFT minted in mintReceipt, from its Transfer event out of the zero address
+// Returns 0 when the receipt has no such event
 func MintNftTokenId(nftManagerClient ContractClient, mintReceipt *types.TxReceipt) *big.Int {
-	nftTokenID := big.NewInt(0) // Default fallback
-	// Parse receipt to extract events
-	eventsJson, err := nftManagerClient.ParseReceipt(mintReceipt)
+	events, err := nftManagerClient.DecodeLogs(mintReceipt)
 	if err != nil {
 		log.Printf("Warning: Failed to parse mint receipt for token ID: %v", err)
-	} else {
-		// Parse the JSON to find Transfer event
-		var events []map[string]interface{}
-		if err := json.Unmarshal([]byte(eventsJson), &events); err == nil {
-			for _, event := range events {
-				if eventName, ok := event["event"].(string); ok && eventName == "Transfer" {
-					if params, ok := event["parameter"].(map[string]interface{}); ok {
-						// Check if this is a mint (from zero address to recipient)
-						if fromAddr, ok := params["from"].(string); ok {
-							zeroAddr := common.Address{}
-							if fromAddr == "0x0000000000000000000000000000000000000000" || fromAddr == zeroAddr.Hex() {
-								// Extract tokenId from the Transfer event
-								if tokenIdVal, ok := params["tokenId"]; ok {
-									switch v := tokenIdVal.(type) {
-									case *big.Int:
-										nftTokenID = v
-									case float64:
-										nftTokenID = big.NewInt(int64(v))
-									case string:
-										if tokenIdBig, ok := new(big.Int).SetString(v, 10); ok {
-											nftTokenID = tokenIdBig
-										}
-									}
-									log.Printf("Extracted NFT token ID from mint receipt: %s", nftTokenID.String())
-									break
-								}
-							}
-						}
-					}
-				}
-			}
+		return big.NewInt(0) // Default fallback
+	}
+
+	for _, event := range events {
+		if event.Name != "Transfer" {
+			continue
+		}
+		// A mint transfers the NFT from the zero address to the recipient
+		if from, ok := event.Params["from"].(common.Address); !ok || from != (common.Address{}) {
+			continue
+		}
+		if tokenID, ok := event.Params["tokenId"].(*big.Int); ok {
+			log.Printf("Extracted NFT token ID from mint receipt: %s", tokenID.String())
+			return tokenID
 		}
 	}
+	return big.NewInt(0) // Default fallback
+}
 
-	return nftTokenID
+// MintDepositedAmounts extracts the amounts actually deposited by a mint from its IncreaseLiquidity event
+// The position manager rarely consumes exactly the desired amounts, so the receipt is the source of truth
+func MintDepositedAmounts(nftManagerClient ContractClient, mintReceipt *types.TxReceipt) (amount0, amount1 *big.Int, err error) {
+	return eventAmounts(nftManagerClient, mintReceipt, "IncreaseLiquidity")
+}
+
+// eventAmounts returns the amount0/amount1 parameters of the first eventName event emitted by client in receipt
+func eventAmounts(client ContractClient, receipt *types.TxReceipt, eventName string) (amount0, amount1 *big.Int, err error) {
+	event, ok := client.FindEvent(receipt, eventName)
+	if !ok {
+		return nil, nil, fmt.Errorf("%s event not found in receipt", eventName)
+	}
+
+	amount0, ok0 := event.Params["amount0"].(*big.Int)
+	amount1, ok1 := event.Params["amount1"].(*big.Int)
+	if !ok0 || !ok1 {
+		return nil, nil, fmt.Errorf("%s event has invalid amounts: %v, %v", eventName, event.Params["amount0"], event.Params["amount1"])
+	}
+	return amount0, amount1, nil
 }
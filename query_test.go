@@ -0,0 +1,319 @@
+package blackholedex
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/contractclient"
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAmountsForUSDPosition(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	// 1 AVAX ≈ 12.49 USDC
+	sqrtPrice, _ := new(big.Int).SetString("280057970020625981233062", 10)
+	b := newTestBlackhole(map[string]ContractClient{
+		wavaxUsdcPair: newMockPairPool(sqrtPrice, -251068, wavaxAddr, usdcAddr),
+		wavax:         newMockContractClient(wavaxAddr),
+		usdc:          newMockContractClient(usdcAddr),
+	}, &mockTxListener{})
+
+	targetUSD := big.NewFloat(1000)
+	r := types.PositionRange{TickLower: -252000, TickUpper: -250000}
+
+	wavaxAmount, usdcAmount, err := b.AmountsForUSDPosition(targetUSD, r)
+	assert.NoError(t, err)
+	assert.Positive(t, wavaxAmount.Sign())
+	assert.Positive(t, usdcAmount.Sign())
+
+	// value = (usdc + wavax * price) / 10^6
+	price := util.SqrtPriceToPrice(sqrtPrice)
+	value := new(big.Float).Mul(new(big.Float).SetInt(wavaxAmount), price)
+	value.Add(value, new(big.Float).SetInt(usdcAmount))
+	value.Quo(value, big.NewFloat(1_000_000))
+	valueUSD, _ := value.Float64()
+	assert.InDelta(t, 1000.0, valueUSD, 0.01)
+
+	t.Run("USDCIsToken0", func(t *testing.T) {
+		// The same market seen from a pool ordering USDC first: inverted price and mirrored ticks
+		invertedSqrtPrice := new(big.Int).Div(new(big.Int).Mul(util.Q96, util.Q96), sqrtPrice)
+		usdcFirst := newTestBlackhole(map[string]ContractClient{
+			wavaxUsdcPair: newMockPairPool(invertedSqrtPrice, 251068, usdcAddr, wavaxAddr),
+			wavax:         newMockContractClient(wavaxAddr),
+			usdc:          newMockContractClient(usdcAddr),
+		}, &mockTxListener{})
+
+		mirroredWAVAX, mirroredUSDC, err := usdcFirst.AmountsForUSDPosition(targetUSD, types.PositionRange{TickLower: 250000, TickUpper: 252000})
+		if !assert.NoError(t, err) {
+			return
+		}
+		toFloat := func(v *big.Int) float64 {
+			f, _ := new(big.Float).SetInt(v).Float64()
+			return f
+		}
+		assert.InEpsilon(t, toFloat(wavaxAmount), toFloat(mirroredWAVAX), 1e-6)
+		assert.InEpsilon(t, toFloat(usdcAmount), toFloat(mirroredUSDC), 1e-6)
+	})
+
+	t.Run("InvalidRange", func(t *testing.T) {
+		_, _, err := b.AmountsForUSDPosition(targetUSD, types.PositionRange{TickLower: 0, TickUpper: 0})
+		assert.Error(t, err)
+	})
+}
+
+func TestMintDepositedAmounts(t *testing.T) {
+	nftManagerABI, err := util.LoadABI("blackholedex-contracts/abi/MultiCallNonfungiblePositionManager.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	nftManagerAddr := common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	nftManager := contractclient.NewContractClient(nil, nftManagerAddr, nftManagerABI)
+
+	amount0Desired := big.NewInt(1_000_000_000_000_000_000)
+	amount1Desired := big.NewInt(12_490_000)
+	// The pool consumed slightly less than desired
+	amount0Actual, _ := new(big.Int).SetString("999876543210987654", 10)
+	amount1Actual := big.NewInt(12_489_321)
+
+	event := nftManagerABI.Events["IncreaseLiquidity"]
+	data, err := event.Inputs.NonIndexed().Pack(
+		big.NewInt(5_000_000), big.NewInt(4_999_000), amount0Actual, amount1Actual, common.HexToAddress("0xd1"),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+	receipt := &types.TxReceipt{
+		Status: "0x1",
+		Logs: []*ethtypes.Log{
+			// Unrelated token log emitted during the same mint
+			{Address: common.HexToAddress("0xa1"), Topics: []common.Hash{common.HexToHash("0x01")}},
+			{Address: nftManagerAddr, Topics: []common.Hash{event.ID, common.BigToHash(big.NewInt(42))}, Data: data},
+		},
+	}
+
+	amount0, amount1, err := MintDepositedAmounts(nftManager, receipt)
+	assert.NoError(t, err)
+	assert.Equal(t, amount0Actual, amount0)
+	assert.Equal(t, amount1Actual, amount1)
+	assert.NotEqual(t, amount0Desired, amount0)
+	assert.NotEqual(t, amount1Desired, amount1)
+
+	_, _, err = MintDepositedAmounts(nftManager, &types.TxReceipt{Status: "0x1"})
+	assert.ErrorContains(t, err, "IncreaseLiquidity event not found")
+}
+
+func TestWaitForPriceInRange(t *testing.T) {
+	low := big.NewInt(1_000)
+	high := big.NewInt(2_000)
+	prices := []*big.Int{big.NewInt(500), big.NewInt(2_500), big.NewInt(1_500)}
+
+	pool := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000d1"))
+	polls := 0
+	pool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		price := prices[min(polls, len(prices)-1)]
+		polls++
+		return []interface{}{
+			price, big.NewInt(0), uint16(0), uint8(0),
+			big.NewInt(0), big.NewInt(200), big.NewInt(-200),
+		}, nil
+	}
+	b := newTestBlackhole(map[string]ContractClient{"pool": pool}, &mockTxListener{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := b.WaitForPriceInRange(ctx, pool.address, low, high, time.Millisecond)
+	assert.NoError(t, err)
+	// Below and above the range on the first two polls, inside on the third
+	assert.Equal(t, 3, polls)
+}
+
+func TestWaitForPriceInRangeCancelled(t *testing.T) {
+	pool := newMockPool(big.NewInt(500), 0)
+	b := newTestBlackhole(map[string]ContractClient{"pool": pool}, &mockTxListener{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := b.WaitForPriceInRange(ctx, pool.address, big.NewInt(1_000), big.NewInt(2_000), time.Millisecond)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestWaitForStability(t *testing.T) {
+	// A jump resets the window, then the price settles
+	prices := []*big.Int{big.NewInt(1_000), big.NewInt(2_000), big.NewInt(2_001), big.NewInt(2_002)}
+
+	pool := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000d1"))
+	polls := 0
+	pool.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		price := prices[min(polls, len(prices)-1)]
+		polls++
+		return []interface{}{
+			price, big.NewInt(0), uint16(0), uint8(0),
+			big.NewInt(0), big.NewInt(200), big.NewInt(-200),
+		}, nil
+	}
+	b := newTestBlackhole(map[string]ContractClient{"pool": pool}, &mockTxListener{})
+
+	window := &types.StabilityWindow{Threshold: 0.01, RequiredIntervals: 3}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	err := b.WaitForStability(ctx, pool.address, window, time.Millisecond)
+	assert.NoError(t, err)
+	// Reset by the jump on the second poll, then stable for three polls from the third
+	assert.Equal(t, 5, polls)
+
+	t.Run("Cancelled", func(t *testing.T) {
+		volatile := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000d2"))
+		price := int64(1_000)
+		volatile.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			price *= 2
+			return []interface{}{
+				big.NewInt(price), big.NewInt(0), uint16(0), uint8(0),
+				big.NewInt(0), big.NewInt(200), big.NewInt(-200),
+			}, nil
+		}
+		b := newTestBlackhole(map[string]ContractClient{"pool": volatile}, &mockTxListener{})
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		err := b.WaitForStability(ctx, volatile.address, &types.StabilityWindow{Threshold: 0.01, RequiredIntervals: 3}, time.Millisecond)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+func TestMonitoringLoopNearEdge(t *testing.T) {
+	pool := newMockPool(util.Q96, 350)
+	b := newTestBlackhole(map[string]ContractClient{wavaxUsdcPair: pool}, &mockTxListener{})
+	state := &types.StrategyState{NFTTokenID: big.NewInt(42), TickLower: -400, TickUpper: 400}
+	config := types.DefaultStrategyConfig()
+	reports := make(chan string, 10)
+
+	// Tick 350 is 50 ticks (6.25% of the width) below the upper bound; reported once
+	for range 2 {
+		outOfRange, err := b.monitoringLoop(context.Background(), state, config, reports)
+		assert.NoError(t, err)
+		assert.False(t, outOfRange)
+	}
+	if assert.Len(t, reports, 1) {
+		assert.Contains(t, <-reports, `"event_type":"near_edge"`)
+	}
+	assert.True(t, state.NearEdge)
+
+	// Back near the center the warning is re-armed
+	pool.callFn = newMockPool(util.Q96, 0).callFn
+	_, err := b.monitoringLoop(context.Background(), state, config, reports)
+	assert.NoError(t, err)
+	assert.Empty(t, reports)
+	assert.False(t, state.NearEdge)
+}
+
+func TestReportVerbosity(t *testing.T) {
+	tests := []struct {
+		verbosity types.ReportVerbosity
+		reports   int
+	}{
+		{types.Quiet, 1},   // out_of_range
+		{types.Normal, 3},  // + 2 stability_check
+		{types.Verbose, 7}, // + 4 monitoring
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.verbosity.String(), func(t *testing.T) {
+			pool := newMockPool(util.Q96, 0)
+			b := newTestBlackhole(map[string]ContractClient{wavaxUsdcPair: pool}, &mockTxListener{})
+			config := types.DefaultStrategyConfig()
+			config.ReportVerbosity = tt.verbosity
+			state := &types.StrategyState{NFTTokenID: big.NewInt(42), TickLower: -400, TickUpper: 400}
+			reports := make(chan string, 20)
+
+			// Three in-range price checks, then the price leaves the range
+			for range 3 {
+				outOfRange, err := b.monitoringLoop(context.Background(), state, config, reports)
+				assert.NoError(t, err)
+				assert.False(t, outOfRange)
+			}
+			pool.callFn = newMockPool(util.Q96, 600).callFn
+			outOfRange, err := b.monitoringLoop(context.Background(), state, config, reports)
+			assert.NoError(t, err)
+			assert.True(t, outOfRange)
+
+			// Two stability checks short of the required intervals
+			window := &types.StabilityWindow{Threshold: config.StabilityThreshold, RequiredIntervals: config.StabilityIntervals}
+			for range 2 {
+				stable, err := b.stabilityLoop(context.Background(), config, state, window, reports)
+				assert.NoError(t, err)
+				assert.False(t, stable)
+			}
+
+			assert.Len(t, reports, tt.reports)
+			if tt.verbosity == types.Verbose {
+				assert.Contains(t, <-reports, `"position_details":{"nft_token_id":42,"tick_lower":-400,"tick_upper":400`)
+			}
+		})
+	}
+}
+
+func TestListPositions(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	blackAddr := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+
+	// The wallet owns NFTs 101, 102 and 103; pools maps each to its pair
+	newManager := func(pools map[int64][2]common.Address) *mockContractClient {
+		nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+		nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			switch method {
+			case "balanceOf":
+				return []interface{}{big.NewInt(3)}, nil
+			case "tokenOfOwnerByIndex":
+				return []interface{}{new(big.Int).Add(big.NewInt(101), args[1].(*big.Int))}, nil
+			case "positions":
+				pair := pools[args[0].(*big.Int).Int64()]
+				return mockPosition(pair[0], pair[1]), nil
+			}
+			return nil, fmt.Errorf("unexpected method %s", method)
+		}
+		return nftManager
+	}
+	newBlackhole := func(nftManager *mockContractClient) *Blackhole {
+		return newTestBlackhole(map[string]ContractClient{
+			nonfungiblePositionManager: nftManager,
+			wavax:                      newMockContractClient(wavaxAddr),
+			usdc:                       newMockContractClient(usdcAddr),
+		}, &mockTxListener{})
+	}
+
+	t.Run("AllWAVAXUSDC", func(t *testing.T) {
+		b := newBlackhole(newManager(map[int64][2]common.Address{
+			101: {wavaxAddr, usdcAddr},
+			102: {usdcAddr, wavaxAddr},
+			103: {wavaxAddr, usdcAddr},
+		}))
+		positions, err := b.ListPositions()
+		assert.NoError(t, err)
+		assert.Equal(t, []*big.Int{big.NewInt(101), big.NewInt(102), big.NewInt(103)}, positions)
+	})
+
+	t.Run("OtherPoolSkipped", func(t *testing.T) {
+		b := newBlackhole(newManager(map[int64][2]common.Address{
+			101: {wavaxAddr, usdcAddr},
+			102: {blackAddr, wavaxAddr},
+			103: {wavaxAddr, usdcAddr},
+		}))
+		positions, err := b.ListPositions()
+		assert.NoError(t, err)
+		assert.Equal(t, []*big.Int{big.NewInt(101), big.NewInt(103)}, positions)
+
+		all, err := b.GetUserPositions()
+		assert.NoError(t, err)
+		assert.Len(t, all, 3)
+	})
+}
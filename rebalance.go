@@ -0,0 +1,212 @@
+package blackholedex
+
+import (
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/metrics"
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Minimum imbalance worth a swap, as in the strategy's initial entry
+var (
+	minWAVAXSwap = big.NewInt(100_000_000_000_000_000) // 0.1 WAVAX
+	minUSDCSwap  = big.NewInt(1_000_000)               // 1 USDC
+)
+
+// Rebalance moves a staked position to a fresh range around the current tick
+// Steps: unstake → withdraw → swap to a 50/50 split → mint → stake
+// The unstake step follows GetPositionLocation: farmed positions exit farming, gauge deposits are
+// withdrawn from the gauge and positions already in the wallet skip it
+// nftTokenID: position to close
+// rangeWidth: width of the new position (see Mint)
+// slippagePct: slippage tolerance for the swap and the mint
+// Steps already executed on-chain are not undone if a later step fails; the returned
+// RebalanceResult reports the completed steps, the failed step and all transactions
+func (b *Blackhole) Rebalance(nftTokenID *big.Int, rangeWidth, slippagePct int) (*types.RebalanceResult, error) {
+	result := &types.RebalanceResult{
+		OldNFTTokenID: nftTokenID,
+		TotalGasCost:  big.NewInt(0),
+	}
+	addTransactions := func(transactions []types.TransactionRecord) {
+		result.Transactions = append(result.Transactions, transactions...)
+		for _, tx := range transactions {
+			if tx.GasCost != nil {
+				result.TotalGasCost.Add(result.TotalGasCost, tx.GasCost)
+			}
+		}
+	}
+	record := func(step string, transactions []types.TransactionRecord) {
+		result.CompletedSteps = append(result.CompletedSteps, step)
+		addTransactions(transactions)
+	}
+	// Operations return partial transactions alongside their error
+	fail := func(step string, transactions []types.TransactionRecord, err error) (*types.RebalanceResult, error) {
+		addTransactions(transactions)
+		result.FailedStep = step
+		result.ErrorMessage = err.Error()
+		log.Printf("Rebalance of NFT %s failed at %s after %v: %v", nftTokenID, step, result.CompletedSteps, err)
+		return result, fmt.Errorf("rebalance failed at %s: %w", step, err)
+	}
+
+	if nftTokenID == nil || nftTokenID.Sign() <= 0 {
+		return fail("validate", nil, fmt.Errorf("NFT token ID must be positive"))
+	}
+	// Withdraw cannot be simulated, so nothing is simulated either
+	if err := b.rejectDryRun("Rebalance"); err != nil {
+		return fail("validate", nil, err)
+	}
+
+	location, err := b.GetPositionLocation(nftTokenID)
+	if err != nil {
+		return fail("locate", nil, err)
+	}
+	switch location {
+	case types.InFarming:
+		unstakeResult, err := b.Unstake(nftTokenID, b.poolType.PoolNonce())
+		if err != nil {
+			return fail("unstake", unstakeResult.Transactions, err)
+		}
+		record("unstake", unstakeResult.Transactions)
+	case types.InGauge:
+		gaugeRecords, err := b.withdrawFromGauge(nftTokenID)
+		if err != nil {
+			return fail("unstake", gaugeRecords, err)
+		}
+		record("unstake", gaugeRecords)
+	}
+
+	withdrawResult, err := b.Withdraw(nftTokenID, true)
+	if err != nil {
+		return fail("withdraw", withdrawResult.Transactions, err)
+	}
+	record("withdraw", withdrawResult.Transactions)
+
+	swapRecords, err := b.swapToEvenSplit(slippagePct)
+	if err != nil {
+		return fail("swap", swapRecords, err)
+	}
+	record("swap", swapRecords)
+
+	// Mint with everything the wallet holds, as the strategy does
+	wavaxClient, err := b.registry.Client(wavax)
+	if err != nil {
+		return fail("mint", nil, fmt.Errorf("failed to get WAVAX client: %w", err))
+	}
+	usdcClient, err := b.registry.Client(usdc)
+	if err != nil {
+		return fail("mint", nil, fmt.Errorf("failed to get USDC client: %w", err))
+	}
+	wavaxBalance, err := b.tokenBalance(wavaxClient)
+	if err != nil {
+		return fail("mint", nil, err)
+	}
+	usdcBalance, err := b.tokenBalance(usdcClient)
+	if err != nil {
+		return fail("mint", nil, err)
+	}
+
+	mintResult, err := b.Mint(wavaxBalance, usdcBalance, rangeWidth, slippagePct)
+	if err != nil {
+		return fail("mint", mintResult.Transactions, err)
+	}
+	result.NewNFTTokenID = mintResult.NFTTokenID
+	result.TickLower = mintResult.FinalTickLower
+	result.TickUpper = mintResult.FinalTickUpper
+	record("mint", mintResult.Transactions)
+
+	stakeResult, err := b.Stake(mintResult.NFTTokenID)
+	if err != nil {
+		return fail("stake", stakeResult.Transactions, err)
+	}
+	record("stake", stakeResult.Transactions)
+
+	result.Success = true
+	log.Printf("Rebalanced NFT %s into NFT %s (ticks %d to %d, gas cost: %s wei)",
+		nftTokenID, result.NewNFTTokenID, result.TickLower, result.TickUpper, result.TotalGasCost)
+	return result, nil
+}
+
+// swapToEvenSplit swaps part of the wallet's WAVAX or USDC so both hold equal value
+// at the pool price, net of the pool fee (see CalculateRebalanceAmountsWithFee)
+// Imbalances below 0.1 WAVAX or 1 USDC are left as they are
+// Returns the swap transaction, if one was sent
+func (b *Blackhole) swapToEvenSplit(slippagePct int) ([]types.TransactionRecord, error) {
+	wavaxClient, err := b.registry.Client(wavax)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WAVAX client: %w", err)
+	}
+	usdcClient, err := b.registry.Client(usdc)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get USDC client: %w", err)
+	}
+	wavaxBalance, err := b.tokenBalance(wavaxClient)
+	if err != nil {
+		return nil, err
+	}
+	usdcBalance, err := b.tokenBalance(usdcClient)
+	if err != nil {
+		return nil, err
+	}
+
+	poolState, err := b.GetAMMState()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pool state: %w", err)
+	}
+
+	tokenToSwap, swapAmount, err := util.CalculateRebalanceAmountsWithFee(
+		wavaxBalance,
+		usdcBalance,
+		poolState.SqrtPrice,
+		poolState.FeeFraction(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to calculate rebalance: %w", err)
+	}
+	if tokenToSwap == util.NoSwap ||
+		(tokenToSwap == 0 && swapAmount.Cmp(minWAVAXSwap) <= 0) ||
+		(tokenToSwap == 1 && swapAmount.Cmp(minUSDCSwap) <= 0) {
+		return nil, nil
+	}
+
+	wavaxAddr, _ := b.registry.GetAddress(wavax)
+	usdcAddr, _ := b.registry.GetAddress(usdc)
+	fromToken, toToken := wavaxAddr, usdcAddr
+	if tokenToSwap == 1 {
+		fromToken, toToken = usdcAddr, wavaxAddr
+	}
+
+	route, err := b.findRoute(fromToken, toToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find swap route: %w", err)
+	}
+	expectedAmountOut := util.ApplyFee(expectedSwapOut(poolState, tokenToSwap, swapAmount), poolState.FeeFraction())
+
+	swapTxHash, err := b.Swap(&types.SWAPExactTokensForTokensParams{
+		AmountIn:     swapAmount,
+		AmountOutMin: util.CalculateMinAmount(expectedAmountOut, slippagePct),
+		Routes:       []types.Route{route},
+		To:           b.myAddr,
+		Deadline:     b.txDeadline(txDeadlineOffset),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("swap failed: %w", err)
+	}
+	if swapTxHash == (common.Hash{}) {
+		// Dry run: nothing was swapped
+		return nil, nil
+	}
+
+	swapReceipt, err := b.tl.WaitForTransaction(swapTxHash)
+	if err != nil {
+		return nil, fmt.Errorf("swap transaction failed: %w", err)
+	}
+	swapGasCost, _ := util.ExtractGasCost(swapReceipt)
+	records := []types.TransactionRecord{{TxHash: swapTxHash, GasCost: swapGasCost, Timestamp: time.Now(), Operation: "Swap"}}
+	metrics.RecordTransactions(records)
+	return records, nil
+}
@@ -0,0 +1,194 @@
+package blackholedex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// newRebalanceBlackhole wires mocks for a full unstake → withdraw → swap → mint → stake sequence
+// The wallet holds 5 WAVAX and 1 USDC at a raw price of 1, so a WAVAX to USDC swap is needed
+func newRebalanceBlackhole(t *testing.T) (*Blackhole, map[string]*mockContractClient) {
+	load := func(path string) *mockContractClient {
+		contractABI, err := util.LoadABI(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c := newMockContractClient(common.Address{})
+		c.abi = contractABI
+		return c
+	}
+	self := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	wavaxAddr := common.HexToAddress("0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7")
+	usdcAddr := common.HexToAddress("0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E")
+
+	farming := load("blackholedex-contracts/abi/IFarmingCenter.json")
+	farming.address = common.HexToAddress("0x00000000000000000000000000000000000000f1")
+	farming.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "deposits" {
+			return []interface{}{[32]byte{1}}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+
+	nftManager := load("blackholedex-contracts/abi/MultiCallNonfungiblePositionManager.json")
+	nftManager.address = common.HexToAddress("0x00000000000000000000000000000000000000b1")
+	nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "ownerOf":
+			return []interface{}{self}, nil
+		case "getApproved":
+			return []interface{}{common.Address{}}, nil
+		case "positions":
+			return mockPosition(wavaxAddr, usdcAddr), nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	nftManager.events = `[{"event":"Collect","parameter":{"tokenId":42,"amount0":1000,"amount1":2000}},` +
+		`{"event":"Transfer","parameter":{"from":"0x0000000000000000000000000000000000000000","to":"0x00000000000000000000000000000000000000aa","tokenId":77}}]`
+
+	poolABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraPoolState.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pool := newABIMock(common.HexToAddress("0x00000000000000000000000000000000000000d1"), poolABI, map[string][]interface{}{
+		"safelyGetStateOfAMM": {util.Q96, big.NewInt(0), uint16(0), uint8(0), big.NewInt(0), big.NewInt(200), big.NewInt(-200)},
+		"token0":              {wavaxAddr},
+		"token1":              {usdcAddr},
+		"fee":                 {uint16(0)},
+		"tickSpacing":         {big.NewInt(200)},
+		"liquidity":           {big.NewInt(0)},
+	})
+
+	clients := map[string]*mockContractClient{
+		farmingCenter:              farming,
+		nonfungiblePositionManager: nftManager,
+		wavax:                      newMockToken(wavaxAddr, new(big.Int).Mul(big.NewInt(5), big.NewInt(1_000_000_000_000_000_000))),
+		usdc:                       newMockToken(usdcAddr, big.NewInt(1_000_000)),
+		wavaxUsdcPair:              pool,
+		routerv2:                   newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000c2")),
+		gauge:                      newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000e1")),
+	}
+	registered := make(map[string]ContractClient, len(clients))
+	for name, c := range clients {
+		registered[name] = c
+	}
+	return newTestBlackhole(registered, &mockTxListener{}), clients
+}
+
+func TestRebalance(t *testing.T) {
+	b, clients := newRebalanceBlackhole(t)
+
+	result, err := b.Rebalance(big.NewInt(42), 6, 5)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, result.Success)
+	assert.Equal(t, []string{"unstake", "withdraw", "swap", "mint", "stake"}, result.CompletedSteps)
+	assert.Empty(t, result.FailedStep)
+	assert.Equal(t, big.NewInt(42), result.OldNFTTokenID)
+	assert.Equal(t, big.NewInt(77), result.NewNFTTokenID)
+	assert.Equal(t, int32(-600), result.TickLower)
+	assert.Equal(t, int32(600), result.TickUpper)
+
+	assert.Equal(t, []string{"multicall"}, clients[farmingCenter].sentMethods())
+	assert.Equal(t, []string{"multicall", "mint", "approve"}, clients[nonfungiblePositionManager].sentMethods())
+	assert.Equal(t, []string{"swapExactTokensForTokens"}, clients[routerv2].sentMethods())
+	assert.Equal(t, []string{"deposit"}, clients[gauge].sentMethods())
+	assert.Equal(t, big.NewInt(77), clients[gauge].sent[0].Args[0])
+
+	// Every mock receipt costs 21000 gas at 1 Gwei
+	operations := make([]string, len(result.Transactions))
+	for i, tx := range result.Transactions {
+		operations[i] = tx.Operation
+	}
+	assert.Equal(t, []string{"Unstake", "Withdraw", "Swap", "ApproveWAVAX", "ApproveUSDC", "Mint", "ApproveNFT", "DepositNFT"}, operations)
+	expectedGas := new(big.Int).Mul(big.NewInt(int64(len(result.Transactions))), big.NewInt(21_000_000_000_000))
+	assert.Equal(t, expectedGas, result.TotalGasCost)
+}
+
+func TestRebalanceStakeFailure(t *testing.T) {
+	b, clients := newRebalanceBlackhole(t)
+	clients[gauge].sendErr = errors.New("gauge paused")
+
+	result, err := b.Rebalance(big.NewInt(42), 6, 5)
+	assert.ErrorContains(t, err, "rebalance failed at stake")
+	assert.ErrorContains(t, err, "gauge paused")
+	assert.False(t, result.Success)
+	assert.Equal(t, "stake", result.FailedStep)
+	assert.Equal(t, []string{"unstake", "withdraw", "swap", "mint"}, result.CompletedSteps)
+	// The new position exists but is not staked
+	assert.Equal(t, big.NewInt(77), result.NewNFTTokenID)
+	assert.Empty(t, clients[gauge].sentMethods())
+
+	// Transactions up to the failure, including the NFT approval, are still accounted for
+	last := result.Transactions[len(result.Transactions)-1]
+	assert.Equal(t, "ApproveNFT", last.Operation)
+	expectedGas := new(big.Int).Mul(big.NewInt(int64(len(result.Transactions))), big.NewInt(21_000_000_000_000))
+	assert.Equal(t, expectedGas, result.TotalGasCost)
+}
+
+func TestRebalanceUnstakeFailure(t *testing.T) {
+	b, clients := newRebalanceBlackhole(t)
+	clients[farmingCenter].sendErr = errors.New("farming paused")
+
+	result, err := b.Rebalance(big.NewInt(42), 6, 5)
+	assert.ErrorContains(t, err, "farming paused")
+	assert.Equal(t, "unstake", result.FailedStep)
+	assert.Empty(t, result.CompletedSteps)
+	assert.Empty(t, clients[nonfungiblePositionManager].sentMethods())
+	assert.Empty(t, clients[routerv2].sentMethods())
+}
+
+func TestRebalanceUnstakePath(t *testing.T) {
+	t.Run("InWallet", func(t *testing.T) {
+		b, clients := newRebalanceBlackhole(t)
+		clients[farmingCenter].callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			return []interface{}{[32]byte{}}, nil // not farmed
+		}
+
+		result, err := b.Rebalance(big.NewInt(42), 6, 5)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []string{"withdraw", "swap", "mint", "stake"}, result.CompletedSteps)
+		assert.Empty(t, clients[farmingCenter].sentMethods())
+	})
+
+	t.Run("InGauge", func(t *testing.T) {
+		b, clients := newRebalanceBlackhole(t)
+		// The gauge holds the NFT until it is withdrawn
+		inWallet := clients[nonfungiblePositionManager].callFn
+		inGauge := ownerOverride(inWallet, *clients[gauge].ContractAddress())
+		clients[nonfungiblePositionManager].callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			if len(clients[gauge].sentMethods()) == 0 {
+				return inGauge(method, args...)
+			}
+			return inWallet(method, args...)
+		}
+
+		result, err := b.Rebalance(big.NewInt(42), 6, 5)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, []string{"unstake", "withdraw", "swap", "mint", "stake"}, result.CompletedSteps)
+		assert.Empty(t, clients[farmingCenter].sentMethods())
+		assert.Equal(t, []string{"withdraw", "deposit"}, clients[gauge].sentMethods())
+		assert.Equal(t, big.NewInt(42), clients[gauge].sent[0].Args[0])
+		assert.Equal(t, "GaugeWithdraw", result.Transactions[0].Operation)
+	})
+}
+
+// ownerOverride makes ownerOf report owner and leaves every other call to next
+func ownerOverride(next func(string, ...interface{}) ([]interface{}, error), owner common.Address) func(string, ...interface{}) ([]interface{}, error) {
+	return func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "ownerOf" {
+			return []interface{}{owner}, nil
+		}
+		return next(method, args...)
+	}
+}
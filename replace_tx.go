@@ -0,0 +1,114 @@
+package blackholedex
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// ErrTransactionMined is returned when replacing a transaction that is no longer pending
+var ErrTransactionMined = errors.New("transaction already mined")
+
+// SpeedUpTransaction resubmits a stuck pending transaction with the same nonce and gasMultiplier times its gas price
+// For DynamicFee transactions both the tip and the fee cap are bumped
+// Returns the replacement's hash; fails with ErrTransactionMined if the original is already mined
+func (b *Blackhole) SpeedUpTransaction(txHash common.Hash, gasMultiplier float64) (common.Hash, error) {
+	if err := b.rejectDryRun("SpeedUpTransaction"); err != nil {
+		return common.Hash{}, err
+	}
+	orig, err := b.pendingTx(txHash, gasMultiplier)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return b.sendReplacement(orig, bumpFees(orig, gasMultiplier, orig.To(), orig.Value(), orig.Data(), orig.Gas()))
+}
+
+// CancelTransaction replaces a stuck pending transaction with a zero-value transfer to myAddr
+// The transfer reuses the nonce at gasMultiplier times the gas price, so once mined the original can no longer be
+// Returns the replacement's hash; fails with ErrTransactionMined if the original is already mined
+func (b *Blackhole) CancelTransaction(txHash common.Hash, gasMultiplier float64) (common.Hash, error) {
+	if err := b.rejectDryRun("CancelTransaction"); err != nil {
+		return common.Hash{}, err
+	}
+	orig, err := b.pendingTx(txHash, gasMultiplier)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	return b.sendReplacement(orig, bumpFees(orig, gasMultiplier, &b.myAddr, big.NewInt(0), nil, params.TxGas))
+}
+
+// pendingTx fetches txHash and checks it can be replaced: still pending and sent from myAddr
+func (b *Blackhole) pendingTx(txHash common.Hash, gasMultiplier float64) (*ethtypes.Transaction, error) {
+	if gasMultiplier <= 1 {
+		return nil, fmt.Errorf("gas multiplier must be greater than 1, got %v", gasMultiplier)
+	}
+
+	tx, isPending, err := b.txReader.TransactionByHash(b.rpcContext(), txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction %s: %w", txHash.Hex(), err)
+	}
+	if !isPending {
+		return nil, fmt.Errorf("cannot replace %s: %w", txHash.Hex(), ErrTransactionMined)
+	}
+
+	from, err := ethtypes.Sender(ethtypes.LatestSignerForChainID(tx.ChainId()), tx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to recover sender of %s: %w", txHash.Hex(), err)
+	}
+	if from != b.myAddr {
+		return nil, fmt.Errorf("transaction %s was sent by %s, not %s", txHash.Hex(), from.Hex(), b.myAddr.Hex())
+	}
+	return tx, nil
+}
+
+// bumpFees builds a transaction of orig's type and nonce with its gas pricing scaled by gasMultiplier
+func bumpFees(orig *ethtypes.Transaction, gasMultiplier float64, to *common.Address, value *big.Int, data []byte, gas uint64) ethtypes.TxData {
+	if orig.Type() == ethtypes.DynamicFeeTxType {
+		return &ethtypes.DynamicFeeTx{
+			ChainID:   orig.ChainId(),
+			Nonce:     orig.Nonce(),
+			GasTipCap: scaleGas(orig.GasTipCap(), gasMultiplier),
+			GasFeeCap: scaleGas(orig.GasFeeCap(), gasMultiplier),
+			Gas:       gas,
+			To:        to,
+			Value:     value,
+			Data:      data,
+		}
+	}
+	return &ethtypes.LegacyTx{
+		Nonce:    orig.Nonce(),
+		GasPrice: scaleGas(orig.GasPrice(), gasMultiplier),
+		Gas:      gas,
+		To:       to,
+		Value:    value,
+		Data:     data,
+	}
+}
+
+// scaleGas multiplies a gas price by m, rounding up and always returning more than price
+func scaleGas(price *big.Int, m float64) *big.Int {
+	scaled, acc := new(big.Float).Mul(new(big.Float).SetInt(price), big.NewFloat(m)).Int(nil)
+	if acc == big.Below {
+		scaled.Add(scaled, big.NewInt(1))
+	}
+	if scaled.Cmp(price) <= 0 {
+		scaled.Add(price, big.NewInt(1))
+	}
+	return scaled
+}
+
+// sendReplacement signs txData for orig's chain and broadcasts it
+func (b *Blackhole) sendReplacement(orig *ethtypes.Transaction, txData ethtypes.TxData) (common.Hash, error) {
+	signed, err := ethtypes.SignNewTx(b.privateKey, ethtypes.LatestSignerForChainID(orig.ChainId()), txData)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to sign replacement for %s: %w", orig.Hash().Hex(), err)
+	}
+	if err := b.txSender.SendTransaction(b.rpcContext(), signed); err != nil {
+		return common.Hash{}, fmt.Errorf("failed to send replacement for %s: %w", orig.Hash().Hex(), err)
+	}
+	return signed.Hash(), nil
+}
@@ -0,0 +1,149 @@
+package blackholedex
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTxSender records broadcast transactions
+type mockTxSender struct {
+	sent []*ethtypes.Transaction
+}
+
+func (s *mockTxSender) SendTransaction(ctx context.Context, tx *ethtypes.Transaction) error {
+	s.sent = append(s.sent, tx)
+	return nil
+}
+
+func TestSpeedUpTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if !assert.NoError(t, err) {
+		return
+	}
+	chainID := big.NewInt(43114)
+	signer := ethtypes.LatestSignerForChainID(chainID)
+	router := common.HexToAddress("0x00000000000000000000000000000000000000c2")
+
+	legacy := ethtypes.MustSignNewTx(key, signer, &ethtypes.LegacyTx{
+		Nonce: 7, GasPrice: big.NewInt(25_000_000_000), Gas: 300_000, To: &router, Value: big.NewInt(5), Data: []byte{0x01, 0x02},
+	})
+	dynamic := ethtypes.MustSignNewTx(key, signer, &ethtypes.DynamicFeeTx{
+		ChainID: chainID, Nonce: 8, GasTipCap: big.NewInt(2_000_000_000), GasFeeCap: big.NewInt(30_000_000_000),
+		Gas: 300_000, To: &router, Data: []byte{0x03},
+	})
+
+	setup := func(pending bool) (*Blackhole, *mockTxSender) {
+		b := newTestBlackhole(nil, &mockTxListener{})
+		b.privateKey = key
+		b.myAddr = crypto.PubkeyToAddress(key.PublicKey)
+		b.txReader = &mockTxReader{pending: pending, txs: map[common.Hash]*ethtypes.Transaction{
+			legacy.Hash():  legacy,
+			dynamic.Hash(): dynamic,
+		}}
+		sender := &mockTxSender{}
+		b.txSender = sender
+		return b, sender
+	}
+
+	t.Run("Legacy", func(t *testing.T) {
+		b, sender := setup(true)
+		hash, err := b.SpeedUpTransaction(legacy.Hash(), 1.5)
+		if !assert.NoError(t, err) || !assert.Len(t, sender.sent, 1) {
+			return
+		}
+		tx := sender.sent[0]
+		assert.Equal(t, tx.Hash(), hash)
+		assert.Equal(t, uint64(7), tx.Nonce())
+		assert.Equal(t, big.NewInt(37_500_000_000), tx.GasPrice())
+		assert.Equal(t, legacy.To(), tx.To())
+		assert.Equal(t, legacy.Value(), tx.Value())
+		assert.Equal(t, legacy.Data(), tx.Data())
+
+		from, err := ethtypes.Sender(signer, tx)
+		assert.NoError(t, err)
+		assert.Equal(t, b.myAddr, from)
+	})
+
+	t.Run("DynamicFee", func(t *testing.T) {
+		b, sender := setup(true)
+		_, err := b.SpeedUpTransaction(dynamic.Hash(), 1.2)
+		if !assert.NoError(t, err) || !assert.Len(t, sender.sent, 1) {
+			return
+		}
+		tx := sender.sent[0]
+		assert.Equal(t, uint8(ethtypes.DynamicFeeTxType), tx.Type())
+		assert.Equal(t, uint64(8), tx.Nonce())
+		assert.Equal(t, big.NewInt(2_400_000_000), tx.GasTipCap())
+		assert.Equal(t, big.NewInt(36_000_000_000), tx.GasFeeCap())
+	})
+
+	t.Run("AlreadyMined", func(t *testing.T) {
+		b, sender := setup(false)
+		_, err := b.SpeedUpTransaction(legacy.Hash(), 1.5)
+		assert.ErrorIs(t, err, ErrTransactionMined)
+		assert.Empty(t, sender.sent)
+	})
+
+	t.Run("MultiplierTooLow", func(t *testing.T) {
+		b, sender := setup(true)
+		_, err := b.SpeedUpTransaction(legacy.Hash(), 1)
+		assert.Error(t, err)
+		assert.Empty(t, sender.sent)
+	})
+}
+
+func TestCancelTransaction(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if !assert.NoError(t, err) {
+		return
+	}
+	signer := ethtypes.LatestSignerForChainID(big.NewInt(43114))
+	router := common.HexToAddress("0x00000000000000000000000000000000000000c2")
+	orig := ethtypes.MustSignNewTx(key, signer, &ethtypes.LegacyTx{
+		Nonce: 7, GasPrice: big.NewInt(25_000_000_000), Gas: 300_000, To: &router, Value: big.NewInt(5), Data: []byte{0x01},
+	})
+
+	setup := func(pending bool) (*Blackhole, *mockTxSender) {
+		b := newTestBlackhole(nil, &mockTxListener{})
+		b.privateKey = key
+		b.myAddr = crypto.PubkeyToAddress(key.PublicKey)
+		b.txReader = &mockTxReader{pending: pending, txs: map[common.Hash]*ethtypes.Transaction{orig.Hash(): orig}}
+		sender := &mockTxSender{}
+		b.txSender = sender
+		return b, sender
+	}
+
+	t.Run("ZeroValueSelfTransfer", func(t *testing.T) {
+		b, sender := setup(true)
+		hash, err := b.CancelTransaction(orig.Hash(), 1.5)
+		if !assert.NoError(t, err) || !assert.Len(t, sender.sent, 1) {
+			return
+		}
+		tx := sender.sent[0]
+		assert.Equal(t, tx.Hash(), hash)
+		assert.Equal(t, uint64(7), tx.Nonce())
+		assert.Equal(t, 0, tx.Value().Sign())
+		assert.Empty(t, tx.Data())
+		assert.Equal(t, uint64(21_000), tx.Gas())
+		assert.Equal(t, big.NewInt(37_500_000_000), tx.GasPrice())
+
+		from, err := ethtypes.Sender(signer, tx)
+		assert.NoError(t, err)
+		if assert.NotNil(t, tx.To()) {
+			assert.Equal(t, from, *tx.To())
+		}
+	})
+
+	t.Run("AlreadyMined", func(t *testing.T) {
+		b, sender := setup(false)
+		_, err := b.CancelTransaction(orig.Hash(), 1.5)
+		assert.ErrorIs(t, err, ErrTransactionMined)
+		assert.Empty(t, sender.sent)
+	})
+}
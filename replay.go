@@ -0,0 +1,37 @@
+package blackholedex
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrUnknownContract is returned by ReplayTransaction when the transaction's target is not a registered contract
+var ErrUnknownContract = errors.New("unknown contract")
+
+// ReplayTransaction fetches a past transaction and decodes its calldata against the target contract's ABI
+// Returns the method name and typed arguments with the sender and AVAX value, e.g. to see what an earlier bot transaction did
+// Fails with ErrUnknownContract when the transaction was not sent to a registered contract
+func (b *Blackhole) ReplayTransaction(txHash common.Hash) (*types.DecodedTransaction, error) {
+	tx, _, err := b.txReader.TransactionByHash(b.rpcContext(), txHash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch transaction %s: %w", txHash.Hex(), err)
+	}
+	if tx.To() == nil {
+		return nil, fmt.Errorf("transaction %s is a contract creation: %w", txHash.Hex(), ErrUnknownContract)
+	}
+
+	client, err := b.registry.ClientByAddress(tx.To().Hex())
+	if err != nil {
+		return nil, fmt.Errorf("transaction %s targets %s: %w", txHash.Hex(), tx.To().Hex(), ErrUnknownContract)
+	}
+
+	decoded, err := client.DecodeTransaction(tx.Data())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode transaction %s: %w", txHash.Hex(), err)
+	}
+	decoded.SetTransaction(tx)
+	return decoded, nil
+}
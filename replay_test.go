@@ -0,0 +1,96 @@
+package blackholedex
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/contractclient"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	ethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+)
+
+// mockTxReader serves transactions by hash; unknown hashes are not found
+type mockTxReader struct {
+	txs     map[common.Hash]*ethtypes.Transaction
+	pending bool
+}
+
+func (r *mockTxReader) TransactionByHash(ctx context.Context, hash common.Hash) (*ethtypes.Transaction, bool, error) {
+	tx, ok := r.txs[hash]
+	if !ok {
+		return nil, false, ethereum.NotFound
+	}
+	return tx, r.pending, nil
+}
+
+func TestReplayTransaction(t *testing.T) {
+	routerABI, err := util.LoadABI("blackholedex-contracts/abi/RouterV2.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	routerAddr := common.HexToAddress("0x04E1dee021Cd12bBa022A72806441B43d8212Fec")
+	otherAddr := common.HexToAddress("0x00000000000000000000000000000000000000ee")
+
+	// Calldata of the recorded swap 0x1600e68bfd607a5e8452f7533b162eeb4afd4f0435f31639999aa46fbaef79b1
+	swapHash := common.HexToHash("0x1600e68bfd607a5e8452f7533b162eeb4afd4f0435f31639999aa46fbaef79b1")
+	swapData := common.FromHex("6ba16543000000000000000000000000000000000000000000000038b4034b62cec2f5a10000000000000000000000000000000000000000000000000000000000000080000000000000000000000000b4dd4fb3d4bced984cce972991fb100488b59223000000000000000000000000000000000000000000000000000000006927fa81000000000000000000000000000000000000000000000000000000000000000100000000000000000000000014e4a5bed2e5e688ee1a5ca3a4914250d1abd573000000000000000000000000b31f66aa3c1e785363f0875a1b74e27b85fd66c7000000000000000000000000cd94a87696fac69edae3a70fe5725307ae1c43f600000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000000b4dd4fb3d4bced984cce972991fb100488b59223")
+	unknownHash := common.HexToHash("0x02")
+
+	b := newTestBlackhole(map[string]ContractClient{
+		routerv2: contractclient.NewContractClient(nil, routerAddr, routerABI),
+	}, &mockTxListener{})
+	key, _ := crypto.GenerateKey()
+	value := big.NewInt(1_500_000_000_000_000_000)
+	swapTx, err := ethtypes.SignNewTx(key, ethtypes.LatestSignerForChainID(big.NewInt(43114)),
+		&ethtypes.DynamicFeeTx{ChainID: big.NewInt(43114), To: &routerAddr, Value: value, Data: swapData})
+	if !assert.NoError(t, err) {
+		return
+	}
+	b.txReader = &mockTxReader{txs: map[common.Hash]*ethtypes.Transaction{
+		swapHash:    swapTx,
+		unknownHash: ethtypes.NewTx(&ethtypes.LegacyTx{To: &otherAddr, Data: swapData}),
+	}}
+
+	t.Run("RecordedSwap", func(t *testing.T) {
+		decoded, err := b.ReplayTransaction(swapHash)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, routerAddr, decoded.ContractAddress)
+		assert.Equal(t, "swapExactETHForTokens", decoded.MethodName)
+		params := decoded.Params()
+		assert.Equal(t, "1045988962367239812513", params["amountOutMin"])
+		assert.Equal(t, "1764227713", params["deadline"])
+		assert.Equal(t, "0xb4dd4fb3D4bCED984cce972991fB100488b59223", params["to"])
+
+		assert.Equal(t, routerAddr, decoded.To)
+		assert.Equal(t, crypto.PubkeyToAddress(key.PublicKey), decoded.From)
+		assert.Equal(t, value, decoded.Value)
+	})
+
+	t.Run("RawCalldata", func(t *testing.T) {
+		client, _ := b.registry.Client(routerv2)
+		decoded, err := client.DecodeTransaction(swapData)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, common.Address{}, decoded.To)
+		assert.Equal(t, common.Address{}, decoded.From)
+		assert.Nil(t, decoded.Value)
+	})
+
+	t.Run("UnknownContract", func(t *testing.T) {
+		_, err := b.ReplayTransaction(unknownHash)
+		assert.ErrorIs(t, err, ErrUnknownContract)
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		_, err := b.ReplayTransaction(common.HexToHash("0x03"))
+		assert.ErrorIs(t, err, ethereum.NotFound)
+	})
+}
@@ -0,0 +1,58 @@
+package blackholedex
+
+import (
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// resumeState returns the optional resume token passed to an operation, or nil
+func resumeState(resume []*types.OperationState) *types.OperationState {
+	if len(resume) > 0 {
+		return resume[0]
+	}
+	return nil
+}
+
+// awaitRecorded waits for the transaction op recorded for step, so the step's on-chain check sees it
+// Reports whether it was confirmed; a failed or unknown transaction is left to the check to redo
+func (b *Blackhole) awaitRecorded(op *types.OperationState, step string) (*types.TxReceipt, bool) {
+	txHash, ok := op.Sent(step)
+	if !ok {
+		return nil, false
+	}
+	receipt, err := b.tl.WaitForTransaction(txHash)
+	if err != nil {
+		log.Printf("Resuming %s: earlier %s transaction %s not confirmed: %v", op.ID, step, txHash.Hex(), err)
+		return nil, false
+	}
+	return receipt, true
+}
+
+// resumableApproval approves like approveOrSimulate, first awaiting an approval op already sent for step
+// The allowance is checked on-chain afterwards, so a confirmed earlier approval is not sent again
+func (b *Blackhole) resumableApproval(op *types.OperationState, step string, tokenClient ContractClient, spender common.Address, amount *big.Int) (common.Hash, error) {
+	b.awaitRecorded(op, step)
+	txHash, err := b.approveOrSimulate(tokenClient, spender, amount)
+	if err == nil && txHash != (common.Hash{}) {
+		op.Record(step, txHash)
+	}
+	return txHash, err
+}
+
+// receiptRecord builds the TransactionRecord of a confirmed transaction; gas fields are zero if the receipt lacks them
+func receiptRecord(txHash common.Hash, receipt *types.TxReceipt, operation string) types.TransactionRecord {
+	record := types.TransactionRecord{TxHash: txHash, Timestamp: time.Now(), Operation: operation}
+	if gasCost, err := util.ExtractGasCost(receipt); err == nil {
+		gasPrice, _ := util.ParseReceiptUint(receipt.EffectiveGasPrice)
+		gasUsed, _ := util.ParseReceiptUint(receipt.GasUsed)
+		record.GasUsed = gasUsed.Uint64()
+		record.GasPrice = gasPrice
+		record.GasCost = gasCost
+	}
+	return record
+}
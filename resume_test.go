@@ -0,0 +1,109 @@
+package blackholedex
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// flakyTxListener fails the first wait for each hash in failOnce and confirms every other wait
+type flakyTxListener struct {
+	mu        sync.Mutex
+	failOnce  map[common.Hash]bool
+	confirmed map[common.Hash]bool
+	waited    []common.Hash
+}
+
+func (l *flakyTxListener) WaitForTransaction(txHash common.Hash) (*types.TxReceipt, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waited = append(l.waited, txHash)
+	if l.failOnce[txHash] {
+		delete(l.failOnce, txHash)
+		return nil, errors.New("timeout waiting for transaction")
+	}
+	l.confirmed[txHash] = true
+	return mockReceipt(txHash), nil
+}
+
+func (l *flakyTxListener) isConfirmed(txHash common.Hash) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.confirmed[txHash]
+}
+
+func TestMintResumeSkipsConfirmedApproval(t *testing.T) {
+	poolABI, err := util.LoadABI("blackholedex-contracts/abi/IAlgebraPoolState.json")
+	if !assert.NoError(t, err) {
+		return
+	}
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	sqrtPriceFloat := new(big.Float).Mul(new(big.Float).SetInt(util.Q96), big.NewFloat(math.Pow(1.0001, 50)))
+	sqrtPrice, _ := sqrtPriceFloat.Int(nil)
+
+	approvalHash := common.BigToHash(big.NewInt(1))
+	tl := &flakyTxListener{
+		failOnce:  map[common.Hash]bool{approvalHash: true},
+		confirmed: map[common.Hash]bool{},
+	}
+
+	// The allowance reflects the last approval only once it is confirmed
+	newToken := func(addr common.Address) *mockContractClient {
+		token := newMockToken(addr, big.NewInt(1_000_000_000))
+		erc20 := token.callFn
+		token.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			if method == "allowance" {
+				token.mu.Lock()
+				defer token.mu.Unlock()
+				if n := len(token.sent); n > 0 && tl.isConfirmed(common.BigToHash(big.NewInt(int64(n)))) {
+					return []interface{}{token.sent[n-1].Args[1]}, nil
+				}
+			}
+			return erc20(method, args...)
+		}
+		return token
+	}
+
+	pool := newABIMock(common.HexToAddress("0x00000000000000000000000000000000000000d1"), poolABI, map[string][]interface{}{
+		"safelyGetStateOfAMM": {sqrtPrice, big.NewInt(100), uint16(0), uint8(0), big.NewInt(0), big.NewInt(200), big.NewInt(0)},
+		"token0":              {wavaxAddr},
+		"token1":              {usdcAddr},
+		"fee":                 {uint16(0)},
+		"tickSpacing":         {big.NewInt(200)},
+		"liquidity":           {big.NewInt(0)},
+	})
+	wavaxClient, usdcClient := newToken(wavaxAddr), newToken(usdcAddr)
+	nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+	b := newTestBlackhole(map[string]ContractClient{
+		wavaxUsdcPair:              pool,
+		wavax:                      wavaxClient,
+		usdc:                       usdcClient,
+		nonfungiblePositionManager: nftManager,
+	}, tl)
+
+	op := types.NewOperationState("mint-1")
+	_, err = b.Mint(big.NewInt(3_000_000), big.NewInt(1_000_000), 6, 5, op)
+	assert.Error(t, err)
+	recorded, ok := op.Sent("ApproveWAVAX")
+	assert.True(t, ok)
+	assert.Equal(t, approvalHash, recorded)
+	assert.Empty(t, nftManager.sentMethods())
+
+	_, err = b.Mint(big.NewInt(3_000_000), big.NewInt(1_000_000), 6, 5, op)
+	assert.NoError(t, err)
+
+	// The resumed run waited on the recorded approval instead of sending another
+	assert.Equal(t, []string{"approve"}, wavaxClient.sentMethods())
+	assert.Equal(t, []string{"approve"}, usdcClient.sentMethods())
+	assert.Equal(t, []string{"mint"}, nftManager.sentMethods())
+	_, ok = op.Sent("ApproveUSDC")
+	assert.True(t, ok)
+}
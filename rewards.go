@@ -0,0 +1,114 @@
+package blackholedex
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetPendingRewards returns the farming rewards accrued by a staked NFT without sending a transaction
+// The incentive is looked up from the NFT's deposit, and FarmingCenter.collectRewards is run as an eth_call
+// Fails with ErrNotStaked when the NFT is not in farming
+func (b *Blackhole) GetPendingRewards(nftTokenID *big.Int) (*types.RewardAmounts, error) {
+	if nftTokenID == nil || nftTokenID.Sign() <= 0 {
+		return nil, fmt.Errorf("validation failed: invalid token ID")
+	}
+
+	farmingCenterClient, err := b.registry.Client(farmingCenter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get FarmingCenter client: %w", err)
+	}
+
+	depositsResult, err := farmingCenterClient.CallCtx(b.rpcContext(), &b.myAddr, "deposits", nftTokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check farming status: %w", err)
+	}
+	incentiveID := depositsResult[0].([32]byte)
+	if incentiveID == [32]byte{} {
+		return nil, fmt.Errorf("NFT %s: %w", nftTokenID, ErrNotStaked)
+	}
+
+	keyResult, err := farmingCenterClient.CallCtx(b.rpcContext(), &b.myAddr, "incentiveKeys", incentiveID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get incentive key: %w", err)
+	}
+	incentiveKey := types.IncentiveKey{
+		RewardToken:      keyResult[0].(common.Address),
+		BonusRewardToken: keyResult[1].(common.Address),
+		Pool:             keyResult[2].(common.Address),
+		Nonce:            keyResult[3].(*big.Int),
+	}
+
+	// collectRewards moves the accrued rewards to the claimable balance; as a call it only reports them
+	rewardsResult, err := farmingCenterClient.CallCtx(b.rpcContext(), &b.myAddr, "collectRewards", incentiveKey, nftTokenID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read pending rewards: %w", err)
+	}
+
+	return &types.RewardAmounts{
+		Reward:           rewardsResult[0].(*big.Int),
+		BonusReward:      rewardsResult[1].(*big.Int),
+		RewardToken:      incentiveKey.RewardToken,
+		BonusRewardToken: incentiveKey.BonusRewardToken,
+	}, nil
+}
+
+// HarvestGauge claims the BLACK emissions the gauge has accrued for the wallet while nftTokenID stays staked
+// The NFT must be deposited in the gauge. Nothing is sent when earned() reports no rewards
+// Returns the harvested amount from the gauge's Harvest event and the claim transaction hash
+func (b *Blackhole) HarvestGauge(nftTokenID *big.Int) (*big.Int, common.Hash, error) {
+	if nftTokenID == nil || nftTokenID.Sign() <= 0 {
+		return nil, common.Hash{}, fmt.Errorf("validation failed: invalid token ID")
+	}
+	if err := b.rejectDryRun("HarvestGauge"); err != nil {
+		return nil, common.Hash{}, err
+	}
+
+	nftManagerClient, err := b.registry.Client(nonfungiblePositionManager)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("failed to get NFT manager client: %w", err)
+	}
+	gaugeClient, err := b.registry.Client(gauge)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("failed to get gauge client: %w", err)
+	}
+
+	// A deposited NFT is held by the gauge
+	gaugeAddr := *gaugeClient.ContractAddress()
+	ownerResult, err := nftManagerClient.CallCtx(b.rpcContext(), &b.myAddr, "ownerOf", nftTokenID)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("failed to verify NFT ownership: %w", err)
+	}
+	if owner := ownerResult[0].(common.Address); owner != gaugeAddr {
+		return nil, common.Hash{}, fmt.Errorf("NFT %s is held by %s, not gauge %s: %w", nftTokenID, owner.Hex(), gaugeAddr.Hex(), ErrNotStaked)
+	}
+
+	earnedResult, err := gaugeClient.CallCtx(b.rpcContext(), &b.myAddr, "earned", b.myAddr)
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("failed to read earned rewards: %w", err)
+	}
+	if earned := earnedResult[0].(*big.Int); earned.Sign() == 0 {
+		return big.NewInt(0), common.Hash{}, nil
+	}
+
+	txHash, err := gaugeClient.SendCtx(b.rpcContext(), types.Standard, &b.myAddr, b.privateKey, "getReward")
+	if err != nil {
+		return nil, common.Hash{}, fmt.Errorf("failed to send getReward: %w", err)
+	}
+	receipt, err := b.tl.WaitForTransaction(txHash)
+	if err != nil {
+		return nil, txHash, fmt.Errorf("getReward transaction failed: %w", err)
+	}
+
+	event, ok := gaugeClient.FindEvent(receipt, "Harvest")
+	if !ok {
+		return nil, txHash, fmt.Errorf("Harvest event not found in receipt")
+	}
+	harvested, ok := event.Params["reward"].(*big.Int)
+	if !ok {
+		return nil, txHash, fmt.Errorf("Harvest event has invalid reward: %v", event.Params["reward"])
+	}
+	return harvested, txHash, nil
+}
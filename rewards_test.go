@@ -0,0 +1,99 @@
+package blackholedex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPendingRewards(t *testing.T) {
+	blackAddr := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	poolAddr := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+	incentiveID := [32]byte{0x01}
+
+	farming := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000f1"))
+	farming.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "deposits":
+			if args[0].(*big.Int).Int64() == 42 {
+				return []interface{}{incentiveID}, nil
+			}
+			return []interface{}{[32]byte{}}, nil
+		case "incentiveKeys":
+			return []interface{}{blackAddr, common.Address{}, poolAddr, big.NewInt(3)}, nil
+		case "collectRewards":
+			key := args[0].(types.IncentiveKey)
+			if key.Pool != poolAddr || key.Nonce.Int64() != 3 {
+				return nil, errors.New("execution reverted: unknown incentive")
+			}
+			return []interface{}{big.NewInt(1_500), big.NewInt(20)}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	b := newTestBlackhole(map[string]ContractClient{farmingCenter: farming}, &mockTxListener{})
+
+	rewards, err := b.GetPendingRewards(big.NewInt(42))
+	if assert.NoError(t, err) {
+		assert.Equal(t, big.NewInt(1_500), rewards.Reward)
+		assert.Equal(t, big.NewInt(20), rewards.BonusReward)
+		assert.Equal(t, blackAddr, rewards.RewardToken)
+		assert.Equal(t, common.Address{}, rewards.BonusRewardToken)
+	}
+	assert.Empty(t, farming.sentMethods())
+
+	_, err = b.GetPendingRewards(big.NewInt(43))
+	assert.ErrorIs(t, err, ErrNotStaked)
+}
+
+func TestHarvestGauge(t *testing.T) {
+	gaugeAddr := common.HexToAddress("0x00000000000000000000000000000000000000e5")
+	earned := big.NewInt(0)
+
+	nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+	nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "ownerOf" && args[0].(*big.Int).Int64() == 42 {
+			return []interface{}{gaugeAddr}, nil
+		}
+		return []interface{}{common.HexToAddress("0x00000000000000000000000000000000000000aa")}, nil
+	}
+	gaugeClient := newMockContractClient(gaugeAddr)
+	gaugeClient.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "earned" {
+			return []interface{}{earned}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	gaugeClient.events = `[{"event":"Harvest","parameter":{"user":"0x00000000000000000000000000000000000000aa","reward":1234}}]`
+	tl := &mockTxListener{}
+	b := newTestBlackhole(map[string]ContractClient{
+		nonfungiblePositionManager: nftManager,
+		gauge:                      gaugeClient,
+	}, tl)
+
+	t.Run("NothingEarned", func(t *testing.T) {
+		harvested, txHash, err := b.HarvestGauge(big.NewInt(42))
+		assert.NoError(t, err)
+		assert.Equal(t, 0, harvested.Sign())
+		assert.Equal(t, common.Hash{}, txHash)
+		assert.Empty(t, gaugeClient.sentMethods())
+	})
+
+	t.Run("EarnedThenClaimed", func(t *testing.T) {
+		earned = big.NewInt(1_200)
+		harvested, txHash, err := b.HarvestGauge(big.NewInt(42))
+		assert.NoError(t, err)
+		assert.Equal(t, big.NewInt(1234), harvested)
+		assert.Equal(t, []string{"getReward"}, gaugeClient.sentMethods())
+		assert.Equal(t, []common.Hash{txHash}, tl.waited)
+	})
+
+	t.Run("NotDeposited", func(t *testing.T) {
+		_, _, err := b.HarvestGauge(big.NewInt(43))
+		assert.ErrorIs(t, err, ErrNotStaked)
+		assert.Len(t, gaugeClient.sentMethods(), 1)
+	})
+}
@@ -0,0 +1,175 @@
+package blackholedex
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// rpcResponseTimeout bounds how long one endpoint may take to answer before the next is tried
+	rpcResponseTimeout = 15 * time.Second
+	// rpcProbeInterval is how often the primary endpoint is health checked while another one is in use
+	rpcProbeInterval = 30 * time.Second
+)
+
+// DialRPC connects to the RPC endpoints in urls, attaching headers to every request
+// With one URL it dials like ethclient.Dial, so WebSocket and IPC endpoints work; with several, all must be
+// HTTP(S) and requests fail over to the next endpoint on connection errors, timeouts and 5xx responses.
+// The first URL is the primary and is switched back to once it passes a health check
+func DialRPC(urls []string, headers map[string]string) (*ethclient.Client, error) {
+	if len(urls) == 0 {
+		return nil, errors.New("no RPC URL configured")
+	}
+	header := make(http.Header, len(headers))
+	for key, value := range headers {
+		header.Set(key, value)
+	}
+
+	opts := []rpc.ClientOption{rpc.WithHeaders(header)}
+	if len(urls) > 1 {
+		endpoints := make([]*url.URL, len(urls))
+		for i, rawURL := range urls {
+			endpoint, err := url.Parse(rawURL)
+			if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") {
+				return nil, fmt.Errorf("RPC failover needs HTTP endpoints, got %s", rawURL)
+			}
+			endpoints[i] = endpoint
+		}
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.ResponseHeaderTimeout = rpcResponseTimeout
+		opts = append(opts, rpc.WithHTTPClient(&http.Client{
+			Transport: newFailoverTransport(endpoints, transport, rpcProbeInterval),
+		}))
+	}
+
+	rpcClient, err := rpc.DialOptions(context.Background(), urls[0], opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to dial RPC %s: %w", urls[0], err)
+	}
+	return ethclient.NewClient(rpcClient), nil
+}
+
+// failoverTransport sends each JSON-RPC request to the endpoint in use, moving on to the next endpoint
+// when it cannot be reached or answers with a server error
+// While a backup is in use the primary (urls[0]) is probed every probeEvery and used again once healthy
+type failoverTransport struct {
+	urls       []*url.URL
+	base       http.RoundTripper
+	probeEvery time.Duration
+
+	mu        sync.Mutex
+	current   int
+	lastProbe time.Time
+}
+
+func newFailoverTransport(urls []*url.URL, base http.RoundTripper, probeEvery time.Duration) *failoverTransport {
+	return &failoverTransport{urls: urls, base: base, probeEvery: probeEvery}
+}
+
+func (t *failoverTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	start := t.endpoint(req)
+	var lastErr error
+	for i := range t.urls {
+		idx := (start + i) % len(t.urls)
+		resp, err := t.base.RoundTrip(withURL(req, t.urls[idx], body))
+		if err == nil && resp.StatusCode < http.StatusInternalServerError {
+			t.use(idx)
+			return resp, nil
+		}
+		if err == nil {
+			resp.Body.Close()
+			err = fmt.Errorf("%s: %s", t.urls[idx].Host, resp.Status)
+		}
+		// A cancelled request would fail on every endpoint
+		if ctxErr := req.Context().Err(); ctxErr != nil {
+			return nil, ctxErr
+		}
+		lastErr = err
+	}
+	return nil, fmt.Errorf("all RPC endpoints failed: %w", lastErr)
+}
+
+// endpoint returns the index of the endpoint to try first, switching back to the primary if a due probe passes
+func (t *failoverTransport) endpoint(req *http.Request) int {
+	t.mu.Lock()
+	current := t.current
+	probe := current != 0 && time.Since(t.lastProbe) >= t.probeEvery
+	if probe {
+		t.lastProbe = time.Now()
+	}
+	t.mu.Unlock()
+
+	if probe && t.healthy(req, t.urls[0]) {
+		t.use(0)
+		return 0
+	}
+	return current
+}
+
+// use makes the endpoint at idx the one requests are sent to first
+func (t *failoverTransport) use(idx int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.current == idx {
+		return
+	}
+	log.Printf("RPC endpoint switched from %s to %s", t.urls[t.current].Host, t.urls[idx].Host)
+	t.current = idx
+	if idx != 0 {
+		t.lastProbe = time.Now()
+	}
+}
+
+// healthy reports whether endpoint answers an eth_blockNumber request without error
+func (t *failoverTransport) healthy(req *http.Request, endpoint *url.URL) bool {
+	probe := []byte(`{"jsonrpc":"2.0","id":1,"method":"eth_blockNumber","params":[]}`)
+	resp, err := t.base.RoundTrip(withURL(req, endpoint, probe))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	var result struct {
+		Result string    `json:"result"`
+		Error  *struct{} `json:"error"`
+	}
+	if resp.StatusCode != http.StatusOK || json.NewDecoder(resp.Body).Decode(&result) != nil {
+		return false
+	}
+	return result.Error == nil && result.Result != ""
+}
+
+// withURL copies req, with its headers and context, sending body to endpoint
+func withURL(req *http.Request, endpoint *url.URL, body []byte) *http.Request {
+	out := req.Clone(req.Context())
+	target := *endpoint
+	out.URL = &target
+	out.Host = ""
+	out.Body = io.NopCloser(bytes.NewReader(body))
+	out.ContentLength = int64(len(body))
+	out.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(body)), nil
+	}
+	return out
+}
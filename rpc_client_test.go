@@ -0,0 +1,162 @@
+package blackholedex
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type chainIDBackend struct{}
+
+func (chainIDBackend) ChainId() hexutil.Uint64 {
+	return 43114
+}
+
+func TestDialRPCHeaders(t *testing.T) {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", chainIDBackend{}); err != nil {
+		t.Fatal(err)
+	}
+	var mu sync.Mutex
+	var apiKeys []string
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		apiKeys = append(apiKeys, r.Header.Get("X-Api-Key"))
+		mu.Unlock()
+		server.ServeHTTP(w, r)
+	}))
+	defer httpServer.Close()
+
+	client, err := DialRPC([]string{httpServer.URL}, map[string]string{"x-api-key": "secret"})
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer client.Close()
+
+	chainID, err := client.ChainID(t.Context())
+	assert.NoError(t, err)
+	assert.Equal(t, int64(43114), chainID.Int64())
+
+	mu.Lock()
+	defer mu.Unlock()
+	assert.Equal(t, []string{"secret"}, apiKeys)
+}
+
+// countingServer serves chainIDBackend, failing with 503 while down is set
+type countingServer struct {
+	*httptest.Server
+	mu       sync.Mutex
+	down     bool
+	requests int
+}
+
+func newCountingServer(t *testing.T) *countingServer {
+	server := rpc.NewServer()
+	if err := server.RegisterName("eth", chainIDBackend{}); err != nil {
+		t.Fatal(err)
+	}
+	s := &countingServer{}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		s.mu.Lock()
+		s.requests++
+		down := s.down
+		s.mu.Unlock()
+		if down {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		server.ServeHTTP(w, r)
+	}))
+	t.Cleanup(s.Close)
+	return s
+}
+
+func (s *countingServer) setDown(down bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.down = down
+}
+
+func (s *countingServer) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.requests
+}
+
+func (chainIDBackend) BlockNumber() hexutil.Uint64 {
+	return 1
+}
+
+func TestDialRPCFailover(t *testing.T) {
+	t.Run("UnreachablePrimary", func(t *testing.T) {
+		primary := httptest.NewServer(http.NotFoundHandler())
+		primary.Close()
+		secondary := newCountingServer(t)
+
+		client, err := DialRPC([]string{primary.URL, secondary.URL}, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer client.Close()
+
+		for range 2 {
+			chainID, err := client.ChainID(t.Context())
+			assert.NoError(t, err)
+			assert.Equal(t, int64(43114), chainID.Int64())
+		}
+		assert.Equal(t, 2, secondary.count())
+	})
+
+	t.Run("SwitchBackAfterHealthCheck", func(t *testing.T) {
+		primary, secondary := newCountingServer(t), newCountingServer(t)
+		primaryURL, _ := url.Parse(primary.URL)
+		secondaryURL, _ := url.Parse(secondary.URL)
+		transport := newFailoverTransport([]*url.URL{primaryURL, secondaryURL}, http.DefaultTransport, time.Hour)
+		rpcClient, err := rpc.DialOptions(t.Context(), primary.URL, rpc.WithHTTPClient(&http.Client{Transport: transport}))
+		if !assert.NoError(t, err) {
+			return
+		}
+		client := ethclient.NewClient(rpcClient)
+		defer client.Close()
+
+		primary.setDown(true)
+		_, err = client.ChainID(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, secondary.count())
+
+		// Recovered, but not probed until the interval passes
+		primary.setDown(false)
+		_, err = client.ChainID(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, 1, primary.count())
+		assert.Equal(t, 2, secondary.count())
+
+		transport.probeEvery = 0
+		_, err = client.ChainID(t.Context())
+		assert.NoError(t, err)
+		assert.Equal(t, 3, primary.count(), "health check and request go to the primary")
+		assert.Equal(t, 2, secondary.count())
+	})
+
+	t.Run("AllDown", func(t *testing.T) {
+		primary, secondary := newCountingServer(t), newCountingServer(t)
+		primary.setDown(true)
+		secondary.setDown(true)
+		client, err := DialRPC([]string{primary.URL, secondary.URL}, nil)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer client.Close()
+
+		_, err = client.ChainID(t.Context())
+		assert.ErrorContains(t, err, "all RPC endpoints failed")
+	})
+}
@@ -0,0 +1,78 @@
+package blackholedex
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/metrics"
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+// shutdownTimeout bounds the unstake and withdraw made when the strategy is cancelled
+const shutdownTimeout = 5 * time.Minute
+
+// holdsPosition reports whether phase has a staked position, or one part-way through being withdrawn
+func holdsPosition(phase types.StrategyPhase, state *types.StrategyState) bool {
+	switch phase {
+	case types.ActiveMonitoring:
+		return true
+	case types.RebalancingRequired:
+		return state.CurrentStep < types.Step_Rebalance_WithdrawCompleted
+	default:
+		return false
+	}
+}
+
+// shutdown runs when the strategy's context is cancelled, then sends the shutdown report with the final NetPnL
+// With config.WithdrawOnShutdown, a staked position is unstaked and withdrawn to the wallet first, resuming
+// from the rebalance checkpoint if one was in progress. phase is the phase before any pause
+// The transactions use a context detached from ctx, so cancellation does not abort them part-way
+func (b *Blackhole) shutdown(
+	ctx context.Context,
+	config *types.StrategyConfig,
+	state *types.StrategyState,
+	phase types.StrategyPhase,
+	nonce *big.Int,
+	reportChan chan<- string,
+) {
+	if config.WithdrawOnShutdown && holdsPosition(phase, state) {
+		shutdownCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), shutdownTimeout)
+		defer cancel()
+		b.runCtx.Store(&shutdownCtx)
+
+		log.Printf("Shutdown: withdrawing position NFT %v to the wallet", state.NFTTokenID)
+		if _, err := b.executeRebalancing(config, state, nonce, reportChan); err != nil {
+			b.status.recordError(err)
+			b.sendReport(reportChan, types.StrategyReport{
+				Timestamp:  time.Now(),
+				EventType:  "error",
+				Message:    fmt.Sprintf("Withdraw on shutdown failed at step %s", state.CurrentStep.String()),
+				Error:      err.Error(),
+				Phase:      &state.CurrentState,
+				NFTTokenID: state.NFTTokenID,
+			})
+		} else {
+			metrics.SetPositionLiquidity(nil)
+			b.RecordCurrentAssetSnapshot(state)
+		}
+	}
+
+	state.CurrentState = types.Halted
+	b.status.publish(state)
+
+	netPnL := new(big.Int).Sub(state.CumulativeRewards, state.CumulativeGas)
+	netPnL = new(big.Int).Sub(netPnL, state.TotalSwapFees)
+	b.sendReport(reportChan, types.StrategyReport{
+		Timestamp:      time.Now(),
+		EventType:      "shutdown",
+		Message:        "Strategy stopped: context cancelled",
+		Phase:          &state.CurrentState,
+		CumulativeGas:  state.CumulativeGas,
+		Profit:         state.CumulativeRewards,
+		NetPnL:         netPnL,
+		GasByOperation: state.GasByOperation,
+	})
+}
@@ -0,0 +1,90 @@
+package blackholedex
+
+import (
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShutdownWithdrawsPosition(t *testing.T) {
+	for _, withdraw := range []bool{true, false} {
+		b, clients := newRebalanceBlackhole(t)
+		// The wallet holds NFT 42, loaded in ActiveMonitoring
+		nftManager := clients[nonfungiblePositionManager]
+		positions := nftManager.callFn
+		nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			switch method {
+			case "balanceOf":
+				return []interface{}{big.NewInt(1)}, nil
+			case "tokenOfOwnerByIndex":
+				return []interface{}{big.NewInt(42)}, nil
+			}
+			return positions(method, args...)
+		}
+		// No monitoring ticks before the cancellation
+		b.tickPeriod = time.Hour
+
+		config := types.DefaultStrategyConfig()
+		config.CodeHashCheckInterval = 0
+		config.WithdrawOnShutdown = withdraw
+
+		reports := make(chan string, 100)
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan error, 1)
+		go func() {
+			done <- b.RunAutoPositionStrategy(ctx, reports, config)
+		}()
+
+		var shutdownReport string
+		timeout := time.After(2 * time.Second)
+	run:
+		for {
+			select {
+			case report := <-reports:
+				if strings.Contains(report, `"event_type":"strategy_start"`) {
+					cancel()
+				}
+				if strings.Contains(report, `"event_type":"shutdown"`) {
+					shutdownReport = report
+				}
+			case err := <-done:
+				assert.ErrorIs(t, err, context.Canceled)
+				// Reports sent before returning may still be buffered
+				for len(reports) > 0 {
+					if report := <-reports; strings.Contains(report, `"event_type":"shutdown"`) {
+						shutdownReport = report
+					}
+				}
+				break run
+			case <-timeout:
+				t.Fatal("strategy did not stop after cancellation")
+			}
+		}
+		cancel()
+
+		assert.Contains(t, shutdownReport, `"net_pnl"`)
+		if withdraw {
+			// Unstaked from the farming center, then collected and burned through the position manager
+			assert.Equal(t, []string{"multicall"}, clients[farmingCenter].sentMethods())
+			assert.Equal(t, []string{"multicall"}, nftManager.sentMethods())
+		} else {
+			assert.Empty(t, clients[farmingCenter].sentMethods())
+			assert.Empty(t, nftManager.sentMethods())
+		}
+	}
+}
+
+func TestHoldsPosition(t *testing.T) {
+	state := &types.StrategyState{CurrentStep: types.Step_Rebalance_UnstakeCompleted}
+	assert.True(t, holdsPosition(types.ActiveMonitoring, state))
+	assert.True(t, holdsPosition(types.RebalancingRequired, state))
+	assert.False(t, holdsPosition(types.WaitingForStability, state))
+
+	state.CurrentStep = types.Step_Rebalance_WithdrawCompleted
+	assert.False(t, holdsPosition(types.RebalancingRequired, state))
+}
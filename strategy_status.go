@@ -0,0 +1,141 @@
+package blackholedex
+
+import (
+	"math/big"
+	"sync"
+	"sync/atomic"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/metrics"
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+// strategyStatus holds the externally observable state of a running strategy
+// Fields are updated atomically so a supervisor can poll them without consuming reportChan
+type strategyStatus struct {
+	phase   atomic.Int32
+	nftID   atomic.Pointer[big.Int]
+	lastErr atomic.Pointer[error]
+
+	droppedReports atomic.Uint64 // Non-critical reports dropped because reportChan was full
+
+	paused      atomic.Bool
+	pauseSignal chan struct{} // Wakes the strategy loop after Pause/Resume
+	signalOnce  sync.Once
+
+	mu            sync.Mutex
+	cumulativeGas *big.Int
+	config        *types.StrategyConfig
+	reports       []types.StrategyReport // Most recent reports, oldest first
+}
+
+// maxRecentReports bounds the reports kept for ExportState
+const maxRecentReports = 20
+
+// publish copies the phase and active NFT from the strategy state and reports the phase to metrics
+func (s *strategyStatus) publish(state *types.StrategyState) {
+	s.phase.Store(int32(state.CurrentState))
+	metrics.SetPhase(state.CurrentState)
+	if state.NFTTokenID == nil {
+		s.nftID.Store(nil)
+	} else {
+		s.nftID.Store(new(big.Int).Set(state.NFTTokenID))
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if state.CumulativeGas != nil {
+		s.cumulativeGas = new(big.Int).Set(state.CumulativeGas)
+	}
+}
+
+// setConfig stores the configuration of the running strategy
+func (s *strategyStatus) setConfig(config *types.StrategyConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = config
+}
+
+// recordReport keeps report among the most recent maxRecentReports reports
+func (s *strategyStatus) recordReport(report types.StrategyReport) {
+	// Reports point into the live strategy state; keep copies of what changes in place
+	if report.Phase != nil {
+		phase := *report.Phase
+		report.Phase = &phase
+	}
+	if report.GasByOperation != nil {
+		gas := make(map[string]*big.Int, len(report.GasByOperation))
+		for op, cost := range report.GasByOperation {
+			gas[op] = cost
+		}
+		report.GasByOperation = gas
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.reports) == maxRecentReports {
+		s.reports = append(s.reports[:0], s.reports[1:]...)
+	}
+	s.reports = append(s.reports, report)
+}
+
+// pauseSignals returns the channel the strategy loop watches for Pause/Resume calls
+func (s *strategyStatus) pauseSignals() <-chan struct{} {
+	s.signalOnce.Do(func() { s.pauseSignal = make(chan struct{}, 1) })
+	return s.pauseSignal
+}
+
+// requestPause records the requested pause state and wakes the strategy loop
+// Calls made while the strategy is not running take effect when it starts
+func (s *strategyStatus) requestPause(paused bool) {
+	s.paused.Store(paused)
+	s.pauseSignals()
+	select {
+	case s.pauseSignal <- struct{}{}:
+	default: // A wake-up is already pending
+	}
+}
+
+// pauseRequested reports whether the latest Pause/Resume call was Pause
+func (s *strategyStatus) pauseRequested() bool {
+	return s.paused.Load()
+}
+
+// recordError stores err as the most recent strategy error
+func (s *strategyStatus) recordError(err error) {
+	if err == nil {
+		return
+	}
+	s.lastErr.Store(&err)
+}
+
+// CurrentPhase returns the current phase of the running strategy
+// Safe to call concurrently with RunAutoPositionStrategy
+func (b *Blackhole) CurrentPhase() types.StrategyPhase {
+	return types.StrategyPhase(b.status.phase.Load())
+}
+
+// ActiveNFT returns a copy of the active position NFT token ID, or nil if there is none
+// Safe to call concurrently with RunAutoPositionStrategy
+func (b *Blackhole) ActiveNFT() *big.Int {
+	id := b.status.nftID.Load()
+	if id == nil {
+		return nil
+	}
+	return new(big.Int).Set(id)
+}
+
+// LastError returns the most recent error observed by the strategy loop, or nil
+// Safe to call concurrently with RunAutoPositionStrategy
+func (b *Blackhole) LastError() error {
+	err := b.status.lastErr.Load()
+	if err == nil {
+		return nil
+	}
+	return *err
+}
+
+// DroppedReports returns how many non-critical reports were dropped because the report channel was full
+// Safe to call concurrently with RunAutoPositionStrategy
+func (b *Blackhole) DroppedReports() uint64 {
+	return b.status.droppedReports.Load()
+}
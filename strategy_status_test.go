@@ -0,0 +1,118 @@
+package blackholedex
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+// Run with -race to verify accessors are safe while the strategy loop is running
+func TestStrategyStatusAccessors(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+
+	nftManager := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000b1"))
+	nftManager.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		switch method {
+		case "balanceOf":
+			return []interface{}{big.NewInt(1)}, nil
+		case "tokenOfOwnerByIndex":
+			return []interface{}{big.NewInt(42)}, nil
+		case "positions":
+			return []interface{}{
+				big.NewInt(0), common.Address{}, wavaxAddr, usdcAddr, common.Address{},
+				big.NewInt(-400), big.NewInt(400), big.NewInt(1000),
+				big.NewInt(0), big.NewInt(0), big.NewInt(0), big.NewInt(0),
+			}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+
+	b := newTestBlackhole(map[string]ContractClient{
+		nonfungiblePositionManager: nftManager,
+		wavax:                      newMockContractClient(wavaxAddr),
+		usdc:                       newMockContractClient(usdcAddr),
+	}, &mockTxListener{})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- b.RunAutoPositionStrategy(ctx, nil, types.DefaultStrategyConfig())
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		deadline := time.Now().Add(2 * time.Second)
+		for time.Now().Before(deadline) {
+			_ = b.CurrentPhase()
+			_ = b.LastError()
+			if nft := b.ActiveNFT(); nft != nil {
+				return
+			}
+		}
+	}()
+	wg.Wait()
+
+	assert.Equal(t, types.ActiveMonitoring, b.CurrentPhase())
+	assert.Equal(t, big.NewInt(42), b.ActiveNFT())
+	assert.NoError(t, b.LastError())
+
+	cancel()
+	assert.ErrorIs(t, <-done, context.Canceled)
+}
+
+func TestStrategyStatusRecordError(t *testing.T) {
+	b := &Blackhole{}
+	assert.Nil(t, b.ActiveNFT())
+	assert.NoError(t, b.LastError())
+
+	state := &types.StrategyState{CurrentState: types.Halted, NFTTokenID: big.NewInt(7)}
+	b.status.publish(state)
+	b.status.recordError(errors.New("boom"))
+
+	// Mutating the strategy state must not leak into published values
+	state.NFTTokenID.SetInt64(8)
+
+	assert.Equal(t, types.Halted, b.CurrentPhase())
+	assert.Equal(t, big.NewInt(7), b.ActiveNFT())
+	assert.EqualError(t, b.LastError(), "boom")
+}
+
+func TestSendReportSlowConsumer(t *testing.T) {
+	b := newTestBlackhole(nil, &mockTxListener{})
+	reportChan := make(chan string, 1)
+
+	// Nobody is reading yet: the first report fills the buffer and the rest are dropped
+	for i := 0; i < 3; i++ {
+		b.sendReport(reportChan, types.StrategyReport{EventType: "monitoring", Message: fmt.Sprint(i)})
+	}
+	assert.Equal(t, uint64(2), b.DroppedReports())
+
+	// A halt report waits for the slow consumer instead of being dropped
+	delivered := make(chan struct{})
+	go func() {
+		b.sendReport(reportChan, types.StrategyReport{EventType: "shutdown", Message: "halted"})
+		close(delivered)
+	}()
+	time.Sleep(20 * time.Millisecond)
+	select {
+	case <-delivered:
+		t.Fatal("halt report sent while the channel was full")
+	default:
+	}
+
+	assert.Contains(t, <-reportChan, `"event_type":"monitoring"`)
+	assert.Contains(t, <-reportChan, `"event_type":"shutdown"`)
+	<-delivered
+	assert.Equal(t, uint64(2), b.DroppedReports())
+}
@@ -0,0 +1,58 @@
+package blackholedex
+
+import (
+	"fmt"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+)
+
+// EvaluateStrategyStep returns the action the strategy would take for the given pool state and why
+// position is the open position, or nil when the funds are withdrawn and waiting to re-enter
+// window holds the stability checks so far; it is evaluated on a copy and left unchanged
+// Makes no RPC calls and sends nothing, so it can be used for previews and tests
+func (b *Blackhole) EvaluateStrategyStep(
+	state *types.AMMState,
+	position *types.PositionSnapshot,
+	config *types.StrategyConfig,
+	window *types.StabilityWindow,
+) (types.StrategyAction, string) {
+	if b.status.pauseRequested() {
+		return types.Pause, "strategy is paused, position held as-is"
+	}
+	if state == nil || state.SqrtPrice == nil || state.SqrtPrice.Sign() <= 0 {
+		return types.Hold, "pool state unavailable"
+	}
+
+	if position != nil {
+		positionRange := &types.PositionRange{TickLower: position.TickLower, TickUpper: position.TickUpper}
+		if !positionRange.IsOutOfRange(state.Tick) {
+			return types.Hold, fmt.Sprintf("tick %d within [%d, %d]", state.Tick, position.TickLower, position.TickUpper)
+		}
+
+		// How far past the range the price is, in ticks
+		gap := position.TickLower - state.Tick
+		if state.Tick > position.TickUpper {
+			gap = state.Tick - position.TickUpper
+		}
+		if gap > positionRange.Width() {
+			return types.EmergencyExit, fmt.Sprintf("tick %d is %d ticks outside [%d, %d], more than the range width",
+				state.Tick, gap, position.TickLower, position.TickUpper)
+		}
+		return types.Rebalance, fmt.Sprintf("tick %d outside [%d, %d]", state.Tick, position.TickLower, position.TickUpper)
+	}
+
+	var w types.StabilityWindow
+	if window != nil {
+		w = *window
+	} else {
+		w = types.StabilityWindow{
+			Threshold:          config.StabilityThreshold,
+			RequiredIntervals:  config.StabilityIntervals,
+			MaxCumulativeDrift: config.MaxCumulativeDrift,
+		}
+	}
+	if w.CheckStability(state.SqrtPrice) {
+		return types.Reenter, fmt.Sprintf("price stable for %d intervals", w.RequiredIntervals)
+	}
+	return types.Hold, fmt.Sprintf("waiting for stability (%d/%d intervals)", w.StableCount, w.RequiredIntervals)
+}
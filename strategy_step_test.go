@@ -0,0 +1,72 @@
+package blackholedex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEvaluateStrategyStep(t *testing.T) {
+	config := types.DefaultStrategyConfig()
+	position := &types.PositionSnapshot{NFTTokenID: big.NewInt(42), TickLower: -400, TickUpper: 400}
+	atTick := func(tick int32) *types.AMMState {
+		return &types.AMMState{SqrtPrice: util.TickToSqrtPriceX96(int(tick)), Tick: tick}
+	}
+	// One stable interval short of re-entry at the current price
+	nearlyStable := func() *types.StabilityWindow {
+		return &types.StabilityWindow{
+			Threshold:          config.StabilityThreshold,
+			RequiredIntervals:  config.StabilityIntervals,
+			MaxCumulativeDrift: config.MaxCumulativeDrift,
+			LastPrice:          util.Q96,
+			WindowStartPrice:   util.Q96,
+			StableCount:        config.StabilityIntervals - 1,
+		}
+	}
+
+	tests := []struct {
+		name     string
+		state    *types.AMMState
+		position *types.PositionSnapshot
+		window   *types.StabilityWindow
+		action   types.StrategyAction
+	}{
+		{"InRange", atTick(100), position, nil, types.Hold},
+		{"OutOfRange", atTick(600), position, nil, types.Rebalance},
+		{"GappedPastRange", atTick(-1300), position, nil, types.EmergencyExit},
+		{"StableWithoutPosition", atTick(0), nil, nearlyStable(), types.Reenter},
+		{"UnstableWithoutPosition", atTick(1000), nil, nearlyStable(), types.Hold},
+		{"NoWindowYet", atTick(0), nil, nil, types.Hold},
+		{"NoPoolState", nil, position, nil, types.Hold},
+	}
+
+	b := newTestBlackhole(nil, &mockTxListener{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var before types.StabilityWindow
+			if tt.window != nil {
+				before = *tt.window
+			}
+			action, reason := b.EvaluateStrategyStep(tt.state, tt.position, config, tt.window)
+			assert.Equal(t, tt.action, action, reason)
+			assert.NotEmpty(t, reason)
+			if tt.window != nil {
+				assert.Equal(t, before, *tt.window, "window must be left unchanged")
+			}
+		})
+	}
+
+	t.Run("Paused", func(t *testing.T) {
+		b := newTestBlackhole(nil, &mockTxListener{})
+		b.Pause()
+		action, _ := b.EvaluateStrategyStep(atTick(600), position, config, nil)
+		assert.Equal(t, types.Pause, action)
+
+		b.Resume()
+		action, _ = b.EvaluateStrategyStep(atTick(600), position, config, nil)
+		assert.Equal(t, types.Rebalance, action)
+	})
+}
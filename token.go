@@ -3,20 +3,192 @@ package blackholedex
 import (
 	"errors"
 	"fmt"
+	"log"
 	"math/big"
 
 	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
 
+	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
 )
 
+// ErrNoRoute is returned when no pool exists to swap From into To
+type ErrNoRoute struct {
+	From common.Address
+	To   common.Address
+}
+
+func (e *ErrNoRoute) Error() string {
+	return fmt.Sprintf("no swap route from %s to %s: no pool with liquidity for this pair", e.From.Hex(), e.To.Hex())
+}
+
+// findRoute builds a single-hop concentrated route from one token to another
+// With an Algebra factory registered, the deepest pool for the pair is used;
+// otherwise only the configured WAVAX/USDC pool is known
+// Returns *ErrNoRoute when the pair has no pool
+func (b *Blackhole) findRoute(from, to common.Address) (types.Route, error) {
+	route := types.Route{
+		From:         from,
+		To:           to,
+		Stable:       false,
+		Concentrated: true,
+		Receiver:     b.myAddr,
+	}
+
+	if _, err := b.registry.Client(algebraFactory); err == nil {
+		pools, err := b.ListPoolsForPair(from, to)
+		if err != nil {
+			return types.Route{}, fmt.Errorf("failed to look up pools: %w", err)
+		}
+		if len(pools) == 0 || pools[0].Liquidity.Sign() == 0 {
+			return types.Route{}, &ErrNoRoute{From: from, To: to}
+		}
+		route.Pair = pools[0].Address
+		return route, nil
+	}
+
+	wavaxAddr, _ := b.registry.GetAddress(wavax)
+	usdcAddr, _ := b.registry.GetAddress(usdc)
+	if !(from == wavaxAddr && to == usdcAddr) && !(from == usdcAddr && to == wavaxAddr) {
+		return types.Route{}, &ErrNoRoute{From: from, To: to}
+	}
+	pairAddr, err := b.registry.GetAddress(wavaxUsdcPair)
+	if err != nil {
+		return types.Route{}, &ErrNoRoute{From: from, To: to}
+	}
+	route.Pair = pairAddr
+	return route, nil
+}
+
+// QuoteSwap returns the amount of the last route's To token that params.AmountIn would buy
+// RouterV2 has no getAmountsOut, so each hop is quoted with getPoolAmountOut and fed into the next
+func (b *Blackhole) QuoteSwap(params *types.SWAPExactTokensForTokensParams) (*big.Int, error) {
+	if len(params.Routes) == 0 {
+		return nil, errors.New("no routes provided")
+	}
+	if params.AmountIn == nil || params.AmountIn.Sign() <= 0 {
+		return nil, errors.New("swap amount must be positive")
+	}
+
+	swapClient, err := b.registry.Client(routerv2)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swap client %s: %w", routerv2, err)
+	}
+
+	amount := params.AmountIn
+	for i, route := range params.Routes {
+		result, err := swapClient.CallCtx(b.rpcContext(), &b.myAddr, "getPoolAmountOut", amount, route.From, route.Pair)
+		if err != nil {
+			return nil, fmt.Errorf("failed to quote hop %d (%s -> %s): %w", i, route.From.Hex(), route.To.Hex(), err)
+		}
+		amount = result[0].(*big.Int)
+	}
+	return amount, nil
+}
+
+// EstimatePriceImpact returns how much worse than the pool's spot price params would execute, in percent
+// The spot price comes from GetAMMState and the execution price from the QuoteSwap quote, so the pool fee is included
+// Only swaps between WAVAX and USDC can be compared with the pool price; a negative impact means a better price
+func (b *Blackhole) EstimatePriceImpact(params *types.SWAPExactTokensForTokensParams) (float64, error) {
+	if len(params.Routes) == 0 {
+		return 0, ErrNoRoutes
+	}
+	wavaxAddr, _ := b.registry.GetAddress(wavax)
+	usdcAddr, _ := b.registry.GetAddress(usdc)
+	from, to := params.Routes[0].From, params.Routes[len(params.Routes)-1].To
+	var tokenToSwap int
+	switch {
+	case from == wavaxAddr && to == usdcAddr:
+		tokenToSwap = 0
+	case from == usdcAddr && to == wavaxAddr:
+		tokenToSwap = 1
+	default:
+		return 0, fmt.Errorf("price impact is only estimated for WAVAX/USDC swaps, got %s -> %s", from.Hex(), to.Hex())
+	}
+
+	poolState, err := b.GetAMMState()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pool state: %w", err)
+	}
+	quote, err := b.QuoteSwap(params)
+	if err != nil {
+		return 0, fmt.Errorf("failed to quote swap: %w", err)
+	}
+
+	spotOut := expectedSwapOut(poolState, tokenToSwap, params.AmountIn)
+	if spotOut.Sign() == 0 {
+		return 0, fmt.Errorf("swap of %s is worth nothing at the pool price", params.AmountIn)
+	}
+	shortfall := new(big.Float).SetInt(new(big.Int).Sub(spotOut, quote))
+	impact, _ := new(big.Float).Quo(shortfall, new(big.Float).SetInt(spotOut)).Float64()
+	return impact * 100, nil
+}
+
+// maxImpactHalvings is how many times limitPriceImpact halves a swap before giving up on it
+const maxImpactHalvings = 3
+
+// limitPriceImpact halves params.AmountIn, scaling AmountOutMin with it, until EstimatePriceImpact
+// is at most maxImpactPct; returns nil when even an eighth of the swap exceeds it
+// A maxImpactPct of 0 disables the check and returns params unchanged
+func (b *Blackhole) limitPriceImpact(params *types.SWAPExactTokensForTokensParams, maxImpactPct float64) (*types.SWAPExactTokensForTokensParams, error) {
+	if maxImpactPct <= 0 {
+		return params, nil
+	}
+
+	limited := *params
+	for i := 0; i <= maxImpactHalvings; i++ {
+		impact, err := b.EstimatePriceImpact(&limited)
+		if err != nil {
+			return nil, err
+		}
+		if impact <= maxImpactPct {
+			if i > 0 {
+				log.Printf("Swap shrunk from %s to %s to keep price impact at %.2f%% (max %.2f%%)",
+					params.AmountIn, limited.AmountIn, impact, maxImpactPct)
+			}
+			return &limited, nil
+		}
+		if i == maxImpactHalvings {
+			log.Printf("Swap of %s skipped: price impact %.2f%% exceeds %.2f%% even at 1/%d of the amount",
+				params.AmountIn, impact, maxImpactPct, 1<<maxImpactHalvings)
+			break
+		}
+		limited.AmountIn = new(big.Int).Rsh(limited.AmountIn, 1)
+		if limited.AmountOutMin != nil {
+			limited.AmountOutMin = new(big.Int).Rsh(limited.AmountOutMin, 1)
+		}
+	}
+	return nil, nil
+}
+
+// SwapWithSlippage swaps with AmountOutMin set to the QuoteSwap quote less slippagePct percent
+// params is not modified
+func (b *Blackhole) SwapWithSlippage(params *types.SWAPExactTokensForTokensParams, slippagePct int) (common.Hash, error) {
+	if slippagePct < 0 || slippagePct >= 100 {
+		return common.Hash{}, fmt.Errorf("slippage must be in [0, 100), got %d", slippagePct)
+	}
+	quote, err := b.QuoteSwap(params)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to quote swap: %w", err)
+	}
+
+	withMin := *params
+	withMin.AmountOutMin = util.CalculateMinAmount(quote, slippagePct)
+	return b.Swap(&withMin)
+}
+
 // Swap performs a token-to-token swap on Blackhole DEX
 // It first approves the swap router to spend the input token, then executes the swap
+// A nil params.Deadline defaults to txDeadlineOffset from now
 func (b *Blackhole) Swap(
 	params *types.SWAPExactTokensForTokensParams,
 ) (common.Hash, error) { // todo. 다른 함수들처럼 result 반환으로 수정 필요?
 	if len(params.Routes) == 0 {
-		return common.Hash{}, errors.New("no routes provided")
+		return common.Hash{}, ErrNoRoutes
+	}
+	if err := util.ValidateRoutes(params.Routes); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid swap route: %w", err)
 	}
 
 	swapClient, err := b.registry.Client(routerv2)
@@ -24,48 +196,142 @@ func (b *Blackhole) Swap(
 		return common.Hash{}, fmt.Errorf("failed to get swap client %s: %w", routerv2, err)
 	}
 
-	fromTokenAddress := params.Routes[0].From.Hex()
-	tokenClient, err := b.registry.ClientByAddress(fromTokenAddress)
+	// Step 1: Approve the swap router to spend the input tokens
+	if err := b.approveSwapInput(swapClient, params.Routes[0].From, params.AmountIn); err != nil {
+		return common.Hash{}, err
+	}
+
+	deadline := params.Deadline
+	if deadline == nil {
+		deadline = b.txDeadline(txDeadlineOffset)
+	}
+
+	// Step 2: Execute the swap
+	if b.dryRun {
+		_, err := b.simulate(swapClient, "swapExactTokensForTokens",
+			params.AmountIn,
+			params.AmountOutMin,
+			params.Routes,
+			params.To,
+			deadline,
+		)
+		return common.Hash{}, err
+	}
+
+	swapTxHash, err := swapClient.SendCtx(
+		b.rpcContext(),
+		types.Standard,
+		&b.myAddr,
+		b.privateKey,
+		"swapExactTokensForTokens",
+		params.AmountIn,
+		params.AmountOutMin,
+		params.Routes,
+		params.To,
+		deadline,
+	)
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to get from client for token %s: %w", fromTokenAddress, err)
+		return common.Hash{}, fmt.Errorf("failed to execute swap: %w", err)
 	}
 
-	// Get the ERC20 client for the input token (first token in the route)
-	// Step 1: Approve the swap router to spend the input tokens
+	return swapTxHash, nil
+}
 
-	approveTxHash, err := b.ensureApproval(tokenClient, *swapClient.ContractAddress(), params.AmountIn)
+// approveSwapInput approves router to spend amount of token, the input of a swap route, and waits for the approval
+func (b *Blackhole) approveSwapInput(router ContractClient, token common.Address, amount *big.Int) error {
+	tokenClient, err := b.registry.ClientByAddress(token.Hex())
 	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to approve tokens: %w", err)
+		return fmt.Errorf("failed to get from client for token %s: %w", token.Hex(), err)
+	}
+	if *tokenClient.ContractAddress() != token {
+		return fmt.Errorf("input token client is at %s, route starts at %s",
+			tokenClient.ContractAddress().Hex(), token.Hex())
 	}
 
+	approveTxHash, err := b.approveOrSimulate(tokenClient, *router.ContractAddress(), amount)
+	if err != nil {
+		return fmt.Errorf("failed to approve tokens: %w", err)
+	}
 	if approveTxHash != (common.Hash{}) {
-		// Log approval transaction hash (in production, you might want to wait for confirmation)
-		_, err = b.tl.WaitForTransaction(approveTxHash)
-		if err != nil {
-			return common.Hash{}, fmt.Errorf("failed to approve tokens: %w", err)
+		if _, err := b.tl.WaitForTransaction(approveTxHash); err != nil {
+			return fmt.Errorf("failed to approve tokens: %w", err)
 		}
 	}
+	return nil
+}
 
-	// Step 2: Execute the swap
-	swapTxHash, err := swapClient.Send(
+// SwapTokensForETH swaps params.AmountIn of the first route's token for native AVAX
+// The router unwraps the WAVAX the last route buys, so that route must end at WAVAX
+// AmountOutMin is set to the QuoteSwap quote less slippagePct percent; params is not modified
+// A nil params.Deadline defaults to txDeadlineOffset from now
+func (b *Blackhole) SwapTokensForETH(params *types.SWAPExactTokensForETHParams, slippagePct int) (common.Hash, error) {
+	if len(params.Routes) == 0 {
+		return common.Hash{}, ErrNoRoutes
+	}
+	if err := util.ValidateRoutes(params.Routes); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid swap route: %w", err)
+	}
+	if slippagePct < 0 || slippagePct >= 100 {
+		return common.Hash{}, fmt.Errorf("slippage must be in [0, 100), got %d", slippagePct)
+	}
+	wavaxAddr, err := b.registry.GetAddress(wavax)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if last := params.Routes[len(params.Routes)-1]; last.To != wavaxAddr {
+		return common.Hash{}, fmt.Errorf("invalid swap route: ends at %s, not WAVAX", last.To.Hex())
+	}
+
+	swapClient, err := b.registry.Client(routerv2)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get swap client %s: %w", routerv2, err)
+	}
+	quote, err := b.QuoteSwap(&types.SWAPExactTokensForTokensParams{AmountIn: params.AmountIn, Routes: params.Routes})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to quote swap: %w", err)
+	}
+	amountOutMin := util.CalculateMinAmount(quote, slippagePct)
+
+	if err := b.approveSwapInput(swapClient, params.Routes[0].From, params.AmountIn); err != nil {
+		return common.Hash{}, err
+	}
+
+	deadline := params.Deadline
+	if deadline == nil {
+		deadline = b.txDeadline(txDeadlineOffset)
+	}
+	if b.dryRun {
+		_, err := b.simulate(swapClient, "swapExactTokensForETH", params.AmountIn, amountOutMin, params.Routes, params.To, deadline)
+		return common.Hash{}, err
+	}
+
+	swapTxHash, err := swapClient.SendCtx(
+		b.rpcContext(),
 		types.Standard,
 		&b.myAddr,
 		b.privateKey,
-		"swapExactTokensForTokens",
+		"swapExactTokensForETH",
 		params.AmountIn,
-		params.AmountOutMin,
+		amountOutMin,
 		params.Routes,
 		params.To,
-		params.Deadline,
+		deadline,
 	)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to execute swap: %w", err)
 	}
-
 	return swapTxHash, nil
 }
 
+// approvalPolicy configures how ensureApproval raises an insufficient allowance
+type approvalPolicy struct {
+	strict   bool // Reset a non-zero allowance to zero before approving (see WithStrictApproval)
+	infinite bool // Approve the maximum uint256 instead of the required amount (see WithInfiniteApproval)
+}
+
 // ensureApproval ensures token approval exists, optimizing to reuse existing allowances
+// With WithInfiniteApproval the maximum allowance is approved once and later calls send nothing
+// With WithStrictApproval, a non-zero allowance is first reset to zero and the reset is awaited
 // Returns transaction hash (zero if approval not needed), or error
 func (b *Blackhole) ensureApproval(
 	tokenClient ContractClient,
@@ -73,7 +339,7 @@ func (b *Blackhole) ensureApproval(
 	requiredAmount *big.Int,
 ) (common.Hash, error) {
 	// Check existing allowance
-	result, err := tokenClient.Call(&b.myAddr, "allowance", b.myAddr, spender)
+	result, err := tokenClient.CallCtx(b.rpcContext(), &b.myAddr, "allowance", b.myAddr, spender)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to check allowance: %w", err)
 	}
@@ -86,14 +352,29 @@ func (b *Blackhole) ensureApproval(
 		return common.Hash{}, nil
 	}
 
-	// Approve required amount
-	txHash, err := tokenClient.Send(
+	if b.approvals.strict && currentAllowance.Sign() > 0 {
+		resetTxHash, err := tokenClient.SendCtx(b.rpcContext(), types.Standard, &b.myAddr, b.privateKey, "approve", spender, big.NewInt(0))
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to reset allowance: %w", err)
+		}
+		if _, err := b.tl.WaitForTransaction(resetTxHash); err != nil {
+			return common.Hash{}, fmt.Errorf("failed to reset allowance: %w", err)
+		}
+	}
+
+	// Approve required amount, or the maximum so later checks pass without another approval
+	approveAmount := requiredAmount
+	if b.approvals.infinite {
+		approveAmount = abi.MaxUint256
+	}
+	txHash, err := tokenClient.SendCtx(
+		b.rpcContext(),
 		types.Standard,
 		&b.myAddr,
 		b.privateKey,
 		"approve",
 		spender,
-		requiredAmount,
+		approveAmount,
 	)
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("failed to approve tokens: %w", err)
@@ -101,3 +382,85 @@ func (b *Blackhole) ensureApproval(
 
 	return txHash, nil
 }
+
+// RemoveLiquidity burns v2 pair LP tokens through the router and returns the underlying tokens
+// It checks the LP balance, approves the router to spend the LP token at pairAddress,
+// then executes removeLiquidity and waits for confirmation
+// A nil params.Deadline defaults to txDeadlineOffset from now
+func (b *Blackhole) RemoveLiquidity(
+	params *types.RemoveLiquidityParams,
+	pairAddress common.Address,
+) (common.Hash, error) {
+	if params == nil || params.Liquidity == nil || params.Liquidity.Sign() <= 0 {
+		return common.Hash{}, errors.New("liquidity must be positive")
+	}
+	if err := b.rejectDryRun("RemoveLiquidity"); err != nil {
+		return common.Hash{}, err
+	}
+
+	routerClient, err := b.registry.Client(routerv2)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get router client %s: %w", routerv2, err)
+	}
+
+	pairClient, err := b.registry.ClientByAddress(pairAddress.Hex())
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get LP token client for pair %s: %w", pairAddress.Hex(), err)
+	}
+
+	// Step 1: Verify the wallet holds enough LP tokens
+	balanceResult, err := pairClient.CallCtx(b.rpcContext(), &b.myAddr, "balanceOf", b.myAddr)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to get LP balance: %w", err)
+	}
+	lpBalance := balanceResult[0].(*big.Int)
+	if params.Liquidity.Cmp(lpBalance) > 0 {
+		return common.Hash{}, fmt.Errorf("insufficient LP balance for pair %s: have %s, need %s",
+			pairAddress.Hex(), lpBalance.String(), params.Liquidity.String())
+	}
+
+	// Step 2: Approve the router to spend the LP tokens
+	approveTxHash, err := b.ensureApproval(pairClient, *routerClient.ContractAddress(), params.Liquidity)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to approve LP tokens: %w", err)
+	}
+
+	if approveTxHash != (common.Hash{}) {
+		_, err = b.tl.WaitForTransaction(approveTxHash)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("LP token approval transaction failed: %w", err)
+		}
+	}
+
+	deadline := params.Deadline
+	if deadline == nil {
+		deadline = b.txDeadline(txDeadlineOffset)
+	}
+
+	// Step 3: Remove liquidity
+	removeTxHash, err := routerClient.SendCtx(
+		b.rpcContext(),
+		types.Standard,
+		&b.myAddr,
+		b.privateKey,
+		"removeLiquidity",
+		params.TokenA,
+		params.TokenB,
+		params.Stable,
+		params.Liquidity,
+		params.AmountAMin,
+		params.AmountBMin,
+		params.To,
+		deadline,
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to execute removeLiquidity: %w", err)
+	}
+
+	_, err = b.tl.WaitForTransaction(removeTxHash)
+	if err != nil {
+		return removeTxHash, fmt.Errorf("removeLiquidity transaction failed: %w", err)
+	}
+
+	return removeTxHash, nil
+}
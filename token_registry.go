@@ -0,0 +1,72 @@
+package blackholedex
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// TokenInfo describes how a token's amounts are displayed
+type TokenInfo struct {
+	Symbol   string
+	Decimals int
+}
+
+// Format renders amount in smallest units as whole tokens with the symbol, e.g. "3.7508 WAVAX"
+func (t TokenInfo) Format(amount *big.Int) string {
+	return util.FormatTokenAmount(amount, t.Decimals) + " " + t.Symbol
+}
+
+// TokenRegistry maps token addresses to their symbol and decimals for human-readable output
+// A nil registry knows no tokens. Safe for concurrent use
+type TokenRegistry struct {
+	mu     sync.RWMutex
+	tokens map[common.Address]TokenInfo
+}
+
+// NewTokenRegistry creates a registry from a token map
+// The map is copied, so later changes to it do not affect the registry
+func NewTokenRegistry(tokens map[common.Address]TokenInfo) *TokenRegistry {
+	copied := make(map[common.Address]TokenInfo, len(tokens))
+	for addr, info := range tokens {
+		copied[addr] = info
+	}
+	return &TokenRegistry{tokens: copied}
+}
+
+// Register adds or replaces the token at addr
+func (r *TokenRegistry) Register(addr common.Address, info TokenInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.tokens[addr] = info
+}
+
+// Lookup returns the token at addr, if registered
+func (r *TokenRegistry) Lookup(addr common.Address) (TokenInfo, bool) {
+	if r == nil {
+		return TokenInfo{}, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	info, ok := r.tokens[addr]
+	return info, ok
+}
+
+// formatAmount renders amount of the named token contract for output, e.g. "3.7508 WAVAX"
+// Tokens without a symbol and decimals in the token registry are shown in smallest units
+func (b *Blackhole) formatAmount(contract string, amount *big.Int) string {
+	if addr, err := b.registry.GetAddress(contract); err == nil {
+		if info, ok := b.tokens.Lookup(addr); ok {
+			return info.Format(amount)
+		}
+	}
+	return fmt.Sprintf("%s %s (smallest units)", amount, contract)
+}
+
+// formatAVAX renders a native AVAX amount in wei, such as a gas cost, e.g. "0.0042 AVAX"
+func formatAVAX(wei *big.Int) string {
+	return util.FormatTokenAmount(wei, 18) + " AVAX"
+}
@@ -0,0 +1,28 @@
+package blackholedex
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatAmount(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7")
+	usdcAddr := common.HexToAddress("0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E")
+	b := newTestBlackhole(map[string]ContractClient{
+		wavax: newMockContractClient(wavaxAddr),
+		usdc:  newMockContractClient(usdcAddr),
+	}, &mockTxListener{})
+	amount, _ := new(big.Int).SetString("3750793819555087051", 10)
+
+	// Without registered tokens amounts stay in smallest units
+	assert.Equal(t, "3750793819555087051 wavax (smallest units)", b.formatAmount(wavax, amount))
+
+	b.tokens = NewTokenRegistry(map[common.Address]TokenInfo{wavaxAddr: {Symbol: "WAVAX", Decimals: 18}})
+	b.tokens.Register(usdcAddr, TokenInfo{Symbol: "USDC", Decimals: 6})
+	assert.Equal(t, "3.7508 WAVAX", b.formatAmount(wavax, amount))
+	assert.Equal(t, "1.5 USDC", b.formatAmount(usdc, big.NewInt(1_500_000)))
+	assert.Equal(t, "0.0042 AVAX", formatAVAX(big.NewInt(4_200_000_000_000_000)))
+}
@@ -0,0 +1,351 @@
+package blackholedex
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ChoSanghyuk/blackholedex/pkg/types"
+	"github.com/ChoSanghyuk/blackholedex/pkg/util"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveLiquidity(t *testing.T) {
+	pairAddr := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	routerAddr := common.HexToAddress("0x00000000000000000000000000000000000000c2")
+
+	newClients := func(lpBalance *big.Int) (*mockContractClient, *mockContractClient) {
+		pair := newMockContractClient(pairAddr)
+		pair.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			switch method {
+			case "balanceOf":
+				return []interface{}{lpBalance}, nil
+			case "allowance":
+				return []interface{}{big.NewInt(0)}, nil
+			}
+			return nil, errors.New("unexpected method " + method)
+		}
+		return pair, newMockContractClient(routerAddr)
+	}
+
+	params := &types.RemoveLiquidityParams{
+		Liquidity:  big.NewInt(500),
+		AmountAMin: big.NewInt(0),
+		AmountBMin: big.NewInt(0),
+		Deadline:   big.NewInt(0),
+	}
+
+	t.Run("ApproveThenRemove", func(t *testing.T) {
+		pair, router := newClients(big.NewInt(1000))
+		tl := &mockTxListener{}
+		b := newTestBlackhole(map[string]ContractClient{routerv2: router, "lp": pair}, tl)
+
+		txHash, err := b.RemoveLiquidity(params, pairAddr)
+		assert.NoError(t, err)
+		assert.NotEqual(t, common.Hash{}, txHash)
+
+		assert.Equal(t, []string{"approve"}, pair.sentMethods())
+		assert.Equal(t, routerAddr, pair.sent[0].Args[0])
+		assert.Equal(t, []string{"removeLiquidity"}, router.sentMethods())
+		// Both the approval and the removal are awaited
+		assert.Len(t, tl.waited, 2)
+		assert.Equal(t, txHash, tl.waited[1])
+	})
+
+	t.Run("InsufficientLPBalance", func(t *testing.T) {
+		pair, router := newClients(big.NewInt(100))
+		b := newTestBlackhole(map[string]ContractClient{routerv2: router, "lp": pair}, &mockTxListener{})
+
+		_, err := b.RemoveLiquidity(params, pairAddr)
+		assert.ErrorContains(t, err, "insufficient LP balance")
+		assert.Empty(t, pair.sentMethods())
+		assert.Empty(t, router.sentMethods())
+	})
+}
+
+func TestFindRouteNoPool(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	blackAddr := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	pairAddr := common.HexToAddress("0x00000000000000000000000000000000000000d1")
+
+	t.Run("ConfiguredPair", func(t *testing.T) {
+		b := newTestBlackhole(map[string]ContractClient{
+			wavax:         newMockContractClient(wavaxAddr),
+			usdc:          newMockContractClient(usdcAddr),
+			wavaxUsdcPair: newMockContractClient(pairAddr),
+		}, &mockTxListener{})
+
+		route, err := b.findRoute(usdcAddr, wavaxAddr)
+		assert.NoError(t, err)
+		assert.Equal(t, pairAddr, route.Pair)
+
+		_, err = b.findRoute(blackAddr, usdcAddr)
+		var noRoute *ErrNoRoute
+		if assert.ErrorAs(t, err, &noRoute) {
+			assert.Equal(t, blackAddr, noRoute.From)
+			assert.Equal(t, usdcAddr, noRoute.To)
+		}
+	})
+
+	t.Run("FactoryWithoutPool", func(t *testing.T) {
+		factory := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000f2"))
+		factory.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			return []interface{}{common.Address{}}, nil
+		}
+		b := newTestBlackhole(map[string]ContractClient{algebraFactory: factory}, &mockTxListener{})
+
+		_, err := b.findRoute(blackAddr, wavaxAddr)
+		var noRoute *ErrNoRoute
+		if assert.ErrorAs(t, err, &noRoute) {
+			assert.Equal(t, blackAddr, noRoute.From)
+			assert.Equal(t, wavaxAddr, noRoute.To)
+		}
+	})
+}
+
+func TestSwapWithSlippage(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	pairAddr := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+	token := newMockContractClient(wavaxAddr)
+	token.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "allowance" {
+			return []interface{}{big.NewInt(1_000_000)}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	router := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000c2"))
+	router.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "getPoolAmountOut" && args[1] == wavaxAddr && args[2] == pairAddr {
+			return []interface{}{big.NewInt(2_500)}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	b := newTestBlackhole(map[string]ContractClient{
+		routerv2: router,
+		wavax:    token,
+		usdc:     newMockContractClient(usdcAddr),
+	}, &mockTxListener{})
+
+	params := &types.SWAPExactTokensForTokensParams{
+		AmountIn: big.NewInt(100),
+		Routes:   []types.Route{{Pair: pairAddr, From: wavaxAddr, To: usdcAddr, Concentrated: true}},
+		To:       b.myAddr,
+		Deadline: big.NewInt(0),
+	}
+
+	quote, err := b.QuoteSwap(params)
+	assert.NoError(t, err)
+	assert.Equal(t, big.NewInt(2_500), quote)
+
+	_, err = b.SwapWithSlippage(params, 2)
+	assert.NoError(t, err)
+	if assert.Len(t, router.sent, 1) {
+		assert.Equal(t, "swapExactTokensForTokens", router.sent[0].Method)
+		assert.Equal(t, big.NewInt(2_450), router.sent[0].Args[1])
+	}
+	assert.Nil(t, params.AmountOutMin, "params must be left unchanged")
+
+	_, err = b.SwapWithSlippage(params, 100)
+	assert.Error(t, err)
+}
+
+func TestSwapRejectsBrokenRoute(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	blackAddr := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	pairAddr := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+	token := newMockContractClient(wavaxAddr)
+	router := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000c2"))
+	tl := &mockTxListener{}
+	b := newTestBlackhole(map[string]ContractClient{
+		routerv2: router,
+		wavax:    token,
+		usdc:     newMockContractClient(usdcAddr),
+	}, tl)
+
+	tests := []struct {
+		name   string
+		routes []types.Route
+		errMsg string
+	}{
+		{"NoRoutes", nil, "no routes"},
+		{"ZeroPair", []types.Route{{From: wavaxAddr, To: usdcAddr}}, "zero address"},
+		{"SameToken", []types.Route{{Pair: pairAddr, From: wavaxAddr, To: wavaxAddr}}, "to itself"},
+		{"GapInChain", []types.Route{
+			{Pair: pairAddr, From: wavaxAddr, To: usdcAddr},
+			{Pair: pairAddr, From: blackAddr, To: wavaxAddr},
+		}, "route 1 starts at"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := b.Swap(&types.SWAPExactTokensForTokensParams{
+				AmountIn: big.NewInt(100),
+				Routes:   tt.routes,
+				To:       b.myAddr,
+			})
+			assert.ErrorContains(t, err, tt.errMsg)
+		})
+	}
+
+	assert.Empty(t, token.sentMethods(), "no approval may be sent for an invalid route")
+	assert.Empty(t, router.sentMethods())
+	assert.Empty(t, tl.waited)
+}
+
+func TestEnsureApprovalStrict(t *testing.T) {
+	routerAddr := common.HexToAddress("0x00000000000000000000000000000000000000c2")
+	newToken := func() *mockContractClient {
+		token := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000a1"))
+		token.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+			if method == "allowance" {
+				return []interface{}{big.NewInt(50)}, nil // Non-zero but short of the 100 required
+			}
+			return nil, errors.New("unexpected method " + method)
+		}
+		return token
+	}
+
+	t.Run("ResetsThenApproves", func(t *testing.T) {
+		token := newToken()
+		tl := &mockTxListener{}
+		b := newTestBlackhole(nil, tl)
+		WithStrictApproval()(b)
+
+		txHash, err := b.ensureApproval(token, routerAddr, big.NewInt(100))
+		assert.NoError(t, err)
+		if assert.Equal(t, []string{"approve", "approve"}, token.sentMethods()) {
+			assert.Equal(t, []interface{}{routerAddr, big.NewInt(0)}, token.sent[0].Args)
+			assert.Equal(t, []interface{}{routerAddr, big.NewInt(100)}, token.sent[1].Args)
+		}
+		// The reset is confirmed before the new amount is approved
+		assert.Equal(t, []common.Hash{common.BigToHash(big.NewInt(1))}, tl.waited)
+		assert.Equal(t, common.BigToHash(big.NewInt(2)), txHash)
+	})
+
+	t.Run("DefaultApprovesDirectly", func(t *testing.T) {
+		token := newToken()
+		tl := &mockTxListener{}
+		b := newTestBlackhole(nil, tl)
+
+		_, err := b.ensureApproval(token, routerAddr, big.NewInt(100))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"approve"}, token.sentMethods())
+		assert.Empty(t, tl.waited)
+	})
+}
+
+func TestEstimatePriceImpact(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	pairAddr := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+	// At sqrtPrice Q96 one WAVAX wei is worth one USDC unit; the quote loses amount/1e8 of it,
+	// so swapping 1e6 has 1% impact
+	router := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000c2"))
+	router.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method != "getPoolAmountOut" {
+			return nil, errors.New("unexpected method " + method)
+		}
+		amount := args[0].(*big.Int)
+		loss := new(big.Int).Div(new(big.Int).Mul(amount, amount), big.NewInt(100_000_000))
+		return []interface{}{new(big.Int).Sub(amount, loss)}, nil
+	}
+	b := newTestBlackhole(map[string]ContractClient{
+		routerv2:      router,
+		wavax:         newMockContractClient(wavaxAddr),
+		usdc:          newMockContractClient(usdcAddr),
+		wavaxUsdcPair: newMockPool(util.Q96, 0),
+	}, &mockTxListener{})
+	swap := func(amountIn int64) *types.SWAPExactTokensForTokensParams {
+		return &types.SWAPExactTokensForTokensParams{
+			AmountIn:     big.NewInt(amountIn),
+			AmountOutMin: big.NewInt(amountIn / 2),
+			Routes:       []types.Route{{Pair: pairAddr, From: wavaxAddr, To: usdcAddr, Concentrated: true}},
+		}
+	}
+
+	impact, err := b.EstimatePriceImpact(swap(1_000_000))
+	assert.NoError(t, err)
+	assert.InDelta(t, 1.0, impact, 1e-9)
+
+	_, err = b.EstimatePriceImpact(&types.SWAPExactTokensForTokensParams{
+		AmountIn: big.NewInt(1_000_000),
+		Routes:   []types.Route{{Pair: pairAddr, From: usdcAddr, To: common.HexToAddress("0xbb")}},
+	})
+	assert.ErrorContains(t, err, "only estimated for WAVAX/USDC")
+
+	t.Run("Shrink", func(t *testing.T) {
+		// 4% at the full amount, 1% after halving twice
+		limited, err := b.limitPriceImpact(swap(4_000_000), 1)
+		if assert.NoError(t, err) && assert.NotNil(t, limited) {
+			assert.Equal(t, big.NewInt(1_000_000), limited.AmountIn)
+			assert.Equal(t, big.NewInt(500_000), limited.AmountOutMin)
+		}
+	})
+
+	t.Run("Skip", func(t *testing.T) {
+		// Still 0.5% at an eighth of the amount
+		limited, err := b.limitPriceImpact(swap(4_000_000), 0.1)
+		assert.NoError(t, err)
+		assert.Nil(t, limited)
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		params := swap(4_000_000)
+		limited, err := b.limitPriceImpact(params, 0)
+		assert.NoError(t, err)
+		assert.Same(t, params, limited)
+	})
+}
+
+func TestSwapTokensForETH(t *testing.T) {
+	wavaxAddr := common.HexToAddress("0x00000000000000000000000000000000000000a1")
+	usdcAddr := common.HexToAddress("0x00000000000000000000000000000000000000a2")
+	pairAddr := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+
+	token := newMockContractClient(usdcAddr)
+	token.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "allowance" {
+			return []interface{}{big.NewInt(0)}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	router := newMockContractClient(common.HexToAddress("0x00000000000000000000000000000000000000c2"))
+	router.callFn = func(method string, args ...interface{}) ([]interface{}, error) {
+		if method == "getPoolAmountOut" && args[1] == usdcAddr && args[2] == pairAddr {
+			return []interface{}{big.NewInt(40_000)}, nil
+		}
+		return nil, errors.New("unexpected method " + method)
+	}
+	b := newTestBlackhole(map[string]ContractClient{
+		routerv2: router,
+		wavax:    newMockContractClient(wavaxAddr),
+		usdc:     token,
+	}, &mockTxListener{})
+
+	params := &types.SWAPExactTokensForETHParams{
+		AmountIn: big.NewInt(1_000),
+		Routes:   []types.Route{{Pair: pairAddr, From: usdcAddr, To: wavaxAddr, Concentrated: true}},
+		To:       b.myAddr,
+		Deadline: big.NewInt(0),
+	}
+	_, err := b.SwapTokensForETH(params, 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"approve"}, token.sentMethods())
+	if assert.Equal(t, []string{"swapExactTokensForETH"}, router.sentMethods()) {
+		assert.Equal(t, big.NewInt(38_000), router.sent[0].Args[1])
+	}
+	assert.Nil(t, params.AmountOutMin, "params must be left unchanged")
+
+	// The router can only unwrap WAVAX
+	otherAddr := common.HexToAddress("0x00000000000000000000000000000000000000a3")
+	params.Routes = []types.Route{{Pair: pairAddr, From: usdcAddr, To: otherAddr}}
+	_, err = b.SwapTokensForETH(params, 5)
+	assert.ErrorContains(t, err, "not WAVAX")
+	assert.Len(t, router.sent, 1)
+}
@@ -93,18 +93,66 @@ func TestPacking(t *testing.T) {
 		assert.Equal(t, common.Bytes2Hex(packed), swapExactTokensForTokensTxData)
 	})
 
+	t.Run("SWAPExactTokensForETHParams", func(t *testing.T) {
+
+		// 1 USDC -> WAVAX through the CL200 pool, unwrapped to AVAX, encoded word by word
+		swapExactTokensForETHTxData := "f0aff68d" + // swapExactTokensForETH(uint256,uint256,(address,address,address,bool,bool,address)[],address,uint256)
+			"00000000000000000000000000000000000000000000000000000000000f4240" + // amountIn: 1000000
+			"0000000000000000000000000000000000000000000000000058d15e17628000" + // amountOutMin: 0.025 AVAX
+			"00000000000000000000000000000000000000000000000000000000000000a0" + // routes offset
+			"000000000000000000000000b4dd4fb3d4bced984cce972991fb100488b59223" + // to
+			"000000000000000000000000000000000000000000000000000000006927fa81" + // deadline
+			"0000000000000000000000000000000000000000000000000000000000000001" + // routes length
+			"00000000000000000000000041100c6d2c6920b10d12cd8d59c8a9aa2ef56fc7" + // pair
+			"000000000000000000000000b97ef9ef8734c71904d8002f8b6bc66dd9c48a6e" + // from: USDC
+			"000000000000000000000000b31f66aa3c1e785363f0875a1b74e27b85fd66c7" + // to: WAVAX
+			"0000000000000000000000000000000000000000000000000000000000000000" + // stable
+			"0000000000000000000000000000000000000000000000000000000000000001" + // concentrated
+			"00000000000000000000000004e1dee021cd12bba022a72806441b43d8212fec" //   receiver: router, which unwraps
+
+		params := types.SWAPExactTokensForETHParams{
+			AmountIn:     big.NewInt(1_000_000),
+			AmountOutMin: big.NewInt(25_000_000_000_000_000),
+			Routes: []types.Route{
+				{
+					Pair:         common.HexToAddress("0x41100c6d2c6920b10d12cd8d59c8a9aa2ef56fc7"),
+					From:         common.HexToAddress("0xB97EF9Ef8734C71904D8002F8b6Bc66Dd9c48a6E"),
+					To:           common.HexToAddress("0xB31f66AA3C1e785363F0875A1B74E27b85FD66c7"),
+					Stable:       false,
+					Concentrated: true,
+					Receiver:     common.HexToAddress("0x04E1dee021Cd12bBa022A72806441B43d8212Fec"),
+				},
+			},
+			To:       common.HexToAddress("0xb4dd4fb3D4bCED984cce972991fB100488b59223"),
+			Deadline: big.NewInt(1764227713),
+		}
+
+		routerABI, err := util.LoadABI("blackholedex-contracts/abi/RouterV2.json")
+		if err != nil {
+			t.Fatalf("Could not load RouterV2 ABI: %v", err)
+		}
+		packed, err := routerABI.Pack("swapExactTokensForETH", params.AmountIn, params.AmountOutMin, params.Routes, params.To, params.Deadline)
+		if err != nil {
+			t.Fatalf("Failed to pack: %v", err)
+		}
+
+		assert.Equal(t, swapExactTokensForETHTxData, common.Bytes2Hex(packed))
+	})
+
 	t.Run("MintParams", func(t *testing.T) {
 
 		// txHash 0x9e2247a0210448cab301475eef741eba0ee9a9351188a92b8127fce27206b9d0의 txData 값.
 		// txData (0x 없이)
-		swapExactTokensForTokensTxData := "fe3f3be7000000000000000000000000b31f66aa3c1e785363f0875a1b74e27b85fd66c7000000000000000000000000b97ef9ef8734c71904d8002f8b6bc66dd9c48a6e0000000000000000000000005d433a94a4a2aa8f9aa34d8d15692dc2e9960584fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffc3100fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffc35b0000000000000000000000000000000000000000000000000340d7f1b384fc2cb0000000000000000000000000000000000000000000000000000000003a8a540000000000000000000000000000000000000000000000000317338c0424bc5da000000000000000000000000000000000000000000000000000000000379d030000000000000000000000000b4dd4fb3d4bced984cce972991fb100488b592230000000000000000000000000000000000000000000000000000019a9267bb33"
+		swapExactTokensForTokensTxData := "fe3f3be7000000000000000000000000b31f66aa3c1e785363f0875a1b74e27b85fd66c7000000000000000000000000b97ef9ef8734c71904d8002f8b6bc66dd9c48a6e0000000000000000000000005d433a94a4a2aa8f9aa34d8d15692dc2e9960584fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffc3100fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffc35b0000000000000000000000000000000000000000000000000340d7f1b384fc2cb0000000000000000000000000000000000000000000000000000000003a8a540000000000000000000000000000000000000000000000000317338c0424bc5da000000000000000000000000000000000000000000000000000000000379d030000000000000000000000000b4dd4fb3d4bced984cce972991fb100488b5922300000000000000000000000000000000000000000000000000000000691b3d5f"
 
 		// 로컬 Parameter로 동일한 데이터 packing
 		amount0Desired, _ := big.NewInt(0).SetString("3750793819555087051", 10)
 		amount1Desired := big.NewInt(61384000)
 		amount0Min, _ := big.NewInt(0).SetString("3563254128577332698", 10)
 		amount1Min := big.NewInt(58314800)
-		deadline, _ := big.NewInt(0).SetString("1763392863027", 10)
+		// Deadlines are Unix seconds, compared against block.timestamp
+		// (this transaction was originally sent with a millisecond deadline, which never expires)
+		deadline := big.NewInt(1763392863)
 
 		params := types.MintParams{
 			Token0:         common.HexToAddress("0xb31f66aa3c1e785363f0875a1b74e27b85fd66c7"),